@@ -8,22 +8,31 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/aydndglr/rick-agent-v3/internal/agent"
-	"github.com/aydndglr/rick-agent-v3/internal/brain/providers"
 	"github.com/aydndglr/rick-agent-v3/internal/communication/whatsapp"
 	"github.com/aydndglr/rick-agent-v3/internal/core/config"
 	"github.com/aydndglr/rick-agent-v3/internal/core/kernel" // 🚀 YENİ: Brain interface'i için eklendi
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
-	"github.com/aydndglr/rick-agent-v3/internal/memory"
 	"github.com/aydndglr/rick-agent-v3/internal/skills"
 	"github.com/aydndglr/rick-agent-v3/internal/skills/coding"
-	"github.com/aydndglr/rick-agent-v3/internal/skills/filesystem"
-	"github.com/aydndglr/rick-agent-v3/internal/skills/network"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/mcp"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/middleware"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/recall"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
 	"github.com/aydndglr/rick-agent-v3/internal/skills/system"
 )
 
 func main() {
+	// Sandbox yardımcısı olarak başlatıldıysak sınırları kurup Python aracına dönüşür (Geri dönmez)
+	sandbox.MaybeRunHelper()
+
+	// 0. YARDIMCI KOMUTLAR (bench vb.) - Ajanı başlatmadan çalışır
+	if code, ok := runSubcommand(os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
 	// 1. YAPILANDIRMA YÜKLE
 	cfg, err := config.Load("config/config.yaml")
 	if err != nil {
@@ -36,50 +45,31 @@ func main() {
 	logger.Info("🚀 Rick C-137 Uyandırılıyor ")
 	logger.Info("🔒 Güvenlik Seviyesi: %s", cfg.Security.Level)
 
-	// 3. BEYİN BAĞLANTISI (DİNAMİK SAĞLAYICI)
-	var brain kernel.Brain
-
-	switch cfg.Brain.Primary.Provider {
-	case "gemini":
-		if cfg.Brain.APIKeys.Gemini == "" {
-			logger.Error("💥 Gemini API anahtarı eksik! config.yaml dosyasını kontrol et.")
-			os.Exit(1)
-		}
-		brain = providers.NewGemini(
-			cfg.Brain.Primary.BaseURL,
-			cfg.Brain.APIKeys.Gemini,
-			cfg.Brain.Primary.ModelName,
-		)
-		logger.Success("🧠 Ana Beyin: Google Gemini (%s)", cfg.Brain.Primary.ModelName)
-
-	case "openai":
-		if cfg.Brain.APIKeys.OpenAI == "" {
-			logger.Error("💥 OpenAI API anahtarı eksik! config.yaml dosyasını kontrol et.")
-			os.Exit(1)
-		}
-		brain = providers.NewOpenAI(
-			cfg.Brain.Primary.BaseURL,
-			cfg.Brain.APIKeys.OpenAI,
-			cfg.Brain.Primary.ModelName,
-		)
-		logger.Success("🧠 Ana Beyin: OpenAI (%s)", cfg.Brain.Primary.ModelName)
-
-	case "ollama":
-		brain = providers.NewOllama(
-			cfg.Brain.Primary.BaseURL,
-			cfg.Brain.Primary.ModelName,
-			cfg.Brain.Primary.Temperature,
-			cfg.Brain.Primary.NumCtx,
-		)
-		logger.Success("🧠 Ana Beyin: Local Ollama (%s)", cfg.Brain.Primary.ModelName)
-
-	default:
-		logger.Error("💥 Bilinmeyen sağlayıcı: %s. (Desteklenenler: gemini, openai, ollama)", cfg.Brain.Primary.Provider)
+	// 3. BEYİN BAĞLANTISI (DİNAMİK SAĞLAYICI + KASET)
+	brain, ollama, err := newBrain(cfg)
+	if err != nil {
+		logger.Error("💥 %v", err)
 		os.Exit(1)
 	}
 
 	// 4. HAFIZA (VECTOR STORE) BAŞLAT
-	memStore := memory.NewVectorStore("rick_memory.json", brain)
+	memStore, err := openMemory(cfg, brain)
+	if err != nil {
+		logger.Error("💥 %v", err)
+		os.Exit(1)
+	}
+	defer memStore.Close()
+	factStore, err := openFacts(cfg)
+	if err != nil {
+		logger.Error("💥 %v", err)
+		os.Exit(1)
+	}
+	defer factStore.Close()
+	consolidation := cfg.Memory.Consolidation
+	consolidateOpts := consolidateOptions(cfg)
+	if !cfg.Memory.Index.Disabled {
+		memStore.EnableIndex(cfg.Memory.Index.MinDocs)
+	}
 
 	// 4.5. VENV KURULUMU (Sanal Python Ortamı)
 	env, err := skills.SetupVenv("tools")
@@ -91,47 +81,114 @@ func main() {
 	// 5 YETENEK YÖNETİCİSİ (Skill Manager)
 	skillMgr := skills.NewManager()
 
+	// Python araç yükleyicisi: Kodlama araçlarının yaptığı değişiklikleri çalışan yöneticiye anında yansıtır
+	loader := skills.NewLoader(skillMgr, "tools", env.PythonPath)
+	loader.Sandbox = sandboxLimits(cfg)
+	if loader.Sandbox == nil {
+		logger.Warn("⚠️ Python araçları sandbox'sız çalışacak (tools.sandbox.disabled)")
+	}
+
 	// 5.1 "YARATICI"YI EKLE (The Creator)
 	creator := coding.NewDevStudio("tools", env.PipPath, env.PythonPath)
 	editor := coding.NewEditor("tools", env.PipPath, env.PythonPath)
 	deleter := coding.NewDeleter("tools")
+	history := coding.NewToolHistory("tools")
+	tester := coding.NewToolTester("tools", env.PythonPath)
+	creator.Reloader = loader
+	editor.Reloader = loader
+	deleter.Reloader = loader
+	history.Reloader = loader
+	creator.Sandbox = loader.Sandbox
+	editor.Sandbox = loader.Sandbox
+	tester.Sandbox = loader.Sandbox
 	
 	skillMgr.Register(creator)
 	skillMgr.Register(editor)
 	skillMgr.Register(deleter)
+	skillMgr.Register(history)
+	skillMgr.Register(tester)
 
-	// 5.2 NATIVE (GO) ARAÇLARI YÜKLE
-	skillMgr.Register(&filesystem.ListTool{})
-	skillMgr.Register(&filesystem.ReadTool{})
-	skillMgr.Register(&filesystem.WriteTool{})
-	skillMgr.Register(&filesystem.DeleteTool{})
-	skillMgr.Register(&filesystem.SearchTool{})
-	
-	// 5.3 INTERNET / BROWSER ARACI
-	skillMgr.Register(&network.BrowserTool{})
-	skillMgr.Register(&network.SSHTool{})
+	// 5.2 - 5.5 NATIVE (GO) ARAÇLAR: Dosya sistemi, tarayıcı/SSH, arka plan ve zamanlanmış görevler
+	registerNativeTools(skillMgr)
 
-	// 5.4 YENİ EKLENEN ARKA PLAN ARAÇLARI
-	skillMgr.Register(&system.StartTaskTool{})
-	skillMgr.Register(&system.CheckTaskTool{})
-	skillMgr.Register(&system.KillTaskTool{})
+	// 5.6 UZUN SÜRELİ HAFIZA ARAÇLARI (Hatırla / Ara / Unut / Bakım / Bilgi Tabanı)
+	skillMgr.Register(&recall.SaveTool{Memory: memStore})
+	skillMgr.Register(&recall.SearchTool{Memory: memStore})
+	skillMgr.Register(&recall.ForgetTool{Memory: memStore})
+	skillMgr.Register(&recall.AdminTool{Store: memStore, Consolidate: consolidateOpts, ExportDir: recall.DefaultExportDir})
+	skillMgr.Register(&recall.IngestTool{Store: memStore})
+	skillMgr.Register(&recall.FactsTool{Facts: factStore})
 
-	// 5.5 ZAMANLANMIŞ GÖREVLER
-	skillMgr.Register(&system.ScheduleTaskTool{})
+	// 5.7 MODEL YÖNETİMİ (Sadece Ollama)
+	if ollama != nil {
+		skillMgr.Register(system.NewModelTool(ollama))
+	}
 
-	// DİSKTEKİ (PYTHON) ARAÇLARI YÜKLE
-	loader := skills.NewLoader(skillMgr, "tools", env.PythonPath)
+	// DİSKTEKİ (PYTHON) ARAÇLARI YÜKLE (Yerleşik araçlardan sonra: Aynı adlı Python dosyası onları ezemez)
 	if err := loader.LoadAll(); err != nil {
 		logger.Warn("⚠️ Araçlar yüklenirken uyarı: %v", err)
 	}
 
 	// 6. AJANI OLUŞTUR (Rick)
 	rick := agent.NewRick(cfg, brain, skillMgr, memStore)
+	rick.Facts = factStore
+	defer rick.Usage.Flush() // Kullanım defterinin son değişiklikleri
+	agentPol, err := agentPolicy(cfg)
+	if err != nil {
+		logger.Error("❌ Güvenlik politikası geçersiz: %v", err)
+		os.Exit(1)
+	}
+	auditLog, err := openAudit(cfg, "")
+	if err != nil {
+		logger.Error("❌ Denetim kaydı açılamadı: %v", err)
+		os.Exit(1)
+	}
+	if auditLog != nil {
+		defer auditLog.Close()
+		logger.Info("🧾 Araç çağrıları denetim kaydına yazılıyor: %s", auditLog.Path())
+	}
+	// Onay gerektiren araçlar yöneticiye sorulur; cevap "/approve <no>" veya "/deny <no>" komutuyla verilir
+	approvals := &middleware.Approvals{}
+	rick.Approvals = approvals
+	pipeOpts := pipelineOptions(cfg, auditLog, approvals.Approve)
+	pipeOpts.Interceptors = append([]middleware.Interceptor{&middleware.PolicyInterceptor{Policy: agentPol}}, pipeOpts.Interceptors...)
+	rick.Pipeline = middleware.Default(pipeOpts)
 
 	// 7. CONTEXT & SHUTDOWN HANDLER
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// 7.5 HAFIZA TOPLAMA (Periyodik birleştirme + süre aşımı arşivi)
+	if consolidation.Enabled {
+		interval := time.Duration(consolidation.IntervalHours * float64(time.Hour))
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		memStore.StartConsolidation(ctx, interval, consolidateOpts)
+		logger.Info("🧹 Hafıza toparlama her %v çalışacak", interval)
+	}
+
+	// 7.6 ARAÇ KLASÖRÜ İZLEYİCİSİ (Elle eklenen/düzenlenen/silinen Python araçları canlı yüklenir)
+	if watch := cfg.Tools.WatchIntervalSeconds; watch >= 0 {
+		if watch == 0 {
+			watch = 3
+		}
+		loader.Watch(ctx, time.Duration(watch)*time.Second)
+	}
+
+	// 7.7 MCP SUNUCULARI (Uzak araçlar "<önek>__<araç>" adıyla yüklenir; düşen sunucular yeniden başlatılır)
+	var mcpHub *mcp.Hub
+	if servers := mcpServers(cfg); len(servers) > 0 {
+		mcpHub, err = mcp.NewHub(skillMgr, servers)
+		if err != nil {
+			logger.Error("💥 MCP yapılandırması geçersiz: %v", err)
+			os.Exit(1)
+		}
+		mcpHub.Start(ctx)
+		defer mcpHub.Close()
+		logger.Info("🔌 %d MCP sunucusuna bağlanılıyor...", len(servers))
+	}
+
 	// 8. WHATSAPP LISTENER
 	if cfg.Communication.Whatsapp.Enabled {
 		wa := whatsapp.New(
@@ -139,6 +196,11 @@ func main() {
 			cfg.Communication.Whatsapp.AdminPhone,
 			cfg.Communication.Whatsapp.DatabasePath,
 		)
+		// Onay istekleri terminale ve yöneticinin WhatsApp'ına gider
+		approvals.Notify = func(text string) {
+			logger.Info("%s", text)
+			wa.NotifyAdmin(text)
+		}
 		
 		go func() {
 			logger.Info("👂 Portal Açılıyor...")
@@ -156,6 +218,11 @@ func main() {
 		<-sigChan
 		logger.Info("\n🛑 Sistem kapatılıyor...")
 		cancel()
+		if mcpHub != nil {
+			mcpHub.Close() // Sunucu süreçlerini kapat
+		}
+		memStore.Close() // WAL'ı temiz kapat
+		rick.Usage.Flush()
 		logger.Close()
 		os.Exit(0)
 	}()
@@ -164,12 +231,32 @@ func main() {
 	fmt.Println("🤖 RICK AGENT V4 - ONLINE")
 	fmt.Println(strings.Repeat("=", 50))
 
-	scanner := bufio.NewScanner(os.Stdin)
+	// Görev arka planda çalışırken de "/" komutları (Örn: "/approve 1") okunabilsin diye girdi ayrı okunur
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	var running chan struct{} // Süren terminal görevi (Yoksa nil)
+
 	for {
-		if !scanner.Scan() {
-			break
+		var input string
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if running != nil {
+					<-running // Girdi bitti ama süren görev yarıda kesilmesin
+				}
+				return
+			}
+			input = line
+		case <-running:
+			running = nil
+			continue
 		}
-		input := scanner.Text()
 
 		if input == "exit" || input == "quit" {
 			break
@@ -178,8 +265,25 @@ func main() {
 			continue
 		}
 
-		if _, err := rick.Run(ctx, input, nil); err != nil {
-			logger.Error("💥 Döngü Hatası: %v", err)
+		cliCtx := kernel.WithConversation(ctx, kernel.DefaultConversation)
+
+		// "/model list" gibi yönetici komutları LLM'e gitmeden çalışır
+		if out, ok := rick.HandleCommand(kernel.WithActor(cliCtx, kernel.ActorAdmin), input); ok {
+			fmt.Println(out)
+			continue
+		}
+
+		if running != nil {
+			fmt.Println("⏳ Önceki görev sürüyor. Bitmesini bekle (Onay istekleri için /approve <no> veya /deny <no>).")
+			continue
 		}
+		done := make(chan struct{})
+		running = done
+		go func() {
+			defer close(done)
+			if _, err := rick.Run(cliCtx, input, nil); err != nil {
+				logger.Error("💥 Döngü Hatası: %v", err)
+			}
+		}()
 	}
 }
\ No newline at end of file
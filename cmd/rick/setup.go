@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/audit"
+	"github.com/aydndglr/rick-agent-v3/internal/brain/providers"
+	"github.com/aydndglr/rick-agent-v3/internal/core/config"
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+	"github.com/aydndglr/rick-agent-v3/internal/memory"
+	"github.com/aydndglr/rick-agent-v3/internal/skills"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/filesystem"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/mcp"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/middleware"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/network"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/system"
+)
+
+// newBrain: Config'teki ana sağlayıcıyı (ve varsa kaseti) kurar.
+// Ollama kullanılıyorsa model yönetimi aracı için canlı referansı da döner.
+func newBrain(cfg *config.Config) (kernel.Brain, *providers.OllamaProvider, error) {
+	var brain kernel.Brain
+	var ollama *providers.OllamaProvider
+
+	switch cfg.Brain.Primary.Provider {
+	case "gemini":
+		if cfg.Brain.APIKeys.Gemini == "" {
+			return nil, nil, fmt.Errorf("Gemini API anahtarı eksik! config.yaml dosyasını kontrol et")
+		}
+		brain = providers.NewGemini(
+			cfg.Brain.Primary.BaseURL,
+			cfg.Brain.APIKeys.Gemini,
+			cfg.Brain.Primary.ModelName,
+		)
+		logger.Success("🧠 Ana Beyin: Google Gemini (%s)", cfg.Brain.Primary.ModelName)
+
+	case "openai":
+		if cfg.Brain.APIKeys.OpenAI == "" {
+			return nil, nil, fmt.Errorf("OpenAI API anahtarı eksik! config.yaml dosyasını kontrol et")
+		}
+		brain = providers.NewOpenAI(
+			cfg.Brain.Primary.BaseURL,
+			cfg.Brain.APIKeys.OpenAI,
+			cfg.Brain.Primary.ModelName,
+		)
+		logger.Success("🧠 Ana Beyin: OpenAI (%s)", cfg.Brain.Primary.ModelName)
+
+	case "ollama":
+		ollama = providers.NewOllama(
+			cfg.Brain.Primary.BaseURL,
+			cfg.Brain.Primary.ModelName,
+			cfg.Brain.Primary.Temperature,
+			cfg.Brain.Primary.NumCtx,
+		)
+		if cfg.Brain.Primary.EmbeddingModel != "" {
+			ollama.SetEmbeddingModel(cfg.Brain.Primary.EmbeddingModel)
+		}
+		brain = ollama
+		logger.Success("🧠 Ana Beyin: Local Ollama (%s, embedding: %s)", cfg.Brain.Primary.ModelName, ollama.EmbeddingModel())
+
+	default:
+		return nil, nil, fmt.Errorf("Bilinmeyen sağlayıcı: %s. (Desteklenenler: gemini, openai, ollama)", cfg.Brain.Primary.Provider)
+	}
+
+	// Kaset (Kayıt / Tekrar Oynatma)
+	if cfg.Brain.Cassette.Mode != "" {
+		embeddingModel := cfg.Brain.Primary.EmbeddingModel
+		if ollama != nil {
+			embeddingModel = ollama.EmbeddingModel()
+		}
+		cassette, err := providers.NewCassette(brain, cfg.Brain.Cassette.Mode, cfg.Brain.Cassette.Path, cfg.Brain.Primary.ModelName, embeddingModel)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Kaset hazırlanamadı: %v", err)
+		}
+		brain = cassette
+	}
+	return brain, ollama, nil
+}
+
+// openMemory: Config'teki backend ile hafızayı açar ve arama varsayılanlarını uygular (ANN indeksi hariç).
+func openMemory(cfg *config.Config, brain kernel.Brain) (*memory.VectorStore, error) {
+	legacyJSON := cfg.Memory.LegacyJSON
+	if legacyJSON == "" {
+		legacyJSON = "rick_memory.json"
+	}
+
+	var memStore *memory.VectorStore
+	if cfg.Memory.Backend == "json" {
+		memStore = memory.NewVectorStore(legacyJSON, brain)
+	} else {
+		dbPath := cfg.Memory.Path
+		if dbPath == "" {
+			dbPath = "rick_memory.db"
+		}
+		var err error
+		memStore, err = memory.NewSQLiteStore(dbPath, legacyJSON, brain)
+		if err != nil {
+			return nil, fmt.Errorf("Hafıza veritabanı açılamadı: %v", err)
+		}
+	}
+
+	search := cfg.Memory.Search
+	memStore.Defaults = memory.SearchOptions{
+		Limit:         search.Limit,
+		VectorWeight:  search.VectorWeight,
+		LexicalWeight: search.LexicalWeight,
+		Threshold:     search.Threshold,
+		RRFK:          search.RRFK,
+		DecayHalfLife: time.Duration(search.DecayHalfLifeDays * float64(24*time.Hour)),
+	}.WithDefaults(memory.DefaultSearchOptions())
+	return memStore, nil
+}
+
+// openFacts: Kesin bilgiler (anahtar-değer) deposunu açar.
+func openFacts(cfg *config.Config) (*memory.FactStore, error) {
+	path := cfg.Memory.FactsPath
+	if path == "" {
+		path = "rick_facts.db"
+	}
+	facts, err := memory.OpenFactStore(path)
+	if err != nil {
+		return nil, fmt.Errorf("Bilgi deposu açılamadı: %v", err)
+	}
+	return facts, nil
+}
+
+// consolidateOptions: Config'teki hafıza toparlama ayarları
+func consolidateOptions(cfg *config.Config) memory.ConsolidateOptions {
+	c := cfg.Memory.Consolidation
+	return memory.ConsolidateOptions{
+		Similarity:     c.Similarity,
+		MaxCluster:     c.MaxCluster,
+		TTL:            time.Duration(c.TTLDays) * 24 * time.Hour,
+		KeepImportance: c.KeepImportance,
+	}
+}
+
+// sandboxLimits: Python araçlarının sandbox sınırları (Kapalıysa nil)
+func sandboxLimits(cfg *config.Config) *sandbox.Limits {
+	c := cfg.Tools.Sandbox
+	if c.Disabled {
+		return nil
+	}
+	maxOutput := c.MaxOutputKB
+	if maxOutput > 0 {
+		maxOutput <<= 10
+	}
+	return &sandbox.Limits{
+		Timeout:        time.Duration(c.TimeoutSeconds) * time.Second,
+		CPUSeconds:     c.CPUSeconds,
+		MemoryMB:       c.MemoryMB,
+		MaxProcesses:   c.MaxProcesses,
+		MaxOpenFiles:   c.MaxOpenFiles,
+		MaxOutputBytes: maxOutput,
+		IsolateNetwork: c.IsolateNetwork,
+		EnvAllow:       c.EnvAllow,
+	}
+}
+
+// registerNativeTools: Durumsuz yerel (Go) araçlar. Ajan ve "rick mcp-serve" aynı seti kullanır.
+func registerNativeTools(mgr *skills.Manager) {
+	mgr.Register(&filesystem.ListTool{})
+	mgr.Register(&filesystem.ReadTool{})
+	mgr.Register(&filesystem.WriteTool{})
+	mgr.Register(&filesystem.DeleteTool{})
+	mgr.Register(&filesystem.SearchTool{})
+
+	// İnternet / Uzak sunucu
+	mgr.Register(&network.BrowserTool{})
+	mgr.Register(&network.SSHTool{})
+
+	// Arka plan ve zamanlanmış görevler
+	mgr.Register(&system.StartTaskTool{})
+	mgr.Register(&system.CheckTaskTool{})
+	mgr.Register(&system.KillTaskTool{})
+	mgr.Register(&system.ScheduleTaskTool{})
+}
+
+// mcpServers: Config'teki etkin MCP sunucuları
+func mcpServers(cfg *config.Config) []mcp.ServerConfig {
+	var servers []mcp.ServerConfig
+	for _, s := range cfg.MCP.Servers {
+		if s.Disabled {
+			continue
+		}
+		servers = append(servers, mcp.ServerConfig{
+			Name:    s.Name,
+			Command: s.Command,
+			Args:    s.Args,
+			Env:     s.Env,
+			Dir:     s.Dir,
+			URL:     s.URL,
+			Headers: s.Headers,
+			Prefix:  s.Prefix,
+			Tools:   s.Tools,
+			Timeout: time.Duration(s.TimeoutSeconds) * time.Second,
+		})
+	}
+	return servers
+}
+
+// defaultServeTools: "rick mcp-serve" için varsayılan (sağlamlaştırılmış) araç seti
+var defaultServeTools = []string{"fs_*", "browser", "ssh_tool", "start_task", "check_task"}
+
+// servePolicy: Config'teki güvenlik ayarları ve mcp.serve araç listesiyle politika kurar.
+func servePolicy(cfg *config.Config, tools []string) (*policy.Policy, error) {
+	if len(tools) == 0 {
+		tools = cfg.MCP.Serve.Tools
+	}
+	if len(tools) == 0 {
+		tools = defaultServeTools
+	}
+	return policy.New(policy.Options{
+		Level:     cfg.Security.Level,
+		Tools:     tools,
+		Deny:      cfg.Security.DenyTools,
+		Roots:     cfg.Security.AllowedRoots,
+		Protected: append(dataPaths(cfg), cfg.Security.ProtectedPaths...),
+	})
+}
+
+// agentPolicy: Ajanın (modelin) araç çağrılarına uygulanan politika; tüm araçlar seviyenin izin verdiği ölçüde açık.
+func agentPolicy(cfg *config.Config) (*policy.Policy, error) {
+	return policy.New(policy.Options{
+		Level:     cfg.Security.Level,
+		Deny:      cfg.Security.DenyTools,
+		Roots:     cfg.Security.AllowedRoots,
+		Protected: append(dataPaths(cfg), cfg.Security.ProtectedPaths...),
+	})
+}
+
+// dataPaths: Config'te varsayılandan farklı verilmiş hafıza, bilgi ve denetim dosyaları (Politika bunları da korur)
+func dataPaths(cfg *config.Config) []string {
+	var paths []string
+	for _, db := range []string{cfg.Memory.Path, cfg.Memory.FactsPath} {
+		if db != "" {
+			paths = append(paths, db, db+"-wal", db+"-shm", db+".hnsw")
+		}
+	}
+	if cfg.Memory.LegacyJSON != "" {
+		paths = append(paths, cfg.Memory.LegacyJSON, cfg.Memory.LegacyJSON+".hnsw")
+	}
+	if cfg.Security.Audit.Path != "" {
+		paths = append(paths, auditPath(cfg, ""), auditPath(cfg, "mcp"))
+	}
+	return paths
+}
+
+// openAudit: security.audit ayarlarıyla denetim kaydını açar (Kapalıysa nil). suffix doluysa dosya adına eklenir
+// (Örn: "mcp" -> logs/audit-mcp.jsonl); her süreç kendi zincirine yazar.
+func openAudit(cfg *config.Config, suffix string) (*audit.Log, error) {
+	if cfg.Security.Audit.Disabled {
+		return nil, nil
+	}
+	return audit.Open(auditPath(cfg, suffix))
+}
+
+func auditPath(cfg *config.Config, suffix string) string {
+	path := cfg.Security.Audit.Path
+	if path == "" {
+		path = audit.DefaultPath
+	}
+	if suffix != "" {
+		ext := filepath.Ext(path)
+		path = strings.TrimSuffix(path, ext) + "-" + suffix + ext
+	}
+	return path
+}
+
+// pipelineOptions: tools.execution ayarlarından araç zincirinin seçenekleri (auditLog ve approver nil olabilir;
+// onaylayıcı yoksa onay gerektiren araçlar reddedilir)
+func pipelineOptions(cfg *config.Config, auditLog *audit.Log, approver middleware.Approver) middleware.Options {
+	exec := cfg.Tools.Execution
+	opts := middleware.Options{Timeouts: middleware.Timeouts{PerTool: make(map[string]time.Duration)}}
+	if exec.TimeoutSeconds != 0 {
+		opts.Timeouts.Default = time.Duration(exec.TimeoutSeconds) * time.Second
+	}
+	for name, sec := range exec.Timeouts {
+		opts.Timeouts.PerTool[name] = time.Duration(sec) * time.Second
+	}
+	if exec.MaxOutputKB > 0 {
+		opts.MaxOutputBytes = exec.MaxOutputKB << 10
+	} else if exec.MaxOutputKB < 0 {
+		opts.MaxOutputBytes = -1
+	}
+	if len(exec.RequireApproval) > 0 {
+		opts.Interceptors = append(opts.Interceptors, &middleware.ApprovalInterceptor{Tools: exec.RequireApproval, Approver: approver})
+	}
+	opts.Interceptors = append(opts.Interceptors, middleware.LogAuditor{})
+	if auditLog != nil {
+		opts.Interceptors = append(opts.Interceptors, &middleware.AuditInterceptor{Log: auditLog})
+	}
+	return opts
+}
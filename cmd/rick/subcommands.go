@@ -0,0 +1,541 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/audit"
+	"github.com/aydndglr/rick-agent-v3/internal/core/config"
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+	"github.com/aydndglr/rick-agent-v3/internal/memory"
+	"github.com/aydndglr/rick-agent-v3/internal/skills"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/coding"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/mcp"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/middleware"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/recall"
+)
+
+// runSubcommand: "rick <komut> ..." biçimindeki yardımcı komutları ajanı başlatmadan çalıştırır.
+// Komut tanınmazsa ok=false döner ve normal başlangıç devam eder.
+func runSubcommand(args []string) (code int, ok bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	switch args[0] {
+	case "bench":
+		return runBench(args[1:]), true
+	case "ingest":
+		return runIngest(args[1:]), true
+	case "memory":
+		return runMemory(args[1:]), true
+	case "tools":
+		return runTools(args[1:]), true
+	case "mcp-serve":
+		return runMCPServe(args[1:]), true
+	case "audit":
+		return runAudit(args[1:]), true
+	}
+	return 0, false
+}
+
+// runBench: "rick bench memory -sizes 10000,100000 -dim 384 -queries 200"
+func runBench(args []string) int {
+	if len(args) == 0 || args[0] != "memory" {
+		fmt.Println("Kullanım: rick bench memory [-sizes 10000,100000] [-dim 384] [-queries 200] [-k 10]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("bench memory", flag.ContinueOnError)
+	sizes := fs.String("sizes", "10000,100000", "Virgülle ayrılmış doküman sayıları")
+	dim := fs.Int("dim", 384, "Embedding boyutu")
+	queries := fs.Int("queries", 200, "Boyut başına sorgu sayısı")
+	k := fs.Int("k", 10, "Sorgu başına sonuç sayısı")
+	seed := fs.Int64("seed", 1, "Rastgele üreteç tohumu")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	opts := memory.BenchOptions{Dim: *dim, Queries: *queries, K: *k, Seed: *seed}
+	for _, s := range strings.Split(*sizes, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n <= 0 {
+			fmt.Printf("❌ Geçersiz boyut: %q\n", s)
+			return 2
+		}
+		opts.Sizes = append(opts.Sizes, n)
+	}
+
+	memory.RunBenchmark(os.Stdout, opts)
+	return 0
+}
+
+// runIngest: "rick ingest ./docs -include '**/*.md' -exclude 'drafts/**' -namespace project:rick"
+func runIngest(args []string) int {
+	fs := flag.NewFlagSet("ingest", flag.ContinueOnError)
+	include := fs.String("include", "", "Virgülle ayrılmış dahil edilecek glob'lar (Örn: **/*.md,docs/**)")
+	exclude := fs.String("exclude", "", "Virgülle ayrılmış atlanacak glob'lar")
+	namespace := fs.String("namespace", "shared", "Hedef ad alanı (shared, project:<ad>, user:<id>)")
+	tags := fs.String("tags", "", "Virgülle ayrılmış etiketler")
+	chunkSize := fs.Int("chunk-size", 0, "Parça boyutu (karakter, varsayılan 1500)")
+	overlap := fs.Int("overlap", 0, "Büyük bölümler bölünürken örtüşme (karakter, varsayılan 200)")
+
+	// Yol bayraklardan önce de sonra da verilebilsin
+	var paths []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		paths = append(paths, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(paths) == 0 {
+		fmt.Println("Kullanım: rick ingest <klasör|dosya> [-include **/*.md] [-exclude drafts/**] [-namespace shared] [-tags runbook]")
+		return 2
+	}
+
+	memStore, code := openCLIMemory()
+	if memStore == nil {
+		return code
+	}
+	defer memStore.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	failed := false
+	for _, path := range paths {
+		report, err := memStore.Ingest(ctx, memory.IngestOptions{
+			Path:         path,
+			Include:      splitList(*include),
+			Exclude:      splitList(*exclude),
+			Namespace:    *namespace,
+			Tags:         splitList(strings.ToLower(*tags)),
+			ChunkSize:    *chunkSize,
+			ChunkOverlap: *overlap,
+		})
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		fmt.Print(recall.FormatIngestReport(path, *namespace, report))
+		if len(report.Errors) > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+const memoryUsage = `Kullanım:
+  rick memory list   [-namespace ns] [-tags a,b] [-since 7d] [-until 2026-10-01] [-limit 20]
+  rick memory show   <id>
+  rick memory search <sorgu> [-namespace ns] [-tags a,b] [-limit 20]
+  rick memory delete <id> [id...]
+  rick memory export [-o hafiza.jsonl] [-namespace ns] [-embeddings]
+  rick memory import <dosya.jsonl> [-namespace ns]`
+
+// runMemory: "rick memory <list|show|search|delete|export|import>" hafızayı ajanı başlatmadan inceler ve taşır.
+// Eylemler memory_admin aracına yönlendirilir; böylece CLI ve model aynı çıktıyı görür.
+func runMemory(args []string) int {
+	if len(args) == 0 {
+		fmt.Println(memoryUsage)
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("memory "+action, flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "Ad alanı (Örn: shared, project:rick); import için hedef ad alanı")
+	tags := fs.String("tags", "", "Virgülle ayrılmış etiketler (Hepsi aranır)")
+	since := fs.String("since", "", "Bu zamandan sonraki kayıtlar ('2026-10-01', '24h', '7d')")
+	until := fs.String("until", "", "Bu zamandan önceki kayıtlar")
+	limit := fs.Int("limit", 20, "En fazla kayıt")
+	output := fs.String("o", "", "export için çıktı dosyası (Boşsa standart çıktı)")
+	embeddings := fs.Bool("embeddings", false, "export için vektörleri de yaz")
+
+	// Konumsal argümanlar bayraklardan önce de sonra da verilebilsin
+	var positional []string
+	rest := args[1:]
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+
+	toolArgs := map[string]interface{}{
+		"action":    action,
+		"namespace": *namespace,
+		"tags":      *tags,
+		"since":     *since,
+		"until":     *until,
+		"limit":     float64(*limit),
+	}
+	switch action {
+	case "list":
+	case "show":
+		if len(positional) != 1 {
+			fmt.Println(memoryUsage)
+			return 2
+		}
+		toolArgs["id"] = positional[0]
+	case "search":
+		if len(positional) == 0 {
+			fmt.Println(memoryUsage)
+			return 2
+		}
+		toolArgs["query"] = strings.Join(positional, " ")
+	case "delete":
+		if len(positional) == 0 {
+			fmt.Println(memoryUsage)
+			return 2
+		}
+		toolArgs["ids"] = positional
+	case "import":
+		if len(positional) != 1 {
+			fmt.Println(memoryUsage)
+			return 2
+		}
+		toolArgs["path"] = positional[0]
+	case "export":
+	default:
+		fmt.Println(memoryUsage)
+		return 2
+	}
+
+	memStore, code := openCLIMemory()
+	if memStore == nil {
+		return code
+	}
+	defer memStore.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx = kernel.WithActor(ctx, kernel.ActorCLI)
+
+	// Standart çıktıya döküm: Araç dosya yolu ister, JSONL doğrudan yazılır
+	if action == "export" && *output == "" {
+		if _, err := memStore.Export(ctx, os.Stdout, memory.ExportOptions{Namespaces: splitList(*namespace), Embeddings: *embeddings}); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	if action == "export" {
+		toolArgs["path"] = *output
+		toolArgs["embeddings"] = *embeddings
+	}
+
+	tool := &recall.AdminTool{Store: memStore}
+	res, err := tool.ExecuteRich(ctx, toolArgs)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Print(res.Text)
+	if !strings.HasSuffix(res.Text, "\n") {
+		fmt.Println()
+	}
+	if !res.OK() {
+		return 1
+	}
+	return 0
+}
+
+const toolsUsage = `Kullanım:
+  rick tools list     [dosya.py]
+  rick tools show     <dosya.py> [sürüm]
+  rick tools diff     <dosya.py> [eski] [yeni]
+  rick tools rollback <dosya.py> [sürüm]`
+
+// runTools: "rick tools <list|show|diff|rollback>" Python araçlarının sürüm geçmişini ajanı başlatmadan yönetir.
+// Ajan çalışıyorsa geri dönülen araç klasör izleyicisiyle yeniden yüklenir.
+func runTools(args []string) int {
+	if len(args) == 0 {
+		fmt.Println(toolsUsage)
+		return 2
+	}
+	toolArgs := map[string]interface{}{"action": args[0]}
+	rest := args[1:]
+	switch args[0] {
+	case "list":
+		if len(rest) > 1 {
+			fmt.Println(toolsUsage)
+			return 2
+		}
+	case "show", "rollback":
+		if len(rest) == 0 || len(rest) > 2 {
+			fmt.Println(toolsUsage)
+			return 2
+		}
+		if len(rest) == 2 {
+			toolArgs["version"] = rest[1]
+		}
+	case "diff":
+		if len(rest) == 0 || len(rest) > 3 {
+			fmt.Println(toolsUsage)
+			return 2
+		}
+		if len(rest) > 1 {
+			toolArgs["from"] = rest[1]
+		}
+		if len(rest) > 2 {
+			toolArgs["to"] = rest[2]
+		}
+	default:
+		fmt.Println(toolsUsage)
+		return 2
+	}
+	if len(rest) > 0 {
+		toolArgs["filename"] = rest[0]
+	}
+
+	ctx := kernel.WithActor(context.Background(), kernel.ActorCLI)
+	res, err := coding.NewToolHistory("tools").ExecuteRich(ctx, toolArgs)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Print(res.Text)
+	if !strings.HasSuffix(res.Text, "\n") {
+		fmt.Println()
+	}
+	if !res.OK() {
+		return 1
+	}
+	return 0
+}
+
+// runMCPServe: "rick mcp-serve [-tools fs_*,browser] [-list]" Rick'in yerel araçlarını stdio üzerinden MCP
+// sunucusu olarak açar. security ayarları (seviye, izinli klasörler, korunan yollar) her çağrıda uygulanır.
+func runMCPServe(args []string) int {
+	fs := flag.NewFlagSet("mcp-serve", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	tools := fs.String("tools", "", "Virgülle ayrılmış açılacak araç kalıpları (Boşsa config'teki mcp.serve.tools)")
+	list := fs.Bool("list", false, "Açılacak araçları yazdır ve çık")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load("config/config.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Config yüklenemedi: %v\n", err)
+		return 1
+	}
+	pol, err := servePolicy(cfg, splitList(*tools))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Güvenlik politikası geçersiz: %v\n", err)
+		return 1
+	}
+	mgr := skills.NewManager()
+	registerNativeTools(mgr)
+
+	server := mcp.NewServer(mgr, pol)
+	server.Name = "rick"
+	server.Version = cfg.App.Version
+	auditLog, err := openAudit(cfg, "mcp")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Denetim kaydı açılamadı: %v\n", err)
+		return 1
+	}
+	if auditLog != nil {
+		defer auditLog.Close()
+	}
+	opts := pipelineOptions(cfg, auditLog, nil) // stdio kanalında onay soracak kimse yok
+	if cfg.MCP.Serve.TimeoutSeconds != 0 {
+		opts.Timeouts.Default = time.Duration(cfg.MCP.Serve.TimeoutSeconds) * time.Second
+	}
+	server.Pipeline = middleware.Default(opts)
+
+	if *list {
+		fmt.Printf("🔒 Güvenlik seviyesi: %s\n", pol.Level())
+		for _, t := range mgr.ListTools() {
+			if pol.Exposed(t.Name()) {
+				fmt.Printf("- %s (%s)\n", t.Name(), policy.Class(t.Name()))
+			}
+		}
+		return 0
+	}
+
+	// stdout MCP kanalıdır: Logger dahil her türlü yazdırma stderr'e gitsin
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	logger.Setup(cfg.App.Debug, "logs")
+	logger.Info("🔌 MCP sunucusu stdio üzerinde (Güvenlik seviyesi: %s)", pol.Level())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = kernel.WithSession(ctx, fmt.Sprintf("MCP-%X", time.Now().UnixNano()%0xFFFFF))
+	if err := server.ServeStdio(ctx, os.Stdin, out); err != nil && ctx.Err() == nil {
+		logger.Error("💥 MCP sunucusu: %v", err)
+		return 1
+	}
+	return 0
+}
+
+const auditUsage = `Kullanım:
+  rick audit verify [-file logs/audit.jsonl] [-head <hash>]
+  rick audit query  [-file logs/audit.jsonl] [-tool fs_*] [-session TSK-1A2B] [-conversation id] [-actor model]
+                    [-status denied] [-since 24h] [-until 2026-10-01] [-limit 50] [-json]
+
+-file verilmezse security.audit.path ve (varsa) "rick mcp-serve"in -mcp ekli kaydı birlikte incelenir.`
+
+// runAudit: "rick audit <verify|query>" araç çağrılarının denetim kaydını doğrular ve süzer.
+func runAudit(args []string) int {
+	if len(args) == 0 || (args[0] != "verify" && args[0] != "query") {
+		fmt.Println(auditUsage)
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("audit "+action, flag.ContinueOnError)
+	file := fs.String("file", "", "Denetim kaydı (Boşsa config'teki yol)")
+	head := fs.String("head", "", "verify: Daha önce not edilen uç hash; sondan silinen kayıtları yakalar")
+	tool := fs.String("tool", "", "Araç adı kalıbı (Örn: fs_*)")
+	session := fs.String("session", "", "Görev oturumu (Örn: TSK-1A2B)")
+	conversation := fs.String("conversation", "", "Sohbet kimliği (WhatsApp JID, cli...)")
+	actor := fs.String("actor", "", "Çağıran (model, admin, cli, mcp)")
+	status := fs.String("status", "", "Sonuç (success, failed, error, denied)")
+	since := fs.String("since", "", "Bu zamandan sonraki kayıtlar ('2026-10-01', '24h', '7d')")
+	until := fs.String("until", "", "Bu zamandan önceki kayıtlar")
+	limit := fs.Int("limit", 50, "En fazla kayıt (Sondan; 0 = hepsi)")
+	asJSON := fs.Bool("json", false, "Kayıtları ham JSONL olarak yazdır")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	files := []string{*file}
+	if *file == "" {
+		cfg, err := config.Load("config/config.yaml")
+		if err != nil {
+			fmt.Printf("❌ Config yüklenemedi: %v\n", err)
+			return 1
+		}
+		files = []string{auditPath(cfg, "")}
+		if _, err := os.Stat(auditPath(cfg, "mcp")); err == nil {
+			files = append(files, auditPath(cfg, "mcp"))
+		}
+	}
+
+	if action == "verify" {
+		code := 0
+		for _, path := range files {
+			rep, err := audit.Verify(path, *head)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				code = 1
+				continue
+			}
+			if rep.OK() {
+				fmt.Printf("✅ %s: %d kayıt, zincir sağlam. Uç: seq %d, %s\n", path, rep.Entries, rep.LastSeq, rep.Head)
+				continue
+			}
+			code = 1
+			fmt.Printf("🚨 %s: %d kayıtta %d sorun bulundu:\n", path, rep.Entries, len(rep.Problems))
+			for _, p := range rep.Problems {
+				fmt.Printf("  - %s\n", p)
+			}
+		}
+		return code
+	}
+
+	filter := audit.Filter{Tool: *tool, Session: *session, Conversation: *conversation, Actor: *actor, Status: *status, Limit: *limit}
+	var err error
+	if filter.Since, err = recall.ParseTimeArg(*since); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	if filter.Until, err = recall.ParseTimeArg(*until); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+
+	var entries []audit.Entry
+	for _, path := range files {
+		found, err := audit.Query(path, filter)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		entries = append(entries, found...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].When().Before(entries[j].When()) })
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[len(entries)-*limit:]
+	}
+
+	for _, e := range entries {
+		if *asJSON {
+			data, _ := json.Marshal(e)
+			fmt.Println(string(data))
+			continue
+		}
+		args := []rune(string(e.Args))
+		if len(args) > 160 {
+			args = append(args[:160], '…')
+		}
+		line := fmt.Sprintf("%s #%d [%s] %s/%s %s -> %s (%dms) %s",
+			e.When().Local().Format("2006-01-02 15:04:05"), e.Seq, e.Session, e.Actor, e.Conversation, e.Tool, e.Status, e.DurationMs, string(args))
+		if e.Error != "" {
+			line += "\n    ↳ " + strings.ReplaceAll(e.Error, "\n", " ")
+		}
+		fmt.Println(line)
+	}
+	if !*asJSON {
+		fmt.Printf("(%d kayıt)\n", len(entries))
+	}
+	return 0
+}
+
+// openCLIMemory: Yardımcı komutlar için config'i, logger'ı, beyni ve hafızayı ajanı başlatmadan açar.
+// Hata durumunda nil ve çıkış kodu döner.
+func openCLIMemory() (*memory.VectorStore, int) {
+	cfg, err := config.Load("config/config.yaml")
+	if err != nil {
+		fmt.Printf("❌ Config yüklenemedi: %v\n", err)
+		return nil, 1
+	}
+	logger.Setup(cfg.App.Debug, "logs")
+
+	brain, _, err := newBrain(cfg)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return nil, 1
+	}
+	memStore, err := openMemory(cfg, brain)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return nil, 1
+	}
+	return memStore, 0
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// commandAliases: Kısa yönetici komutlarını araç adına ve pozisyonel parametrelere eşler.
+// Örn: "/model use qwen3:8b conversation" -> ollama_models {action: switch, model: qwen3:8b, scope: conversation}
+var commandAliases = map[string]struct {
+	Tool       string
+	Actions    map[string]string // Kullanıcı eylemi -> araç eylemi
+	Positional []string          // Eylemden sonraki çıplak kelimelerin parametre adları
+}{
+	"model": {
+		Tool:       "ollama_models",
+		Actions:    map[string]string{"use": "switch"},
+		Positional: []string{"model", "scope"},
+	},
+	// Örn: "/facts set user.language Türkçe", "/facts list", "/facts delete server.prod"
+	"facts": {
+		Tool:       "facts",
+		Actions:    map[string]string{"rm": "delete", "ls": "list"},
+		Positional: []string{"key", "value"},
+	},
+	// Örn: "/tools history hava.py", "/tools diff hava.py 2 4", "/tools rollback hava.py 3"
+	"tools": {
+		Tool:       "tool_history",
+		Actions:    map[string]string{"history": "list", "ls": "list", "cat": "show"},
+		Positional: []string{"filename", "version", "to"},
+	},
+	// Örn: "/tests run hava.py", "/tests list hava.py"
+	"tests": {
+		Tool:       "test_tool",
+		Actions:    map[string]string{"ls": "list", "rm": "remove"},
+		Positional: []string{"filename", "names"},
+	},
+}
+
+// IsCommand: Satır bilinen bir komutsa ("/approve", "/deny", kısaltmalar, kayıtlı araç adları) true döner.
+// "/etc/hosts dosyasını oku" gibi "/" ile başlayan sıradan istekler komut sayılmaz.
+func (a *Rick) IsCommand(line string) bool {
+	fields := strings.Fields(strings.TrimSpace(line))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return false
+	}
+	name := fields[0][1:]
+	if name == "approve" || name == "deny" {
+		return true
+	}
+	if _, ok := commandAliases[name]; ok {
+		return true
+	}
+	_, err := a.Skills.GetTool(name)
+	return err == nil
+}
+
+// HandleCommand: "/" ile başlayan yönetici komutlarını LLM'e sormadan doğrudan ilgili araca yönlendirir.
+// Biçim: "/<araç veya kısaltma> [eylem] [değer ...] [anahtar=değer ...]". Komut değilse (IsCommand) false döner.
+// Komutlar sadece çağıran yöneticiyse (ctx'te kernel.ActorAdmin) çalışır; gönderenin kimliğini portal doğrular.
+func (a *Rick) HandleCommand(ctx context.Context, line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !a.IsCommand(line) {
+		return "", false
+	}
+	if kernel.ActorFrom(ctx) != kernel.ActorAdmin {
+		logger.Warn("🛑 Yönetici olmayan çağıran (%s) komut denedi: %s", kernel.ActorFrom(ctx), line)
+		return "🛑 '/' komutları sadece yönetici tarafından çalıştırılabilir.", true
+	}
+
+	fields := strings.Fields(line[1:])
+	name := fields[0]
+	rest := fields[1:]
+
+	// Onay cevapları: "/approve 3", "/deny 3"
+	if name == "approve" || name == "deny" {
+		return a.resolveApproval(name == "approve", rest), true
+	}
+
+	toolName := name
+	var positional []string
+	var actionMap map[string]string
+	if alias, ok := commandAliases[name]; ok {
+		toolName = alias.Tool
+		positional = alias.Positional
+		actionMap = alias.Actions
+	}
+
+	tool, err := a.Skills.GetTool(toolName)
+	if err != nil {
+		return fmt.Sprintf("❌ '/%s' komutunun aracı (%s) yüklü değil.", name, toolName), true
+	}
+
+	args := make(map[string]interface{})
+	pos := 0
+	for i, f := range rest {
+		if key, val, ok := strings.Cut(f, "="); ok && key != "" {
+			args[key] = parseCommandValue(val)
+			continue
+		}
+		// İlk çıplak kelime eylemdir
+		if i == 0 {
+			if mapped, ok := actionMap[f]; ok {
+				f = mapped
+			}
+			args["action"] = f
+			continue
+		}
+		if pos < len(positional) {
+			args[positional[pos]] = f
+			pos++
+		} else if pos > 0 {
+			// Fazla kelimeler son parametreye eklenir ("/facts set server.prod db01 (İstanbul)")
+			last := positional[pos-1]
+			args[last] = fmt.Sprintf("%v %s", args[last], f)
+		}
+	}
+
+	logger.Action("⌨️ Yönetici Komutu: %s %v", toolName, args)
+	res, err := a.Pipeline.Run(ctx, tool, "", args)
+	if err != nil {
+		return fmt.Sprintf("❌ %v", err), true
+	}
+	return res.Text, true
+}
+
+// resolveApproval: Bekleyen bir araç çağrısı onay isteğini cevaplar.
+func (a *Rick) resolveApproval(ok bool, rest []string) string {
+	if a.Approvals == nil {
+		return "❌ Onay mekanizması bağlı değil."
+	}
+	if len(rest) != 1 {
+		return "❌ Kullanım: /approve <no> veya /deny <no>"
+	}
+	id := strings.TrimPrefix(rest[0], "#")
+	if err := a.Approvals.Resolve(id, ok); err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	if ok {
+		return fmt.Sprintf("✅ #%s onaylandı.", id)
+	}
+	return fmt.Sprintf("🛑 #%s reddedildi.", id)
+}
+
+// parseCommandValue: "true", "42", "[...]" gibi değerleri JSON olarak çözer, olmazsa düz metin bırakır.
+func parseCommandValue(raw string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err == nil {
+		return v
+	}
+	return raw
+}
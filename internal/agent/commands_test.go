@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/skills"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/middleware"
+)
+
+// echoTool: Aldığı eylemi geri yazan test aracı
+type echoTool struct{}
+
+func (echoTool) Name() string                       { return "echo" }
+func (echoTool) Description() string                { return "test" }
+func (echoTool) Parameters() map[string]interface{} { return nil }
+func (echoTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return "eylem: " + args["action"].(string), nil
+}
+
+func newCommandRick() *Rick {
+	mgr := skills.NewManager()
+	mgr.Register(echoTool{})
+	return &Rick{Skills: mgr, Pipeline: middleware.New()}
+}
+
+func TestHandleCommand(t *testing.T) {
+	admin := kernel.WithActor(context.Background(), kernel.ActorAdmin)
+	model := kernel.WithActor(context.Background(), kernel.ActorModel)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		line    string
+		handled bool
+		want    string
+	}{
+		{"kayıtlı araç", admin, "/echo ping", true, "eylem: ping"},
+		{"kısaltma, araç yüklü değil", admin, "/model list", true, "yüklü değil"},
+		{"onay mekanizması yok", admin, "/approve 1", true, "bağlı değil"},
+		{"yol ile başlayan istek ajana gider", admin, "/etc/hosts dosyasını oku", false, ""},
+		{"bilinmeyen kelime ajana gider", admin, "/merhaba nasılsın", false, ""},
+		{"düz metin", admin, "echo ping", false, ""},
+		{"yönetici olmayan komut reddedilir", model, "/echo ping", true, "sadece yönetici"},
+		{"yönetici olmayanın sıradan isteği ajana gider", model, "/tmp/a.txt içinde ne var", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newCommandRick()
+			out, handled := r.HandleCommand(tt.ctx, tt.line)
+			if handled != tt.handled {
+				t.Fatalf("handled = %v, beklenen %v (%s)", handled, tt.handled, out)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Fatalf("'%s' içeren çıktı bekleniyordu, alınan: %s", tt.want, out)
+			}
+		})
+	}
+}
@@ -11,7 +11,8 @@ import (
 )
 
 // BuildSystemPrompt: Rick'in anayasasını, mühendislik disiplinini ve karakterini oluşturur.
-func BuildSystemPrompt(promptPath, workDir, securityLevel string, tools []kernel.Tool) kernel.Message {
+// Kayıtlı kesin bilgiler (facts) kaynaklarıyla birlikte sona eklenir.
+func BuildSystemPrompt(promptPath, workDir, securityLevel string, tools []kernel.Tool, facts []kernel.Fact) kernel.Message {
 	var toolDescriptions []string
 	for _, t := range tools {
 		toolDescriptions = append(toolDescriptions, fmt.Sprintf("- %s: %s", t.Name(), t.Description()))
@@ -28,10 +29,19 @@ func BuildSystemPrompt(promptPath, workDir, securityLevel string, tools []kernel
 		prompt = fmt.Sprintf(string(data), currentYear, workDir, securityLevel, strings.Join(toolDescriptions, "\n"))
 	} else {
 		// Dosya bulunamazsa veya okunamazsa log bas ve varsayılana dön
-		logger.Warn("⚠️ Prompt dosyası bulunamadı (%s), model saf hali ile çalışacak ")
+		logger.Warn("⚠️ Prompt dosyası bulunamadı (%s), model saf hali ile çalışacak ", promptPath)
 
 	}
 
+	if len(facts) > 0 {
+		var sb strings.Builder
+		sb.WriteString("\n\n📌 [KESİN BİLGİLER] (Kullanıcının tercihleri ve envanteri; bunlara birebir uy. Değişirse 'facts' aracıyla güncelle):\n")
+		for _, f := range facts {
+			sb.WriteString(fmt.Sprintf("- %s = %s (%s, %s tarafından, %s)\n", f.Key, f.Value, f.Scope, f.SetBy, f.UpdatedAt.Format("2006-01-02")))
+		}
+		prompt += sb.String()
+	}
+
 	return kernel.Message{
 		Role:    "system",
 		Content: prompt,
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+const (
+	defaultRecallLimit   = 3
+	defaultFactsLimit    = 50
+	transcriptImportance = 0.2
+)
+
+// recallMemories: Göreve başlarken sadece bu sohbetin ve ortak (shared) hafızanın ilgili kayıtlarını getirir.
+// Başka sohbetlerin anıları asla sızmaz. Sonuç sistem mesajına eklenecek metindir (Yoksa boş).
+func (a *Rick) recallMemories(ctx context.Context, conversationID, input string) string {
+	limit := a.Config.Memory.RecallLimit
+	if a.Memory == nil || limit < 0 || strings.TrimSpace(input) == "" {
+		return ""
+	}
+	if limit == 0 {
+		limit = defaultRecallLimit
+	}
+
+	recallCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	records, err := a.Memory.Query(recallCtx, kernel.MemoryQuery{
+		Query:      input,
+		Namespaces: []string{kernel.ConversationNamespace(conversationID), kernel.NamespaceShared},
+		Limit:      limit,
+	})
+	if err != nil {
+		logger.Warn("⚠️ Hafıza getirilemedi: %v", err)
+		return ""
+	}
+	if len(records) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🧠 [İLGİLİ HAFIZA KAYITLARI] (Geçmişten hatırladıkların; güncel olmayabilir, gerekirse doğrula):\n")
+	for _, r := range records {
+		sb.WriteString(fmt.Sprintf("- (🆔 %s, %s, %s) %s\n", r.ID, r.Namespace, r.CreatedAt.Format("2006-01-02"), r.Content))
+	}
+	logger.Debug("🧠 Hafızadan %d kayıt getirildi (%s)", len(records), conversationID)
+	return sb.String()
+}
+
+// rememberExchange: Tamamlanan soru-cevabı sohbetin kendi ad alanına kaydeder.
+// Ham dökümler düşük önemlidir; hafıza toparlama bunları zamanla özetler veya arşivler.
+func (a *Rick) rememberExchange(conversationID, input, answer string) {
+	if a.Memory == nil {
+		return
+	}
+	metadata := map[string]interface{}{"source": "conversation", "kind": "transcript", "importance": transcriptImportance}
+	content := fmt.Sprintf("User: %s | Rick: %s", input, answer)
+	if _, err := a.Memory.Save(context.Background(), kernel.ConversationNamespace(conversationID), content, metadata); err != nil {
+		logger.Warn("⚠️ Sohbet hafızaya kaydedilemedi: %v", err)
+	}
+}
+
+// loadFacts: Bu sohbette geçerli kesin bilgileri getirir. Aynı anahtar hem sohbete özel hem ortak
+// tanımlıysa sohbete özel olan geçerlidir.
+func (a *Rick) loadFacts(ctx context.Context, conversationID string) []kernel.Fact {
+	limit := a.Config.Memory.FactsLimit
+	if a.Facts == nil || limit < 0 {
+		return nil
+	}
+	if limit == 0 {
+		limit = defaultFactsLimit
+	}
+
+	conversation := kernel.ConversationNamespace(conversationID)
+	facts, err := a.Facts.ListFacts(ctx, []string{conversation, kernel.NamespaceShared}, "")
+	if err != nil {
+		logger.Warn("⚠️ Kesin bilgiler getirilemedi: %v", err)
+		return nil
+	}
+
+	overridden := make(map[string]bool)
+	for _, f := range facts {
+		if f.Scope == conversation {
+			overridden[f.Key] = true
+		}
+	}
+	var out []kernel.Fact
+	for _, f := range facts {
+		if f.Scope != conversation && overridden[f.Key] {
+			continue
+		}
+		out = append(out, f)
+	}
+	if len(out) > limit {
+		logger.Warn("⚠️ %d kesin bilginin sadece ilk %d tanesi sisteme eklendi (memory.facts_limit)", len(out), limit)
+		out = out[:limit]
+	}
+	return out
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
 	"github.com/aydndglr/rick-agent-v3/internal/skills"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/middleware"
+	"github.com/aydndglr/rick-agent-v3/internal/usage"
 )
 
 // Session: Rick'in aynı anda çalıştırdığı her bir görevin izole beyni
@@ -22,6 +24,8 @@ type Session struct {
 	History   []kernel.Message
 	CreatedAt time.Time
 	Cancel    context.CancelFunc // 🚀 GÖREVİ ÖLDÜRME SİNYALİ
+	Recall    string             // Göreve başlarken hafızadan getirilen ilgili kayıtlar (sistem mesajına eklenir)
+	Facts     []kernel.Fact      // Bu sohbette geçerli kesin bilgiler (sistem mesajına eklenir)
 	mu        sync.Mutex
 }
 
@@ -30,10 +34,16 @@ type Rick struct {
 	Brain    kernel.Brain
 	Skills   *skills.Manager
 	Memory   kernel.Memory
+	Facts    kernel.FactStore     // Kesin bilgiler (Opsiyonel)
+	Usage    *usage.Meter         // Token muhasebesi ve bütçe bekçisi
+	Pipeline *middleware.Pipeline // Araç çalıştırma zinciri (Panik yakalama, zaman aşımı, politika...)
 	MaxSteps int
+
+	Approvals *middleware.Approvals // Yönetici onayı bekleyen araç çağrıları (Opsiyonel)
 	
-	Sessions map[string]*Session
-	sessMu   sync.RWMutex
+	Sessions    map[string]*Session
+	sessMu      sync.RWMutex
+	lastSession int64 // Son oturum ID'sinin zaman damgası (ID'ler tekrarlanmasın)
 }
 
 // =====================================================================
@@ -45,14 +55,14 @@ type RickControlTool struct {
 
 func (t *RickControlTool) Name() string { return "rick_control" }
 func (t *RickControlTool) Description() string { 
-	return "Rick'in arka planda çalışan aktif görevlerini (oturumlarını) yönetmesini sağlar. Hatalı, donmuş veya iptal edilmesi istenen bir 'TSK-...' görevini durdurmak (cancel) veya aktif listeyi görmek (list) için kullan." 
+	return "Rick'in arka planda çalışan aktif görevlerini (oturumlarını) yönetmesini sağlar. Hatalı, donmuş veya iptal edilmesi istenen bir 'TSK-...' görevini durdurmak (cancel), aktif listeyi görmek (list) veya token/maliyet kullanımını raporlamak (usage) için kullan." 
 }
 func (t *RickControlTool) Parameters() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
-			"action": map[string]interface{}{"type": "string", "enum": []string{"list", "cancel"}},
-			"session_id": map[string]interface{}{"type": "string", "description": "İptal edilecek görevin ID'si (Örn: TSK-1A2B). 'list' işlemi için boş bırakılabilir."},
+			"action": map[string]interface{}{"type": "string", "enum": []string{"list", "cancel", "usage"}},
+			"session_id": map[string]interface{}{"type": "string", "description": "İptal edilecek (veya 'usage' için raporlanacak) görevin ID'si (Örn: TSK-1A2B). 'list' işlemi için boş bırakılabilir."},
 		},
 		"required": []string{"action"},
 	}
@@ -90,6 +100,14 @@ func (t *RickControlTool) Execute(ctx context.Context, args map[string]interface
 			return fmt.Sprintf("✅ BAŞARILI: [%s] görevine ölüm sinyali (Cancel) gönderildi. Görev durduruluyor.", sessID), nil
 		}
 	}
+
+	if action == "usage" {
+		if t.rick.Usage == nil {
+			return "Token muhasebesi bu kurulumda kapalı.", nil
+		}
+		sessID, _ := args["session_id"].(string)
+		return t.rick.Usage.Report(sessID, kernel.ConversationFrom(ctx)), nil
+	}
 	return "Geçersiz eylem.", nil
 }
 
@@ -99,6 +117,8 @@ func NewRick(cfg *config.Config, brain kernel.Brain, skillMgr *skills.Manager, m
 		Brain:    brain,
 		Skills:   skillMgr,
 		Memory:   mem,
+		Usage:    usage.NewMeter(cfg),
+		Pipeline: middleware.Default(middleware.Options{}),
 		MaxSteps: 15,
 		Sessions: make(map[string]*Session),
 	}
@@ -116,7 +136,13 @@ func (a *Rick) createSession(cancel context.CancelFunc) *Session {
 	a.sessMu.Lock()
 	defer a.sessMu.Unlock()
 
-	sessID := fmt.Sprintf("TSK-%X", time.Now().UnixNano()%0xFFFFF)
+	// Kullanım defteri oturumları 30 gün tutar; ID tam nanosaniye damgasıdır ve aynı anda açılanlar için artırılır
+	stamp := time.Now().UnixNano()
+	if stamp <= a.lastSession {
+		stamp = a.lastSession + 1
+	}
+	a.lastSession = stamp
+	sessID := fmt.Sprintf("TSK-%X", stamp)
 	
 	sess := &Session{
 		ID:        sessID,
@@ -133,16 +159,25 @@ func (a *Rick) Run(ctx context.Context, input string, images []string) (string,
 	// 🚀 Göreve özel iptal edilebilir (cancellable) context oluştur
 	sessCtx, cancel := context.WithCancel(ctx)
 	sess := a.createSession(cancel)
+	sessCtx = kernel.WithSession(sessCtx, sess.ID)
 	defer cancel() // Fonksiyon bitince belleği sızdırmamak için kabloyu kopar
+
+	conversationID := kernel.ConversationFrom(ctx)
 	
 	logger.Info("👤 User [%s]: %s (Görsel: %d)", sess.ID, input, len(images))
 
+	// 🧠 HAFIZA: Bu sohbetin ve ortak hafızanın ilgili kayıtlarını getir
+	recall := a.recallMemories(ctx, conversationID, input)
+	facts := a.loadFacts(ctx, conversationID)
+
 	sess.mu.Lock()
 	sess.History = append(sess.History, kernel.Message{
 		Role:    "user",
 		Content: input,
 		Images:  images,
 	})
+	sess.Recall = recall
+	sess.Facts = facts
 	sess.mu.Unlock()
 
 	a.refreshSystemPrompt(sess)
@@ -159,6 +194,17 @@ func (a *Rick) Run(ctx context.Context, input string, images []string) (string,
 		default:
 		}
 
+		// 💰 BÜTÇE KONTROLÜ: Sınır aşıldıysa beyne yeni istek gönderme
+		if a.Usage != nil {
+			if err := a.Usage.CheckBudget(sess.ID); err != nil {
+				a.sessMu.Lock()
+				delete(a.Sessions, sess.ID)
+				a.sessMu.Unlock()
+				logger.Warn("💰 [%s] Bütçe sınırı: %v", sess.ID, err)
+				return fmt.Sprintf("🛑 [%s] Bütçe sınırına takıldım patron: %v. Görev durduruldu. (Detay için 'rick_control' ile 'usage' raporuna bak.)", sess.ID, err), nil
+			}
+		}
+
 		a.manageContextWindow(sess)
 
 		tools := a.Skills.ListTools()
@@ -177,6 +223,14 @@ func (a *Rick) Run(ctx context.Context, input string, images []string) (string,
 			return "", err
 		}
 
+		if a.Usage != nil {
+			model := resp.Model
+			if model == "" {
+				model = a.Config.Brain.Primary.ModelName
+			}
+			a.Usage.Record(sess.ID, conversationID, model, resp.Usage)
+		}
+
 		if len(resp.ToolCalls) == 0 {
 			jsonStr := a.extractJSON(resp.Content)
 			if jsonStr != "" {
@@ -221,7 +275,7 @@ func (a *Rick) Run(ctx context.Context, input string, images []string) (string,
 			if resp.Content != "" {
 				logger.Success("🤖 Rick [%s]: İşlem Tamamlandı.", sess.ID)
 				
-				go a.Memory.Add(context.Background(), fmt.Sprintf("User: %s | Rick: %s", input, resp.Content), nil)
+				go a.rememberExchange(conversationID, input, resp.Content)
 				
 				a.sessMu.Lock()
 				delete(a.Sessions, sess.ID)
@@ -242,25 +296,55 @@ func (a *Rick) Run(ctx context.Context, input string, images []string) (string,
 			logger.Action("🛠️ [%s] Çalıştırılıyor: %s", sess.ID, call.Function)
 			
 			// 🛡️ Araç çalışırken de iptal kablosunu (sessCtx) içeri yolluyoruz
-			toolOutput, err := a.executeToolSafe(sessCtx, call)
+			result, err := a.executeToolSafe(sessCtx, call)
 
 			// Araç çalışırken iptal sinyali gelmişse, sonucu boşver ve çık.
 			if sessCtx.Err() != nil {
 				return fmt.Sprintf("🛑 [%s] İşlem araç çalıştırılırken iptal edildi.", sess.ID), nil
 			}
 
+			var toolOutput string
+			var images []string
 			if err != nil {
 				logger.Warn("⚠️ [%s] Araç Hatası: %v", sess.ID, err)
 				toolOutput = fmt.Sprintf("❌ ÇALIŞTIRMA HATASI: %v\nLütfen hatayı analiz et ve gerekiyorsa düzelt.", err)
 			}
+			if result != nil {
+				if err == nil {
+					toolOutput = formatToolResult(result)
+					if !result.OK() {
+						logger.Warn("⚠️ [%s] %s sonucu: %s", sess.ID, call.Function, strings.ToUpper(result.Status))
+					}
+				}
+				// 📸 GÖRSEL GERİ BİLDİRİM: Aracın ürettiği görselleri modele göster
+				images = a.encodeToolImages(result.Artifacts)
+			}
 
-			sess.mu.Lock()
-			sess.History = append(sess.History, kernel.Message{
+			toolMsg := kernel.Message{
 				Role:       "tool",
 				Content:    toolOutput,
 				Name:       call.Function,
 				ToolCallID: call.ID,
-			})
+			}
+
+			sess.mu.Lock()
+			if len(images) > 0 && a.brainSupportsToolImages() {
+				toolMsg.Images = images
+				sess.History = append(sess.History, toolMsg)
+			} else if len(images) > 0 {
+				// Araç sonucuna görsel koyamayan sağlayıcılar için görseller ayrı kullanıcı mesajıyla gider
+				sess.History = append(sess.History, toolMsg, kernel.Message{
+					Role:    "user",
+					Content: fmt.Sprintf("[SİSTEM: '%s' aracının ürettiği %d görsel ektedir. Bunları inceleyerek devam et.]", call.Function, len(images)),
+					Images:  images,
+				})
+			} else {
+				sess.History = append(sess.History, toolMsg)
+			}
+			if len(images) > 0 {
+				logger.Info("📸 [%s] %s aracından %d görsel modele iletildi.", sess.ID, call.Function, len(images))
+				a.capHistoryImages(sess)
+			}
 			sess.mu.Unlock()
 		}
 	}
@@ -286,21 +370,59 @@ func (a *Rick) extractJSON(content string) string {
 	return ""
 }
 
-func (a *Rick) executeToolSafe(ctx context.Context, call kernel.ToolCall) (string, error) {
+// executeToolSafe: Aracı çalıştırma zincirinden geçirir; araçtaki panik süreci değil sadece bu çağrıyı düşürür.
+func (a *Rick) executeToolSafe(ctx context.Context, call kernel.ToolCall) (*kernel.ToolResult, error) {
 	tool, err := a.Skills.GetTool(call.Function)
 	if err != nil {
-		return "", fmt.Errorf("'%s' adında bir araç sistemde kayıtlı değil", call.Function)
+		return nil, fmt.Errorf("'%s' adında bir araç sistemde kayıtlı değil", call.Function)
+	}
+	return a.Pipeline.Run(ctx, tool, call.ID, call.Arguments)
+}
+
+// formatToolResult: Yapısal sonucu modelin okuyacağı metne çevirir (durum, veri ve dosyalar dahil).
+func formatToolResult(res *kernel.ToolResult) string {
+	var sb strings.Builder
+	switch res.Status {
+	case kernel.ToolStatusFailed:
+		sb.WriteString("⚠️ [DURUM: BAŞARISIZ] Araç çalıştı ancak iş başarıyla tamamlanamadı.\n")
+	case kernel.ToolStatusError:
+		sb.WriteString("❌ [DURUM: HATA] Araç işlemi gerçekleştiremedi.\n")
+	}
+	sb.WriteString(res.Text)
+
+	if len(res.Data) > 0 {
+		if data, err := json.Marshal(res.Data); err == nil {
+			sb.WriteString("\n📦 VERİ: " + string(data))
+		}
+	}
+	for _, art := range res.Artifacts {
+		if art.Kind == "file" && art.Path != "" {
+			sb.WriteString("\n📎 Dosya: " + art.Path)
+		}
+	}
+	return sb.String()
+}
+
+// brainSupportsToolImages: Aktif beyin araç sonucu mesajlarına görsel iliştirmeyi destekliyor mu?
+func (a *Rick) brainSupportsToolImages() bool {
+	if s, ok := a.Brain.(kernel.ToolImageSupporter); ok {
+		return s.SupportsToolImages()
 	}
-	return tool.Execute(ctx, call.Arguments)
+	return false
 }
 
 func (a *Rick) refreshSystemPrompt(sess *Session) {
 	osContext := fmt.Sprintf("%s (OS: %s, ARCH: %s)", a.Config.App.WorkDir, runtime.GOOS, runtime.GOARCH)
-	sysMsg := BuildSystemPrompt(a.Config.App.ActivePrompt, osContext, a.Config.Security.Level, a.Skills.ListTools())
 
 	sess.mu.Lock()
 	defer sess.mu.Unlock()
 
+	sysMsg := BuildSystemPrompt(a.Config.App.ActivePrompt, osContext, a.Config.Security.Level, a.Skills.ListTools(), sess.Facts)
+
+	if sess.Recall != "" {
+		sysMsg.Content += "\n\n" + sess.Recall
+	}
+
 	if len(sess.History) == 0 {
 		sess.History = append([]kernel.Message{sysMsg}, sess.History...)
 	} else if sess.History[0].Role == "system" {
@@ -0,0 +1,13 @@
+package agent
+
+import "testing"
+
+func TestCreateSessionUniqueIDs(t *testing.T) {
+	r := &Rick{Sessions: make(map[string]*Session)}
+	for i := 0; i < 10000; i++ {
+		r.createSession(nil)
+	}
+	if len(r.Sessions) != 10000 {
+		t.Fatalf("10000 oturumdan %d benzersiz ID çıktı", len(r.Sessions))
+	}
+}
@@ -0,0 +1,196 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // PNG ekran görüntülerini çözebilmek için
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+const (
+	defaultMaxImageDimension = 1024
+	defaultMaxHistoryImages  = 4
+	maxToolImageBytes        = 20 << 20   // Daha büyük görsel dosyaları okunmaz
+	maxToolImagePixels       = 50_000_000 // Çözmeden önce denetlenir (Sıkıştırma bombalarına karşı)
+)
+
+// encodeToolImages: Aracın ürettiği görsel çıktıları küçültüp modele gidecek base64 JPEG'lere çevirir.
+func (a *Rick) encodeToolImages(artifacts []kernel.Artifact) []string {
+	maxDim := a.Config.App.MaxImageDimension
+	if maxDim <= 0 {
+		maxDim = defaultMaxImageDimension
+	}
+
+	var images []string
+	for _, art := range artifacts {
+		if art.Kind != "image" {
+			continue
+		}
+		data := art.Data
+		if len(data) == 0 && art.Path != "" {
+			raw, err := a.readToolImage(art.Path)
+			if err != nil {
+				logger.Warn("⚠️ Araç görseli okunamadı (%s): %v", art.Path, err)
+				continue
+			}
+			data = raw
+		}
+		encoded, err := downscaleImage(data, maxDim)
+		if err != nil {
+			logger.Warn("⚠️ Araç görseli işlenemedi (%s): %v", art.Path, err)
+			continue
+		}
+		images = append(images, encoded)
+	}
+	return images
+}
+
+// readToolImage: Araç görselini sadece çalışma klasörünün veya geçici klasörün içindeyse ve boyutu sınırı aşmıyorsa okur.
+func (a *Rick) readToolImage(path string) ([]byte, error) {
+	target, err := filepath.Abs(path)
+	if err == nil {
+		target, err = filepath.EvalSymlinks(target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	workDir := a.Config.App.WorkDir
+	if workDir == "" {
+		workDir = "."
+	}
+	allowed := false
+	for _, root := range []string{workDir, os.TempDir()} {
+		if root, err = filepath.Abs(root); err != nil {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			root = resolved
+		}
+		if rel, err := filepath.Rel(root, target); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("yol çalışma klasörünün ve geçici klasörün dışında")
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxToolImageBytes {
+		return nil, fmt.Errorf("dosya çok büyük (%d bayt)", info.Size())
+	}
+	return os.ReadFile(target)
+}
+
+// downscaleImage: Görseli uzun kenarı maxDim olacak şekilde (kutu filtresiyle) küçültür ve JPEG olarak kodlar.
+func downscaleImage(data []byte, maxDim int) (string, error) {
+	if len(data) > maxToolImageBytes {
+		return "", fmt.Errorf("görsel çok büyük (%d bayt)", len(data))
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("görsel çözülemedi: %v", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxToolImagePixels {
+		return "", fmt.Errorf("görsel boyutu kabul edilemez (%dx%d)", cfg.Width, cfg.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("görsel çözülemedi: %v", err)
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := src
+	if w > maxDim || h > maxDim {
+		nw, nh := maxDim, h*maxDim/w
+		if h > w {
+			nw, nh = w*maxDim/h, maxDim
+		}
+		if nw < 1 {
+			nw = 1
+		}
+		if nh < 1 {
+			nh = 1
+		}
+		dst = boxResize(src, nw, nh)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// boxResize: Her hedef pikseli, kaynaktaki karşılık gelen alanın ortalaması ile doldurur.
+func boxResize(src image.Image, nw, nh int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, nw, nh))
+
+	for y := 0; y < nh; y++ {
+		y0 := b.Min.Y + y*h/nh
+		y1 := b.Min.Y + (y+1)*h/nh
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < nw; x++ {
+			x0 := b.Min.X + x*w/nw
+			x1 := b.Min.X + (x+1)*w/nw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, bl, al, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, al = r+uint64(cr), g+uint64(cg), bl+uint64(cb), al+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(al / n)})
+		}
+	}
+	return dst
+}
+
+// capHistoryImages: Bağlam şişmesin diye geçmişte sadece en yeni N görseli tutar, eskileri not ile değiştirir.
+// Kilit altında çağrılmalıdır.
+func (a *Rick) capHistoryImages(sess *Session) {
+	limit := a.Config.App.MaxHistoryImages
+	if limit <= 0 {
+		limit = defaultMaxHistoryImages
+	}
+
+	kept := 0
+	for i := len(sess.History) - 1; i >= 0; i-- {
+		msg := &sess.History[i]
+		if len(msg.Images) == 0 {
+			continue
+		}
+		if kept+len(msg.Images) <= limit {
+			kept += len(msg.Images)
+			continue
+		}
+		keep := limit - kept
+		if keep < 0 {
+			keep = 0
+		}
+		dropped := len(msg.Images) - keep
+		msg.Images = msg.Images[len(msg.Images)-keep:]
+		kept += keep
+		msg.Content += fmt.Sprintf("\n[SİSTEM NOTU: Bağlamı korumak için %d eski görsel geçmişten kaldırıldı.]", dropped)
+	}
+}
@@ -0,0 +1,204 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// DefaultPath: Config'te yol verilmemişse kullanılan denetim kaydı
+const DefaultPath = "logs/audit.jsonl"
+
+// maxLine: Okurken kabul edilen en uzun satır (Argümanlar zaten kısaltılarak yazılır)
+const maxLine = 4 << 20
+
+// Sonuç durumları (kernel.ToolStatus* + politikanın/onayın reddettikleri)
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusError   = "error"
+	StatusDenied  = "denied"
+)
+
+// Entry: Tek bir araç çağrısının kaydı. Hash; Prev dahil tüm alanların özetidir, böylece her kayıt
+// bir öncekine zincirlenir ve aradan satır silmek/değiştirmek zinciri kırar.
+type Entry struct {
+	Seq          int64           `json:"seq"`
+	Time         string          `json:"time"` // RFC3339Nano, UTC
+	Session      string          `json:"session,omitempty"`
+	Conversation string          `json:"conversation,omitempty"`
+	Actor        string          `json:"actor"`
+	CallID       string          `json:"call_id,omitempty"`
+	Tool         string          `json:"tool"`
+	Args         json.RawMessage `json:"args"` // Gizli değerleri maskelenmiş
+	Status       string          `json:"status"`
+	Error        string          `json:"error,omitempty"`
+	DurationMs   int64           `json:"duration_ms"`
+	OutputBytes  int64           `json:"output_bytes"`
+	Prev         string          `json:"prev"`
+	Hash         string          `json:"hash,omitempty"`
+}
+
+// When: Kaydın zamanı (Bozuksa sıfır zaman)
+func (e *Entry) When() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, e.Time)
+	return t
+}
+
+// computeHash: Hash alanı boşken kaydın JSON'unun SHA-256 özeti
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log: Sadece sona eklenen, hash zincirli JSONL denetim kaydı. Dosya başına tek yazar varsayılır;
+// dosya dışarıdan büyümüşse (Başka bir süreç) yazmadan önce zincirin ucu yeniden okunur.
+type Log struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	seq  int64
+	head string // Son kaydın hash'i
+	size int64  // Bizim bildiğimiz dosya boyu
+}
+
+// Open: Kaydı açar (Yoksa oluşturur) ve zincirin ucunu bulur.
+func Open(path string) (*Log, error) {
+	if path == "" {
+		path = DefaultPath
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("denetim klasörü oluşturulamadı: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("denetim kaydı açılamadı: %v", err)
+	}
+	l := &Log{path: path, file: f}
+	if err := l.loadTail(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Path: Kaydın dosya yolu
+func (l *Log) Path() string { return l.path }
+
+// loadTail: Dosyadaki son geçerli kaydın sırasını ve hash'ini okur.
+func (l *Log) loadTail() error {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return fmt.Errorf("denetim kaydı okunamadı: %v", err)
+	}
+	defer f.Close()
+
+	l.seq, l.head = 0, ""
+	err = scan(f, func(line int, raw []byte) error {
+		var e Entry
+		if json.Unmarshal(raw, &e) == nil && e.Hash != "" {
+			l.seq, l.head = e.Seq, e.Hash
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("denetim kaydı okunamadı: %v", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	l.size = info.Size()
+	return nil
+}
+
+// Append: Kaydı zincire ekler; Seq, Time, Prev ve Hash burada doldurulur.
+func (l *Log) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("denetim kaydı kapalı")
+	}
+	if info, err := l.file.Stat(); err == nil && info.Size() != l.size {
+		logger.Warn("🧾 Denetim kaydı dışarıdan değişmiş (%s), zincirin ucu yeniden okunuyor.", l.path)
+		if err := l.loadTail(); err != nil {
+			return err
+		}
+	}
+
+	e.Seq = l.seq + 1
+	if e.Time == "" {
+		e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	if len(e.Args) == 0 {
+		e.Args = json.RawMessage("{}")
+	}
+	e.Prev = l.head
+	hash, err := e.computeHash()
+	if err != nil {
+		return fmt.Errorf("denetim kaydı özetlenemedi: %v", err)
+	}
+	e.Hash = hash
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	n, err := l.file.Write(append(data, '\n'))
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("denetim kaydı yazılamadı: %v", err)
+	}
+	l.seq, l.head = e.Seq, e.Hash
+	return nil
+}
+
+// Head: Zincirin son sırası ve hash'i (Dışarıda saklanırsa sondan kesilmeyi de ortaya çıkarır)
+func (l *Log) Head() (int64, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq, l.head
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// scan: Dosyayı satır satır gezer (Boş satırlar atlanır, satır numaraları 1'den başlar).
+func scan(r io.Reader, fn func(line int, raw []byte) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxLine)
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := sc.Bytes()
+		if strings.TrimSpace(string(raw)) == "" {
+			continue
+		}
+		if err := fn(line, raw); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
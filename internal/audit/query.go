@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+)
+
+// Filter: Sorgu süzgeci (Boş alanlar süzmez)
+type Filter struct {
+	Tool         string // Araç adı kalıbı (path.Match, Örn: "fs_*")
+	Session      string
+	Conversation string
+	Actor        string
+	Status       string
+	Since        time.Time
+	Until        time.Time
+	Limit        int // Doluysa eşleşen son N kayıt
+}
+
+func (f Filter) match(e *Entry) bool {
+	if f.Tool != "" {
+		if ok, _ := path.Match(f.Tool, e.Tool); !ok {
+			return false
+		}
+	}
+	if (f.Session != "" && e.Session != f.Session) ||
+		(f.Conversation != "" && e.Conversation != f.Conversation) ||
+		(f.Actor != "" && e.Actor != f.Actor) ||
+		(f.Status != "" && e.Status != f.Status) {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		t := e.When()
+		if (!f.Since.IsZero() && t.Before(f.Since)) || (!f.Until.IsZero() && t.After(f.Until)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Query: Süzgece uyan kayıtları eskiden yeniye döner (Bozuk satırlar atlanır; onlar için Verify kullan).
+func Query(file string, f Filter) ([]Entry, error) {
+	if f.Tool != "" {
+		if _, err := path.Match(f.Tool, ""); err != nil {
+			return nil, fmt.Errorf("geçersiz araç kalıbı: '%s'", f.Tool)
+		}
+	}
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("denetim kaydı açılamadı: %v", err)
+	}
+	defer fh.Close()
+
+	var out []Entry
+	err = scan(fh, func(line int, raw []byte) error {
+		var e Entry
+		if json.Unmarshal(raw, &e) != nil || !f.match(&e) {
+			return nil
+		}
+		out = append(out, e)
+		if f.Limit > 0 && len(out) > 2*f.Limit {
+			out = append(out[:0:0], out[len(out)-f.Limit:]...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("denetim kaydı okunamadı: %v", err)
+	}
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[len(out)-f.Limit:]
+	}
+	return out, nil
+}
@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted: Maskelenen değerlerin yerine yazılan metin
+const Redacted = "***"
+
+// maxArgValue: Kayda yazılan tek bir metin argümanının en fazla uzunluğu (Örn: fs_write içeriği)
+const maxArgValue = 2000
+
+// secretKeys: Adında bunlardan biri geçen argümanların değeri tamamen maskelenir.
+var secretKeys = []string{"password", "passwd", "passphrase", "secret", "token", "apikey", "api_key", "authorization", "credential", "private_key", "cookie"}
+
+// secretPatterns: Serbest metnin (Komut, betik) içine gömülü gizli değerler
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)(password|passwd|pwd|passphrase|secret|token|api[_-]?key|access[_-]?key)(["']?\s*[=:]\s*["']?)[^\s"'&;]+`), "${1}${2}" + Redacted},
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]{8,}`), "${1} " + Redacted},
+	{regexp.MustCompile(`\b(sk-[A-Za-z0-9_-]{16,}|gh[pousr]_[A-Za-z0-9]{20,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{30,})\b`), Redacted},
+	{regexp.MustCompile(`(?i)(://[^/\s:@]+:)[^@\s/]+@`), "${1}" + Redacted + "@"},
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), Redacted},
+}
+
+// RedactArgs: Argümanların gizli değerleri maskelenmiş, uzun metinleri kısaltılmış JSON kopyası
+func RedactArgs(args map[string]interface{}) json.RawMessage {
+	if len(args) == 0 {
+		return json.RawMessage("{}")
+	}
+	data, err := json.Marshal(redactValue("", args))
+	if err != nil {
+		return json.RawMessage(fmt.Sprintf(`{"_error":%q}`, err.Error()))
+	}
+	return data
+}
+
+// RedactText: Serbest metindeki gizli değerleri maskeler (Hata mesajları için).
+func RedactText(s string) string {
+	for _, p := range secretPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+func redactValue(key string, v interface{}) interface{} {
+	if isSecretKey(key) {
+		if v == nil || v == "" {
+			return v
+		}
+		return Redacted
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = redactValue(k, item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redactValue(key, item)
+		}
+		return out
+	case []string:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redactValue(key, item)
+		}
+		return out
+	case string:
+		return clip(RedactText(val))
+	}
+	return v
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// clip: Uzun metni kısaltır ve kaç karakter atıldığını not eder.
+func clip(s string) string {
+	if len(s) <= maxArgValue {
+		return s
+	}
+	cut := maxArgValue
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s…(+%d bayt)", s[:cut], len(s)-cut)
+}
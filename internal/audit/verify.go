@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Problem: Zincirde bulunan tek bir kırılma
+type Problem struct {
+	Line   int
+	Seq    int64
+	Reason string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("seq %d: %s", p.Seq, p.Reason)
+	}
+	return fmt.Sprintf("satır %d (seq %d): %s", p.Line, p.Seq, p.Reason)
+}
+
+// Report: Doğrulama sonucu
+type Report struct {
+	Path     string
+	Entries  int
+	LastSeq  int64
+	Head     string // Son kaydın hash'i
+	Problems []Problem
+}
+
+// OK: Zincir baştan sona sağlam mı?
+func (r *Report) OK() bool { return len(r.Problems) == 0 }
+
+// Verify: Her kaydın hash'ini yeniden hesaplar, sıra numaralarını ve bir önceki kayda bağlantıyı denetler.
+// Kırılmadan sonra doğrulama o kaydın kendi hash'iyle sürer; böylece tüm kırılmalar tek seferde raporlanır.
+// expectHead doluysa (Daha önce dışarıda saklanan uç hash) zincirde bulunmalıdır: Sondan silinen kayıtlar ancak böyle fark edilir.
+func Verify(path, expectHead string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("denetim kaydı açılamadı: %v", err)
+	}
+	defer f.Close()
+
+	rep := &Report{Path: path}
+	var prevSeq int64
+	prevHash := ""
+	headSeen := expectHead == ""
+	err = scan(f, func(line int, raw []byte) error {
+		rep.Entries++
+		var e Entry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			rep.Problems = append(rep.Problems, Problem{Line: line, Seq: prevSeq + 1, Reason: "bozuk JSON: " + err.Error()})
+			prevSeq++
+			prevHash = ""
+			return nil
+		}
+		if e.Seq != prevSeq+1 {
+			rep.Problems = append(rep.Problems, Problem{Line: line, Seq: e.Seq, Reason: fmt.Sprintf("sıra numarası %d bekleniyordu (Kayıt silinmiş veya eklenmiş)", prevSeq+1)})
+		}
+		if e.Prev != prevHash && !(prevHash == "" && rep.Entries > 1) {
+			rep.Problems = append(rep.Problems, Problem{Line: line, Seq: e.Seq, Reason: "önceki kaydın hash'iyle eşleşmiyor (Zincir kırık)"})
+		}
+		want, err := e.computeHash()
+		if err != nil || want != e.Hash {
+			rep.Problems = append(rep.Problems, Problem{Line: line, Seq: e.Seq, Reason: "içerik hash'iyle eşleşmiyor (Kayıt değiştirilmiş)"})
+		}
+		if !headSeen && strings.EqualFold(e.Hash, expectHead) {
+			headSeen = true
+		}
+		prevSeq, prevHash = e.Seq, e.Hash
+		rep.LastSeq, rep.Head = e.Seq, e.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("denetim kaydı okunamadı: %v", err)
+	}
+	if !headSeen {
+		rep.Problems = append(rep.Problems, Problem{Line: 0, Seq: rep.LastSeq, Reason: fmt.Sprintf("beklenen uç hash (%s) zincirde yok (Kayıtlar sondan silinmiş olabilir)", expectHead)})
+	}
+	return rep, nil
+}
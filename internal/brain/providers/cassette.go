@@ -0,0 +1,324 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// Kaset Modları
+const (
+	CassetteRecord      = "record"      // Kasette varsa oradan, yoksa canlı beyne sor ve kaydet
+	CassetteReplay      = "replay"      // Sadece kasetten oynat, ağa ASLA çıkma
+	CassettePassthrough = "passthrough" // Her zaman canlı beyne sor ve kaydı ezerek yeniden kaydet
+)
+
+// CassetteEntry: Kasetteki tek bir istek/cevap kaydı
+type CassetteEntry struct {
+	Kind       string                `json:"kind"` // chat | embed
+	Request    json.RawMessage       `json:"request"`
+	Response   *kernel.BrainResponse `json:"response,omitempty"`
+	Embedding  []float32             `json:"embedding,omitempty"`
+	Error      string                `json:"error,omitempty"` // Eski kasetlerden kalma; artık yazılmaz, okunursa kayıt yok sayılır
+	RecordedAt time.Time             `json:"recorded_at"`
+}
+
+// CassetteBrain: Herhangi bir kernel.Brain'i sarmalayıp Chat/Embed trafiğini kasete kaydeden
+// veya kasetten deterministik olarak oynatan beyin.
+type CassetteBrain struct {
+	Inner    kernel.Brain // Canlı beyin (replay modunda nil olabilir)
+	Mode     string
+	FilePath string
+	Model    string // Sohbet modeli; canlı beyin sohbetin modelini bildirmiyorsa anahtara bu girer
+
+	embeddingModel string // Embedding modeli (Anahtara girer; canlı beyin yokken de aynı anahtar üretilsin)
+	entries        map[string]CassetteEntry
+	mu             sync.Mutex
+}
+
+// NewCassette: Kaseti diskten yükler ve sarmalayıcıyı hazırlar. model ve embeddingModel istek anahtarlarına girer;
+// böylece canlı beyin olmadan (replay) üretilen anahtarlar kayıttakilerle aynı olur.
+func NewCassette(inner kernel.Brain, mode, path, model, embeddingModel string) (*CassetteBrain, error) {
+	switch mode {
+	case CassetteRecord, CassetteReplay, CassettePassthrough:
+	default:
+		return nil, fmt.Errorf("bilinmeyen kaset modu: '%s' (Desteklenenler: record, replay, passthrough)", mode)
+	}
+	if mode != CassetteReplay && inner == nil {
+		return nil, fmt.Errorf("'%s' modu için canlı bir beyin gereklidir", mode)
+	}
+
+	c := &CassetteBrain{
+		Inner:          inner,
+		Mode:           mode,
+		FilePath:       path,
+		Model:          model,
+		embeddingModel: embeddingModel,
+		entries:        make(map[string]CassetteEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, &c.entries); err != nil {
+			return nil, fmt.Errorf("kaset dosyası bozuk (%s): %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("kaset dosyası okunamadı: %v", err)
+	} else if mode == CassetteReplay {
+		return nil, fmt.Errorf("replay modu için kaset dosyası bulunamadı: %s", path)
+	}
+
+	logger.Info("📼 Kaset Beyin [%s]: %s (%d kayıt)", mode, path, len(c.entries))
+	return c, nil
+}
+
+// -- Normalize Edilmiş İstek Yapıları (Hash anahtarı bunlardan üretilir) --
+
+type cassetteTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+type cassetteChatRequest struct {
+	Model   string           `json:"model,omitempty"` // Sohbetin etkin modeli
+	History []kernel.Message `json:"history"`
+	Tools   []cassetteTool   `json:"tools"`
+}
+
+// activeModeler: Sohbet başına model seçebilen beyinler (Ollama)
+type activeModeler interface {
+	ActiveModel(ctx context.Context) string
+}
+
+type cassetteEmbedRequest struct {
+	Model string `json:"model,omitempty"` // Vektörü üreten model (Model değişince eski vektörler oynatılmasın)
+	Text  string `json:"text"`
+}
+
+// normalizeHistory: Aynı konuşmanın her seferinde aynı anahtarı üretmesi için mesajları sadeleştirir.
+func normalizeHistory(history []kernel.Message) []kernel.Message {
+	out := make([]kernel.Message, len(history))
+	for i, m := range history {
+		m.Content = strings.TrimSpace(m.Content)
+
+		// Görseller kasette yer kaplamasın, sadece özetleri (hash) anahtara girsin
+		if len(m.Images) > 0 {
+			imgs := make([]string, len(m.Images))
+			for j, img := range m.Images {
+				imgs[j] = "sha256:" + hashBytes([]byte(img))
+			}
+			m.Images = imgs
+		}
+
+		// Araç çağrı ID'leri sağlayıcıya göre değişebilir, anahtardan çıkar
+		m.ToolCallID = ""
+		if len(m.ToolCalls) > 0 {
+			calls := make([]kernel.ToolCall, len(m.ToolCalls))
+			for j, tc := range m.ToolCalls {
+				tc.ID = ""
+				calls[j] = tc
+			}
+			m.ToolCalls = calls
+		}
+		out[i] = m
+	}
+	return out
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// requestKey: İsteği JSON'a çevirip (map anahtarları sıralı) SHA-256 özetini çıkarır.
+func requestKey(kind string, req interface{}) (string, json.RawMessage, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("kaset isteği paketlenemedi: %v", err)
+	}
+	return kind + ":" + hashBytes(data), data, nil
+}
+
+// Chat: İsteği kasetten oynatır veya canlı beyne sorup kaydeder.
+func (c *CassetteBrain) Chat(ctx context.Context, history []kernel.Message, tools []kernel.Tool) (*kernel.BrainResponse, error) {
+	req := cassetteChatRequest{Model: c.chatModel(ctx), History: normalizeHistory(history)}
+	for _, t := range tools {
+		req.Tools = append(req.Tools, cassetteTool{Name: t.Name(), Description: t.Description(), Parameters: t.Parameters()})
+	}
+
+	key, raw, err := requestKey("chat", req)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, ok := c.lookup(key); ok {
+		resp := *entry.Response
+		return &resp, nil
+	}
+	if c.Mode == CassetteReplay {
+		return nil, c.missError(key)
+	}
+
+	// Hatalar kasete yazılmaz: Geçici bir ağ hatası sonraki çalıştırmalarda da tekrar oynatılmasın
+	resp, err := c.Inner.Chat(ctx, history, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	c.record(key, CassetteEntry{Kind: "chat", Request: raw, Response: resp})
+	return resp, nil
+}
+
+// Embed: Embedding isteğini kasetten oynatır veya canlı beyne sorup kaydeder.
+func (c *CassetteBrain) Embed(ctx context.Context, text string) ([]float32, error) {
+	return c.embed(ctx, cassetteEmbedRequest{Model: c.embedKeyModel(), Text: strings.TrimSpace(text)}, func() ([]float32, error) {
+		return c.Inner.Embed(ctx, text)
+	})
+}
+
+// EmbedWithModel: Belirli modelle embedding isteğini kasetten oynatır veya kaydeder.
+func (c *CassetteBrain) EmbedWithModel(ctx context.Context, model, text string) ([]float32, error) {
+	return c.embed(ctx, cassetteEmbedRequest{Model: model, Text: strings.TrimSpace(text)}, func() ([]float32, error) {
+		m, ok := c.Inner.(kernel.EmbeddingModeler)
+		if !ok {
+			return nil, fmt.Errorf("sarmalanan beyin model seçerek embedding üretemiyor")
+		}
+		return m.EmbedWithModel(ctx, model, text)
+	})
+}
+
+func (c *CassetteBrain) embed(ctx context.Context, req cassetteEmbedRequest, live func() ([]float32, error)) ([]float32, error) {
+	key, raw, err := requestKey("embed", req)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, ok := c.lookup(key); ok {
+		return entry.Embedding, nil
+	}
+	if c.Mode == CassetteReplay {
+		return nil, c.missError(key)
+	}
+
+	vec, err := live()
+	if err != nil {
+		return nil, err
+	}
+
+	c.record(key, CassetteEntry{Kind: "embed", Request: raw, Embedding: vec})
+	return vec, nil
+}
+
+// chatModel: Sohbetin etkin modeli (Sohbet başına model seçebilen beyinlerde onun cevabı, yoksa Model)
+func (c *CassetteBrain) chatModel(ctx context.Context) string {
+	if m, ok := c.Inner.(activeModeler); ok {
+		if model := m.ActiveModel(ctx); model != "" {
+			return model
+		}
+	}
+	return c.Model
+}
+
+// EmbeddingModel: Sarmalanan beynin embedding modelini yansıtır (replay'de canlı beyin yoksa kasetin bildiği model).
+func (c *CassetteBrain) EmbeddingModel() string {
+	if m, ok := c.Inner.(kernel.EmbeddingModeler); ok {
+		return m.EmbeddingModel()
+	}
+	if c.Inner == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.embeddingModel
+	}
+	return ""
+}
+
+// embedKeyModel: Embed anahtarındaki model (Canlı beyin modelini bildirmiyorsa kasetin bildiği model)
+func (c *CassetteBrain) embedKeyModel() string {
+	if model := c.EmbeddingModel(); model != "" {
+		return model
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.embeddingModel
+}
+
+// SetEmbeddingModel: Sarmalanan beynin ve kasetin embedding modelini değiştirir.
+func (c *CassetteBrain) SetEmbeddingModel(model string) {
+	c.mu.Lock()
+	c.embeddingModel = model
+	c.mu.Unlock()
+	if m, ok := c.Inner.(kernel.EmbeddingModeler); ok {
+		m.SetEmbeddingModel(model)
+	}
+}
+
+// SupportsToolImages: Sarmalanan beynin görsel yeteneğini aynen yansıtır (replay'de de aynı akış oluşsun).
+func (c *CassetteBrain) SupportsToolImages() bool {
+	if s, ok := c.Inner.(kernel.ToolImageSupporter); ok {
+		return s.SupportsToolImages()
+	}
+	return false
+}
+
+// lookup: Passthrough modunda kasete hiç bakılmaz, her istek yeniden kaydedilir.
+// Hata kayıtlı (eski) girdiler bulunmamış sayılır; record modunda canlı beyne yeniden sorulur.
+func (c *CassetteBrain) lookup(key string) (CassetteEntry, bool) {
+	if c.Mode == CassettePassthrough {
+		return CassetteEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || entry.Error != "" || (entry.Kind == "chat" && entry.Response == nil) {
+		return CassetteEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *CassetteBrain) missError(key string) error {
+	logger.Warn("📼 Kasette kayıt yok: %s", key)
+	return fmt.Errorf("kaset kaydı bulunamadı (%s): bu istek '%s' içinde kayıtlı değil. Yeniden kaydetmek için 'record' veya 'passthrough' modunu kullan", key, c.FilePath)
+}
+
+// record: Kaydı ekler ve kaseti atomik olarak diske yazar.
+func (c *CassetteBrain) record(key string, entry CassetteEntry) {
+	entry.RecordedAt = time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+	if err := c.save(); err != nil {
+		logger.Warn("⚠️ Kaset diske yazılamadı: %v", err)
+		return
+	}
+	logger.Debug("📼 Kasete kaydedildi: %s", key)
+}
+
+func (c *CassetteBrain) save() error {
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.FilePath); dir != "" {
+		os.MkdirAll(dir, 0755)
+	}
+
+	// 🚀 ATOMIC WRITE
+	tempPath := c.FilePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, c.FilePath)
+}
@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+// liveBrain: Cevabına o anki embedding modelini yazan test beyni
+type liveBrain struct{ embedModel string }
+
+func (b *liveBrain) Chat(ctx context.Context, history []kernel.Message, tools []kernel.Tool) (*kernel.BrainResponse, error) {
+	return &kernel.BrainResponse{Content: "merhaba"}, nil
+}
+
+func (b *liveBrain) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(b.embedModel))}, nil
+}
+
+func (b *liveBrain) EmbeddingModel() string         { return b.embedModel }
+func (b *liveBrain) SetEmbeddingModel(model string) { b.embedModel = model }
+func (b *liveBrain) EmbedWithModel(ctx context.Context, model, text string) ([]float32, error) {
+	return []float32{float32(len(model))}, nil
+}
+
+func TestCassetteReplayKeys(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	history := []kernel.Message{{Role: "user", Content: "selam"}}
+
+	rec, err := NewCassette(&liveBrain{embedModel: "nomic"}, CassetteRecord, path, "qwen3:8b", "nomic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Chat(ctx, history, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Embed(ctx, "metin"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		model      string
+		embedModel string
+		chatHit    bool
+		embedHit   bool
+	}{
+		{"aynı modeller canlı beyin olmadan oynar", "qwen3:8b", "nomic", true, true},
+		{"sohbet modeli değişince sohbet kaçar", "llama3", "nomic", false, true},
+		{"embedding modeli değişince vektör kaçar", "qwen3:8b", "mxbai", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, err := NewCassette(nil, CassetteReplay, path, tt.model, tt.embedModel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := replay.Chat(ctx, history, nil); (err == nil) != tt.chatHit {
+				t.Fatalf("sohbet bulundu = %v, beklenen %v (%v)", err == nil, tt.chatHit, err)
+			}
+			if _, err := replay.Embed(ctx, "metin"); (err == nil) != tt.embedHit {
+				t.Fatalf("vektör bulundu = %v, beklenen %v (%v)", err == nil, tt.embedHit, err)
+			}
+		})
+	}
+
+	// Göç sonrası SetEmbeddingModel ile seçilen model anahtara girer
+	replay, err := NewCassette(nil, CassetteReplay, path, "qwen3:8b", "mxbai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay.SetEmbeddingModel("nomic")
+	if _, err := replay.Embed(ctx, "metin"); err != nil {
+		t.Fatalf("SetEmbeddingModel sonrası kayıt bulunmalıydı: %v", err)
+	}
+}
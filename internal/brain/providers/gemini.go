@@ -181,6 +181,11 @@ func (g *GeminiProvider) Chat(ctx context.Context, history []kernel.Message, too
 				Parts []part `json:"parts"`
 			} `json:"content"`
 		} `json:"candidates"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+			TotalTokenCount      int `json:"totalTokenCount"`
+		} `json:"usageMetadata"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
@@ -190,7 +195,10 @@ func (g *GeminiProvider) Chat(ctx context.Context, history []kernel.Message, too
 		return nil, fmt.Errorf("gemini boş cevap döndü")
 	}
 
-	brainResp := &kernel.BrainResponse{}
+	brainResp := &kernel.BrainResponse{
+		Usage: newUsage(result.UsageMetadata.PromptTokenCount, result.UsageMetadata.CandidatesTokenCount, result.UsageMetadata.TotalTokenCount),
+		Model: g.Model,
+	}
 
 	for _, p := range result.Candidates[0].Content.Parts {
 		if p.Text != "" {
@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
@@ -17,6 +19,10 @@ type OllamaProvider struct {
 	Temperature float64      // 🚀 YENİ: Config'den gelecek sıcaklık
 	NumCtx      int          // 🚀 YENİ: Config'den gelecek token limiti
 	Client      *http.Client
+	EmbedModel  string // Embedding modeli; sohbet modeli değişse bile hafıza vektörleri tutarlı kalsın diye ayrı
+
+	convModels map[string]string // Sohbete özel model seçimleri (conversation_id -> model)
+	mu         sync.RWMutex
 }
 
 // 🚀 DÜZELTME: Fonksiyona temp ve numCtx parametrelerini ekledik
@@ -32,6 +38,8 @@ func NewOllama(url, model string, temp float64, numCtx int) *OllamaProvider {
 		Temperature: temp,
 		NumCtx:      numCtx,
 		Client:      &http.Client{Timeout: 300 * time.Second},
+		EmbedModel:  model,
+		convModels:  make(map[string]string),
 	}
 }
 
@@ -69,8 +77,10 @@ type ollamaToolCall struct {
 }
 
 type ollamaResponse struct {
-	Message   ollamaMessage `json:"message"`
-	EvalCount int           `json:"eval_count"`
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
 }
 
 // Chat: LLM ile konuşur
@@ -96,9 +106,9 @@ func (o *OllamaProvider) Chat(ctx context.Context, history []kernel.Message, too
 		messages = append(messages, om)
 	}
 
-	// 2. İsteği hazırla
+	// 2. İsteği hazırla (Sohbete özel model seçildiyse onu kullan)
 	reqBody := ollamaRequest{
-		Model:    o.Model,
+		Model:    o.ActiveModel(ctx),
 		Messages: messages,
 		Stream:   false,
 		Options: map[string]interface{}{
@@ -138,7 +148,11 @@ func (o *OllamaProvider) Chat(ctx context.Context, history []kernel.Message, too
 
 	brainResp := &kernel.BrainResponse{
 		Content: result.Message.Content,
-		Usage:   map[string]int{"completion_tokens": result.EvalCount},
+		Usage:   newUsage(result.PromptEvalCount, result.EvalCount, 0),
+		Model:   result.Model,
+	}
+	if brainResp.Model == "" {
+		brainResp.Model = reqBody.Model
 	}
 
 	for _, tc := range result.Message.ToolCalls {
@@ -151,10 +165,18 @@ func (o *OllamaProvider) Chat(ctx context.Context, history []kernel.Message, too
 	return brainResp, nil
 }
 
-// Embed: Metni vektöre çevirir
+// SupportsToolImages: Ollama, araç sonucu mesajlarındaki görselleri doğrudan modele iletir.
+func (o *OllamaProvider) SupportsToolImages() bool { return true }
+
+// Embed: Metni aktif embedding modeliyle vektöre çevirir
 func (o *OllamaProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	return o.EmbedWithModel(ctx, o.EmbeddingModel(), text)
+}
+
+// EmbedWithModel: Metni belirtilen modelle vektöre çevirir (Yeniden embedding göçü için)
+func (o *OllamaProvider) EmbedWithModel(ctx context.Context, model, text string) ([]float32, error) {
 	reqBody := map[string]interface{}{
-		"model":  o.Model,
+		"model":  model,
 		"prompt": text,
 	}
 	jsonData, _ := json.Marshal(reqBody)
@@ -164,11 +186,19 @@ func (o *OllamaProvider) Embed(ctx context.Context, text string) ([]float32, err
 	if err != nil { return nil, err }
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama embedding hatası (%d): %s", resp.StatusCode, string(b))
+	}
+
 	var result struct {
 		Embedding []float32 `json:"embedding"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
+	if len(result.Embedding) == 0 {
+		return nil, fmt.Errorf("'%s' modeli boş embedding döndürdü", model)
+	}
 	return result.Embedding, nil
 }
\ No newline at end of file
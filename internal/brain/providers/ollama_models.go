@@ -0,0 +1,186 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+// OllamaModel: /api/tags cevabındaki tekil yerel model
+type OllamaModel struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+	Details    struct {
+		Family            string `json:"family"`
+		ParameterSize     string `json:"parameter_size"`
+		QuantizationLevel string `json:"quantization_level"`
+	} `json:"details"`
+}
+
+// OllamaModelInfo: /api/show cevabının Rick'i ilgilendiren kısmı
+type OllamaModelInfo struct {
+	Capabilities []string               `json:"capabilities"` // completion, tools, vision, embedding...
+	Details      map[string]interface{} `json:"details"`
+	ModelInfo    map[string]interface{} `json:"model_info"`
+	Parameters   string                 `json:"parameters"`
+}
+
+// HasCapability: Model belirtilen yeteneği (tools, vision vb.) destekliyor mu?
+func (m *OllamaModelInfo) HasCapability(name string) bool {
+	for _, c := range m.Capabilities {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PullProgress: Model indirme sırasında akan ilerleme bilgisi
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+	Error     string `json:"error"`
+}
+
+// GlobalModel: Tüm sohbetler için varsayılan model
+func (o *OllamaProvider) GlobalModel() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.Model
+}
+
+// ActiveModel: Context'teki sohbet için seçilmiş modeli, yoksa global modeli döner.
+func (o *OllamaProvider) ActiveModel(ctx context.Context) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if m, ok := o.convModels[kernel.ConversationFrom(ctx)]; ok {
+		return m
+	}
+	return o.Model
+}
+
+// SetModel: Modeli global olarak veya sadece bir sohbet için değiştirir (conversationID boşsa global).
+func (o *OllamaProvider) SetModel(conversationID, model string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if conversationID == "" {
+		o.Model = model
+		return
+	}
+	o.convModels[conversationID] = model
+}
+
+// ResetModel: Sohbete özel model seçimini kaldırır, sohbet global modele döner.
+func (o *OllamaProvider) ResetModel(conversationID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.convModels, conversationID)
+}
+
+// EmbeddingModel: Hafıza vektörlerini üreten model
+func (o *OllamaProvider) EmbeddingModel() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.EmbedModel
+}
+
+// SetEmbeddingModel: Embedding modelini değiştirir. Eski vektörler yeniden embedding göçüyle taşınmalıdır.
+func (o *OllamaProvider) SetEmbeddingModel(model string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.EmbedModel = model
+}
+
+// ListModels: Yerelde kurulu modelleri listeler (/api/tags).
+func (o *OllamaProvider) ListModels(ctx context.Context) ([]OllamaModel, error) {
+	req, _ := http.NewRequestWithContext(ctx, "GET", o.BaseURL+"/api/tags", nil)
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama model listesi alınamadı: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Models []OllamaModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Models, nil
+}
+
+// ShowModel: Modelin detaylarını ve yeteneklerini getirir (/api/show).
+func (o *OllamaProvider) ShowModel(ctx context.Context, model string) (*OllamaModelInfo, error) {
+	jsonData, _ := json.Marshal(map[string]string{"model": model})
+	req, _ := http.NewRequestWithContext(ctx, "POST", o.BaseURL+"/api/show", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("'%s' modeli yerelde bulunamadı. Önce 'pull' ile indirmelisin", model)
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama model detayı alınamadı (%d): %s", resp.StatusCode, string(b))
+	}
+
+	var info OllamaModelInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// PullModel: Modeli indirir ve her ilerleme satırını callback'e iletir (/api/pull, stream).
+func (o *OllamaProvider) PullModel(ctx context.Context, model string, onProgress func(PullProgress)) error {
+	jsonData, _ := json.Marshal(map[string]interface{}{"model": model, "stream": true})
+	req, _ := http.NewRequestWithContext(ctx, "POST", o.BaseURL+"/api/pull", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	// İndirme dakikalar sürebilir, genel istemci zaman aşımına takılmasın
+	client := &http.Client{Transport: o.Client.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ollama pull hatası (%d): %s", resp.StatusCode, string(b))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var p PullProgress
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			continue
+		}
+		if p.Error != "" {
+			return fmt.Errorf("ollama pull hatası: %s", p.Error)
+		}
+		if onProgress != nil {
+			onProgress(p)
+		}
+	}
+	return scanner.Err()
+}
@@ -95,7 +95,9 @@ func (o *OpenAIProvider) Chat(ctx context.Context, history []kernel.Message, too
 			} `json:"message"`
 		} `json:"choices"`
 		Usage struct {
-			TotalTokens int `json:"total_tokens"`
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
 		} `json:"usage"`
 	}
 
@@ -109,7 +111,8 @@ func (o *OpenAIProvider) Chat(ctx context.Context, history []kernel.Message, too
 
 	return &kernel.BrainResponse{
 		Content: result.Choices[0].Message.Content,
-		Usage:   map[string]int{"total_tokens": result.Usage.TotalTokens},
+		Usage:   newUsage(result.Usage.PromptTokens, result.Usage.CompletionTokens, result.Usage.TotalTokens),
+		Model:   o.Model,
 	}, nil
 }
 
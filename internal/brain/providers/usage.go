@@ -0,0 +1,16 @@
+package providers
+
+import "github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+
+// newUsage: Sağlayıcıların farklı isimlerle döndüğü token sayılarını ortak anahtarlara çevirir.
+// Toplam gelmemişse prompt + completion olarak hesaplanır.
+func newUsage(prompt, completion, total int) map[string]int {
+	if total == 0 {
+		total = prompt + completion
+	}
+	return map[string]int{
+		kernel.UsagePromptTokens:     prompt,
+		kernel.UsageCompletionTokens: completion,
+		kernel.UsageTotalTokens:      total,
+	}
+}
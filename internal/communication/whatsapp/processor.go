@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/aydndglr/rick-agent-v3/internal/agent"
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -33,8 +34,7 @@ func (w *Listener) HandleMessage(evt *events.Message) {
 		return
 	}
 
-	sender := evt.Info.Sender.User
-	if w.AdminPhone != "" && !strings.Contains(sender, w.AdminPhone) {
+	if w.AdminPhone != "" && !w.isAdmin(evt.Info.MessageSource) {
 		return
 	}
 
@@ -90,11 +90,30 @@ func (w *Listener) HandleMessage(evt *events.Message) {
 		return
 	}
 
-	// 3. UI İşlemleri
+	// 3. Yönetici Komutları ("/model list" gibi) LLM'e gitmeden çalışır
+	// Sadece admin_phone ile birebir eşleşen gönderen yönetici sayılır; admin_phone boşsa kimse sayılmaz.
+	// Bilinen komut olmayan "/" mesajları ("/etc/hosts dosyasını oku") normal istek olarak ajana gider.
+	if rickAgent, ok := w.Agent.(*agent.Rick); ok && len(images) == 0 && rickAgent.IsCommand(msgText) {
+		w.MarkAsRead(evt)
+		if !w.isAdmin(evt.Info.MessageSource) {
+			logger.Warn("🛑 Yönetici olmayan %s '/' komutu denedi, reddedildi.", evt.Info.Sender.String())
+			w.SendReply(evt.Info.Chat, "🛑 '/' komutları sadece yönetici numarasından çalıştırılabilir.")
+			return
+		}
+		go func() {
+			ctx := kernel.WithActor(kernel.WithConversation(context.Background(), evt.Info.Chat.String()), kernel.ActorAdmin)
+			if out, handled := rickAgent.HandleCommand(ctx, msgText); handled {
+				w.SendReply(evt.Info.Chat, out)
+			}
+		}()
+		return
+	}
+
+	// 4. UI İşlemleri
 	w.MarkAsRead(evt)
 	w.SetPresence(evt.Info.Chat, types.ChatPresenceComposing)
 
-	// 4. Ajanı Çalıştır ve CANLI YAYINI Başlat
+	// 5. Ajanı Çalıştır ve CANLI YAYINI Başlat
 	go func() {
 		// ========================================================================
 		// 🚀 RICK CANLI YAYIN MOTORU (MULTI-THREAD GÜVENLİ)
@@ -153,6 +172,7 @@ func (w *Listener) HandleMessage(evt *events.Message) {
 		timeoutDuration := time.Duration(timeoutMin) * time.Minute
 		ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
 		defer cancel()
+		ctx = kernel.WithConversation(ctx, evt.Info.Chat.String())
 
 		// Beyin düşünmeye başlıyor... 🧠
 		response, err := w.Agent.Run(ctx, msgText, images)
@@ -166,4 +186,29 @@ func (w *Listener) HandleMessage(evt *events.Message) {
 			w.SendReply(evt.Info.Chat, response)
 		}
 	}()
-}
\ No newline at end of file
+}
+
+// isAdmin: Gönderenin numarası (veya WhatsApp'ın verdiği alternatif numara adresi) admin_phone ile birebir aynı mı?
+func (w *Listener) isAdmin(src types.MessageSource) bool {
+	phone := normalizePhone(w.AdminPhone)
+	if phone == "" {
+		return false
+	}
+	for _, jid := range []types.JID{src.Sender, src.SenderAlt} {
+		if jid.Server == types.DefaultUserServer && jid.User == phone {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePhone: "+90 555 111 22 33" -> "905551112233"
+func normalizePhone(phone string) string {
+	var sb strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -17,6 +17,15 @@ func (w *Listener) SendReply(jid types.JID, text string) {
 	})
 }
 
+// NotifyAdmin: Yöneticinin numarasına doğrudan mesaj gönderir (admin_phone boşsa veya bağlantı yoksa bir şey yapmaz).
+func (w *Listener) NotifyAdmin(text string) {
+	phone := normalizePhone(w.AdminPhone)
+	if phone == "" || w.Client == nil {
+		return
+	}
+	w.SendReply(types.NewJID(phone, types.DefaultUserServer), text)
+}
+
 // MarkAsRead: Mesajı "okundu" olarak işaretler.
 func (w *Listener) MarkAsRead(evt *events.Message) {
 	w.Client.MarkRead(context.Background(), []types.MessageID{evt.Info.ID}, time.Now(), evt.Info.Chat, evt.Info.Sender)
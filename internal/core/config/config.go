@@ -13,11 +13,23 @@ type Config struct {
 		TimeoutMinutes int `yaml:"timeout_minutes" mapstructure:"timeout_minutes"`
 		Debug        bool   `yaml:"debug"`
 		WorkDir      string `yaml:"work_dir"`
+		// Görsel Geri Bildirim: Araçların ürettiği görseller modele bu sınırlarla gösterilir
+		MaxImageDimension int `yaml:"max_image_dimension"` // Uzun kenar (piksel), varsayılan 1024
+		MaxHistoryImages  int `yaml:"max_history_images"`  // Geçmişte tutulacak en fazla görsel, varsayılan 4
 	} `yaml:"app"`
 
 	Security struct {
-		Level        string `yaml:"level"`         // god_mode, standard, restricted
-		AutoPatching bool   `yaml:"auto_patching"` // Kendi kodunu tamir etme
+		Level          string   `yaml:"level"`           // god_mode, standard, restricted
+		AutoPatching   bool     `yaml:"auto_patching"`   // Kendi kodunu tamir etme
+		AllowedRoots   []string `yaml:"allowed_roots"`   // Doluysa araçların yol argümanları bu klasörlerle sınırlı
+		ProtectedPaths []string `yaml:"protected_paths"` // Varsayılanlara ek, yazılamayan/silinemeyen yollar (god_mode hariç)
+		DenyTools      []string `yaml:"deny_tools"`      // Her durumda kapalı araçlar (Kalıp, Örn: "ssh_*")
+
+		// Audit: Her araç çağrısının hash zincirli, sadece sona eklenen JSONL kaydı ("rick audit verify|query")
+		Audit struct {
+			Disabled bool   `yaml:"disabled"`
+			Path     string `yaml:"path"` // Varsayılan logs/audit.jsonl ("rick mcp-serve" yanına -mcp ekiyle yazar)
+		} `yaml:"audit"`
 	} `yaml:"security"`
 
 	Brain struct {
@@ -27,6 +39,9 @@ type Config struct {
 			ModelName   string  `yaml:"model_name"`
 			Temperature float64 `yaml:"temperature"`
 			NumCtx      int     `yaml:"num_ctx"`
+
+			// Hafıza vektörlerini üreten model (Boşsa sohbet modeli). Değiştirince 'memory_admin reembed' ile göç et.
+			EmbeddingModel string `yaml:"embedding_model"`
 		} `yaml:"primary"`
 
 		Secondary struct {
@@ -36,6 +51,12 @@ type Config struct {
 			ModelName string `yaml:"model_name"`
 		} `yaml:"secondary"`
 
+		// Kaset: Chat/Embed trafiğini kaydedip ağsız ve deterministik oynatmak için
+		Cassette struct {
+			Mode string `yaml:"mode"` // "" (kapalı) | record | replay | passthrough
+			Path string `yaml:"path"`
+		} `yaml:"cassette"`
+
 		APIKeys struct {
 			OpenAI    string `yaml:"openai"`
 			Gemini    string `yaml:"gemini"`
@@ -43,6 +64,91 @@ type Config struct {
 		} `yaml:"api_keys"`
 	} `yaml:"brain"`
 
+	// Usage: Token muhasebesi, fiyatlandırma ve bütçe sınırları (0 = sınırsız)
+	Usage struct {
+		LedgerPath          string                `yaml:"ledger_path"`
+		SessionBudgetTokens int                   `yaml:"session_budget_tokens"`
+		DailyBudgetTokens   int                   `yaml:"daily_budget_tokens"`
+		SessionBudgetUSD    float64               `yaml:"session_budget_usd"`
+		DailyBudgetUSD      float64               `yaml:"daily_budget_usd"`
+		Prices              map[string]TokenPrice `yaml:"prices"` // Model adı -> fiyat ("default" anahtarı yedek)
+	} `yaml:"usage"`
+
+	// Memory: Uzun süreli hafızanın depolama ayarları
+	Memory struct {
+		Backend     string `yaml:"backend"`      // sqlite (varsayılan) | json
+		Path        string `yaml:"path"`         // SQLite veritabanı yolu, varsayılan rick_memory.db
+		LegacyJSON  string `yaml:"legacy_json"`  // Bir kereye mahsus taşınacak eski JSON hafızası, varsayılan rick_memory.json
+		RecallLimit int    `yaml:"recall_limit"` // Göreve başlarken getirilecek kayıt sayısı (0 = 3, -1 = kapalı)
+		FactsPath   string `yaml:"facts_path"`   // Kesin bilgiler (anahtar-değer) SQLite yolu, varsayılan rick_facts.db
+		FactsLimit  int    `yaml:"facts_limit"`  // Sistem mesajına eklenecek en fazla bilgi (0 = 50, -1 = kapalı)
+
+		// Index: HNSW yaklaşık en yakın komşu indeksi (store'un yanında '.hnsw' olarak saklanır)
+		Index struct {
+			Disabled bool `yaml:"disabled"`
+			MinDocs  int  `yaml:"min_docs"` // Bu sayının altında tam tarama yapılır, varsayılan 2000
+		} `yaml:"index"`
+
+		// Search: Hibrit aramanın varsayılanları (0 = yerleşik varsayılan). Araçlar sorgu bazında ezebilir.
+		Search struct {
+			Limit         int     `yaml:"limit"`          // Varsayılan 5
+			VectorWeight  float64 `yaml:"vector_weight"`  // Varsayılan 1
+			LexicalWeight float64 `yaml:"lexical_weight"` // Varsayılan 1
+			Threshold     float64 `yaml:"threshold"`      // En düşük kosinüs benzerliği, varsayılan 0.4, -1 = eşik yok
+			RRFK          float64 `yaml:"rrf_k"`          // Varsayılan 60
+
+			// Zaman aşınması: Bu kadar günlük kaydın ağırlığı yarıya iner (en fazla %50). Varsayılan 30, -1 = kapalı
+			DecayHalfLifeDays float64 `yaml:"decay_half_life_days"`
+		} `yaml:"search"`
+
+		// Consolidation: Periyodik toparlama (benzer kayıtları özetle birleştir, süresi dolanı arşivle). Hiçbir şey silinmez.
+		Consolidation struct {
+			Enabled        bool    `yaml:"enabled"`
+			IntervalHours  float64 `yaml:"interval_hours"`  // Varsayılan 24
+			Similarity     float64 `yaml:"similarity"`      // Kümeleme için en düşük kosinüs benzerliği, varsayılan 0.9
+			MaxCluster     int     `yaml:"max_cluster"`     // Tek özete girecek en fazla kayıt, varsayılan 8
+			TTLDays        int     `yaml:"ttl_days"`        // Bu kadar günden eski otomatik kayıtlar (sohbet dökümleri, özetler) arşivlenir (0 = kapalı)
+			KeepImportance float64 `yaml:"keep_importance"` // Bu önem puanı ve üstü TTL ile arşivlenmez, varsayılan 0.8
+		} `yaml:"consolidation"`
+	} `yaml:"memory"`
+
+	// Python araçları (tools/ klasörü)
+	Tools struct {
+		WatchIntervalSeconds int `yaml:"watch_interval_seconds"` // Klasör değişikliklerini yoklama aralığı (0 = 3 sn, -1 = kapalı)
+
+		// Python ve manifest araçlarının sınırlandırılmış çalıştırılması (0 = varsayılan, -1 = o sınır kapalı)
+		Sandbox struct {
+			Disabled       bool     `yaml:"disabled"`
+			TimeoutSeconds int      `yaml:"timeout_seconds"` // Duvar saati, varsayılan 60
+			CPUSeconds     int      `yaml:"cpu_seconds"`     // Varsayılan 30 (Sadece Linux)
+			MemoryMB       int      `yaml:"memory_mb"`       // Adres alanı, varsayılan 1024 (Sadece Linux)
+			MaxProcesses   int      `yaml:"max_processes"`   // Varsayılan kapalı; kullanıcının TÜM süreçlerini sayar (Sadece Linux)
+			MaxOpenFiles   int      `yaml:"max_open_files"`  // Varsayılan 256 (Sadece Linux)
+			MaxOutputKB    int      `yaml:"max_output_kb"`   // stdout+stderr, varsayılan 256
+			IsolateNetwork bool     `yaml:"isolate_network"` // Yetkisiz user+net namespace ile ağı kapat (Sadece Linux)
+			EnvAllow       []string `yaml:"env_allow"`       // Scripte aktarılacak ek ortam değişkenleri (Gerisi silinir)
+		} `yaml:"sandbox"`
+
+		// Araç çalıştırma zinciri: Panik yakalama, zaman aşımı, ölçüm, çıktı kısaltma, politika ve onay (Ajan ve mcp-serve)
+		Execution struct {
+			TimeoutSeconds  int            `yaml:"timeout_seconds"`  // Varsayılan araç zaman aşımı, varsayılan 120 (-1 = sınırsız)
+			Timeouts        map[string]int `yaml:"timeouts"`         // Araç başına saniye (Örn: browser: 300)
+			MaxOutputKB     int            `yaml:"max_output_kb"`    // Modele giden araç metni sınırı, varsayılan 64 (-1 = kapalı)
+			RequireApproval []string       `yaml:"require_approval"` // Yönetici onayı isteyen araç kalıpları ("/approve <no>" ile onaylanır; mcp-serve'de reddedilir)
+		} `yaml:"execution"`
+	} `yaml:"tools"`
+
+	// MCP: Dış MCP sunucularının araçlarını içe aktarır (Model Context Protocol)
+	MCP struct {
+		Servers []MCPServer `yaml:"servers"`
+
+		// "rick mcp-serve": Rick'in yerel araçlarını stdio üzerinden MCP sunucusu olarak açar (security ayarları uygulanır)
+		Serve struct {
+			Tools          []string `yaml:"tools"`           // Açılacak araçlar (Kalıp); boşsa fs_*, browser, ssh_tool, start_task, check_task
+			TimeoutSeconds int      `yaml:"timeout_seconds"` // Araç zaman aşımı (0 = tools.execution ayarı, -1 = sınırsız)
+		} `yaml:"serve"`
+	} `yaml:"mcp"`
+
 	Communication struct {
 		Whatsapp struct {
 			Enabled      bool   `yaml:"enabled"`
@@ -52,6 +158,27 @@ type Config struct {
 	} `yaml:"communication"`
 }
 
+// TokenPrice: 1 milyon token başına USD fiyatı
+type TokenPrice struct {
+	Prompt     float64 `yaml:"prompt"`
+	Completion float64 `yaml:"completion"`
+}
+
+// MCPServer: Araçları alınacak bir MCP sunucusu ('command' = stdio, 'url' = streamable HTTP)
+type MCPServer struct {
+	Name           string            `yaml:"name"`
+	Disabled       bool              `yaml:"disabled"`
+	Command        string            `yaml:"command"`
+	Args           []string          `yaml:"args"`
+	Env            map[string]string `yaml:"env"` // Değerlerde $VAR açılır
+	Dir            string            `yaml:"dir"`
+	URL            string            `yaml:"url"`
+	Headers        map[string]string `yaml:"headers"`         // Değerlerde $VAR açılır
+	Prefix         string            `yaml:"prefix"`          // Araç adı öneki, varsayılan sunucu adı (<önek>__<araç>)
+	Tools          []string          `yaml:"tools"`           // Sadece bu araçları al (Boşsa hepsi)
+	TimeoutSeconds int               `yaml:"timeout_seconds"` // Araç çağrısı zaman aşımı, varsayılan 60
+}
+
 // Load: Config dosyasını okur
 func Load(path string) (*Config, error) {
 	config := &Config{}
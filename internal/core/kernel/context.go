@@ -0,0 +1,59 @@
+package kernel
+
+import "context"
+
+type ctxKey string
+
+const conversationKey ctxKey = "conversation_id"
+
+// DefaultConversation: Portal belirtmediğinde (örn: terminal) kullanılan sohbet kimliği
+const DefaultConversation = "cli"
+
+// WithConversation: Görevi başlatan sohbetin (WhatsApp JID, terminal vb.) kimliğini context'e iliştirir.
+func WithConversation(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, conversationKey, id)
+}
+
+// ConversationFrom: Context'teki sohbet kimliğini döner. Yoksa DefaultConversation.
+func ConversationFrom(ctx context.Context) string {
+	if id, ok := ctx.Value(conversationKey).(string); ok && id != "" {
+		return id
+	}
+	return DefaultConversation
+}
+
+const actorKey ctxKey = "actor"
+
+// Eylemi başlatan taraf (Kayıtların kaynağını/provenance tutmak için)
+const (
+	ActorModel = "model" // Model kendi kararıyla araç çağırdı (Varsayılan)
+	ActorAdmin = "admin" // Yönetici "/" komutuyla doğrudan çağırdı
+	ActorCLI   = "cli"   // Yardımcı komut satırı ("rick facts ..." vb.)
+	ActorMCP   = "mcp"   // "rick mcp-serve" üzerinden bağlanan bir MCP istemcisi
+)
+
+// WithActor: Aracı kimin çağırdığını context'e iliştirir.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom: Context'teki çağıranı döner. Yoksa ActorModel.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorModel
+}
+
+const sessionKey ctxKey = "session_id"
+
+// WithSession: Çağrının ait olduğu görev oturumunu (Örn: TSK-1A2B) context'e iliştirir.
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionKey, id)
+}
+
+// SessionFrom: Context'teki oturum kimliğini döner. Yoksa boş.
+func SessionFrom(ctx context.Context) string {
+	id, _ := ctx.Value(sessionKey).(string)
+	return id
+}
@@ -0,0 +1,26 @@
+package kernel
+
+import (
+	"context"
+	"time"
+)
+
+// Fact: Bulanık hafızanın aksine birebir hatırlanması gereken anahtar-değer bilgisi
+// (Örn: "user.language" = "Türkçe", "server.prod" = "db01.example.com").
+type Fact struct {
+	Key          string    `json:"key"`
+	Value        string    `json:"value"`
+	Scope        string    `json:"scope"`        // Hafıza ad alanlarıyla aynı biçim: shared, conversation:<id>, user:<id>, project:<ad>
+	SetBy        string    `json:"set_by"`       // Kaynak: admin, model, cli
+	Conversation string    `json:"conversation"` // Bilginin ayarlandığı sohbet
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// FactStore: Yapılandırılmış kullanıcı profili / bilgi deposu.
+type FactStore interface {
+	SetFact(ctx context.Context, fact Fact) (Fact, error)
+	GetFact(ctx context.Context, scope, key string) (*Fact, error)
+	ListFacts(ctx context.Context, scopes []string, prefix string) ([]Fact, error) // Boş scopes = hepsi
+	DeleteFact(ctx context.Context, scope, key string) (bool, error)
+}
@@ -19,11 +19,19 @@ type ToolCall struct {
 	Arguments map[string]interface{} `json:"arguments"`
 }
 
+// Token kullanım anahtarları: Tüm sağlayıcılar BrainResponse.Usage'ı bu isimlerle doldurur.
+const (
+	UsagePromptTokens     = "prompt_tokens"
+	UsageCompletionTokens = "completion_tokens"
+	UsageTotalTokens      = "total_tokens"
+)
+
 // BrainResponse: Beyinden gelen ham cevap
 type BrainResponse struct {
 	Content   string
 	ToolCalls []ToolCall
-	Usage     map[string]int // Token kullanımı
+	Usage     map[string]int // Token kullanımı (Usage* anahtarları)
+	Model     string         // Cevabı üreten model (Fiyatlandırma için)
 }
 
 // Brain: Zeka sağlayıcısı (Ollama, OpenAI, Gemini vb.)
@@ -34,6 +42,20 @@ type Brain interface {
 	Embed(ctx context.Context, text string) ([]float32, error)
 }
 
+// ToolImageSupporter: Araç sonucu (role: tool) mesajına görsel iliştirilmesini kabul eden beyinler.
+// Uygulamayan beyinler için görseller ayrı bir kullanıcı mesajıyla gönderilir.
+type ToolImageSupporter interface {
+	SupportsToolImages() bool
+}
+
+// EmbeddingModeler: Embedding'i hangi modelin ürettiğini bildiren ve modeli değiştirebilen beyinler.
+// Hafıza, farklı modellerin vektörlerini birbiriyle kıyaslamamak için bunu kullanır.
+type EmbeddingModeler interface {
+	EmbeddingModel() string // Boşsa model bilinmiyor demektir
+	SetEmbeddingModel(model string)
+	EmbedWithModel(ctx context.Context, model, text string) ([]float32, error)
+}
+
 // Message: Sohbet geçmişi birimi
 type Message struct {
 	Role       string     `json:"role"`
@@ -45,10 +67,17 @@ type Message struct {
 }
 
 
-// Memory: Uzun süreli hafıza
+// Memory: Uzun süreli hafıza. Kayıtlar ad alanlarına (namespace) ayrılır ve kalıcı ID taşır.
 type Memory interface {
+	// Add: Ad alanı metadata["namespace"] ile verilmezse ortak (shared) hafızaya ekler.
 	Add(ctx context.Context, content string, metadata map[string]interface{}) error
+	// Search: Tüm hafızada arar ve düz metin döner (Eski kullanım; yeni kod Query kullanmalı).
 	Search(ctx context.Context, query string, limit int) ([]string, error)
+
+	Save(ctx context.Context, namespace, content string, metadata map[string]interface{}) (string, error)
+	Get(ctx context.Context, id string) (*MemoryRecord, error)
+	Query(ctx context.Context, q MemoryQuery) ([]MemoryRecord, error)
+	Delete(ctx context.Context, ids ...string) (int, error)
 }
 
 // Agent: Rick'in kendisi
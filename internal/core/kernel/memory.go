@@ -0,0 +1,86 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Hafıza Ad Alanları (Namespace): Sohbetlerin, kullanıcıların ve projelerin anıları birbirine karışmasın diye.
+const (
+	NamespaceShared = "shared" // Herkesin (tüm sohbetlerin) görebildiği ortak hafıza
+)
+
+// ConversationNamespace: Sadece o sohbete ait hafıza (Örn: "conversation:cli", "conversation:9053...@s.whatsapp.net")
+func ConversationNamespace(conversationID string) string {
+	return "conversation:" + conversationID
+}
+
+// UserNamespace: Bir kullanıcıya ait, sohbetler arası hafıza
+func UserNamespace(userID string) string {
+	return "user:" + userID
+}
+
+// ProjectNamespace: Bir projeye ait hafıza
+func ProjectNamespace(project string) string {
+	return "project:" + project
+}
+
+// ValidNamespace: "shared" veya "tür:ad" biçimini (conversation, user, project) doğrular.
+func ValidNamespace(ns string) error {
+	if ns == NamespaceShared {
+		return nil
+	}
+	kind, name, ok := strings.Cut(ns, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("geçersiz ad alanı: '%s' (Örn: 'shared', 'conversation:<id>', 'user:<id>', 'project:<ad>')", ns)
+	}
+	switch kind {
+	case "conversation", "user", "project":
+		return nil
+	}
+	return fmt.Errorf("bilinmeyen ad alanı türü: '%s' (conversation, user, project)", kind)
+}
+
+// MemoryRecord: Hafızadaki tek kayıt; ID kalıcıdır ve modele gösterilir (unutma/güncelleme için).
+type MemoryRecord struct {
+	ID        string                 `json:"id"`
+	Namespace string                 `json:"namespace"`
+	Content   string                 `json:"content"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	Score     float64                `json:"score,omitempty"` // Sadece arama sonuçlarında
+}
+
+// Tags: Metadata'daki "tags" alanını (JSON'dan []interface{} olarak da gelebilir) düz listeye çevirir.
+func (r MemoryRecord) Tags() []string {
+	return MetadataStrings(r.Metadata, "tags")
+}
+
+// MetadataStrings: Metadata'daki metin veya metin listesi alanını düz listeye çevirir.
+func MetadataStrings(metadata map[string]interface{}, key string) []string {
+	switch v := metadata[key].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			out = append(out, fmt.Sprint(item))
+		}
+		return out
+	}
+	return nil
+}
+
+// MemoryQuery: Kayıt bazlı hafıza sorgusu. Query boşsa filtreye uyan en yeni kayıtlar döner.
+type MemoryQuery struct {
+	Query      string
+	Namespaces []string               // Boşsa tüm ad alanları
+	Tags       []string               // Kayıt bu etiketlerin hepsine sahip olmalı
+	Metadata   map[string]interface{} // Anahtar = değer eşleşmesi (Liste alanlarda 'içerir' anlamına gelir)
+	Since      time.Time              // Sıfırsa alt sınır yok
+	Until      time.Time              // Sıfırsa üst sınır yok
+	Limit      int
+}
@@ -0,0 +1,85 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// Araç sonucu durumları
+const (
+	ToolStatusSuccess = "success" // İş tamamlandı
+	ToolStatusFailed  = "failed"  // Araç çalıştı ama iş başarısız oldu (exit code, test çökmesi vb.) -> model düzeltmeli
+	ToolStatusError   = "error"   // Araç işi hiç yapamadı (eksik parametre, kural ihlali vb.)
+)
+
+// Artifact: Aracın metin dışında ürettiği çıktı (ekran görüntüsü, dosya vb.)
+type Artifact struct {
+	Kind     string `json:"kind"`                // image | file
+	Path     string `json:"path,omitempty"`      // Diskteki yolu (varsa)
+	MimeType string `json:"mime_type,omitempty"` // Örn: image/png
+	Data     []byte `json:"-"`                   // Bellekteki ham içerik (Path yoksa)
+}
+
+// ToolResult: Aracın durumunu, model için metnini, yapısal verisini ve ek çıktılarını birlikte taşıyan sonuç
+type ToolResult struct {
+	Status    string                 `json:"status"`              // Boşsa success sayılır
+	Text      string                 `json:"text"`                // Modelin okuyacağı metin
+	Data      map[string]interface{} `json:"data,omitempty"`      // Yapısal veri (exit_code, path, task_id...)
+	Artifacts []Artifact             `json:"artifacts,omitempty"` // Dosyalar ve görseller
+	Metrics   map[string]float64     `json:"metrics,omitempty"`   // Süre, boyut vb. ölçümler
+}
+
+// OK: Sonuç başarılı mı? (Boş durum başarılı kabul edilir)
+func (r *ToolResult) OK() bool {
+	return r.Status == "" || r.Status == ToolStatusSuccess
+}
+
+// RichTool: Yapısal sonuç (ToolResult) döndürebilen araçlar bu arayüzü de uygular.
+// Sadece Execute'u olan (düz metin) araçlar eskisi gibi çalışmaya devam eder.
+type RichTool interface {
+	Tool
+	ExecuteRich(ctx context.Context, args map[string]interface{}) (*ToolResult, error)
+}
+
+// RunTool: Aracı türüne göre (zengin veya düz metin) çalıştırır ve her zaman ToolResult döner.
+func RunTool(ctx context.Context, t Tool, args map[string]interface{}) (*ToolResult, error) {
+	if rich, ok := t.(RichTool); ok {
+		return rich.ExecuteRich(ctx, args)
+	}
+	out, err := t.Execute(ctx, args)
+	if err != nil {
+		return &ToolResult{Status: ToolStatusError, Text: out}, err
+	}
+	return &ToolResult{Status: ToolStatusSuccess, Text: out}, nil
+}
+
+// ResultText: RichTool'ların Execute metodunu ExecuteRich üzerinden tek satırda sağlamak için.
+func ResultText(res *ToolResult, err error) (string, error) {
+	if res == nil {
+		return "", err
+	}
+	return res.Text, err
+}
+
+// Success: Başarılı sonuç kısayolu
+func Success(text string, data map[string]interface{}) *ToolResult {
+	return &ToolResult{Status: ToolStatusSuccess, Text: text, Data: data}
+}
+
+// Failed: Aracın çalıştığı ama işin başarısız olduğu (modelin düzeltmesi gereken) sonuç kısayolu
+func Failed(text string, data map[string]interface{}) *ToolResult {
+	return &ToolResult{Status: ToolStatusFailed, Text: text, Data: data}
+}
+
+// ExitCode: Süreç hatasından çıkış kodunu çıkarır (Bilinmiyorsa -1).
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,338 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Güvenlik seviyeleri (config: security.level)
+const (
+	LevelGodMode    = "god_mode"   // Tam yetki
+	LevelStandard   = "standard"   // Araç kullanır; kritik yollara yazamaz, Rick'in kendi araçlarını değiştiremez
+	LevelRestricted = "restricted" // Sadece okuma
+)
+
+// Araç sınıfları: Seviyenin hangi araçlara izin verdiğini belirler.
+const (
+	ClassRead    = "read"    // Sadece okur (Dosya listeleme/okuma, görev durumu)
+	ClassWrite   = "write"   // Dosya yazar veya siler
+	ClassExec    = "exec"    // Yerelde veya uzakta komut/süreç çalıştırır
+	ClassNetwork = "network" // İnternete çıkar (Tarayıcı)
+	ClassCode    = "code"    // Rick'in kendi araçlarını yazar, değiştirir veya siler
+	ClassMemory  = "memory"  // Rick'in kendi hafızası ve oturumları (Dış dünyaya dokunmaz)
+)
+
+// toolClasses: Bilinen araçların sınıfları. Bilinmeyenler (Python, MCP araçları) exec sayılır; manifest araçları Declare ile bildirir.
+var toolClasses = map[string]string{
+	"fs_list":            ClassRead,
+	"fs_read":            ClassRead,
+	"fs_search":          ClassRead,
+	"check_task":         ClassRead,
+	"sys_info":           ClassRead,
+	"fs_write":           ClassWrite,
+	"fs_delete":          ClassWrite,
+	"start_task":         ClassExec,
+	"kill_task":          ClassExec,
+	"schedule_task":      ClassExec,
+	"sys_exec":           ClassExec,
+	"ssh_tool":           ClassExec,
+	"browser":            ClassNetwork,
+	"dev_studio":         ClassCode,
+	"edit_python_tool":   ClassCode,
+	"delete_python_tool": ClassCode,
+	"tool_history":       ClassCode,
+	"test_tool":          ClassCode,
+	"memory_save":        ClassMemory,
+	"memory_search":      ClassMemory,
+	"memory_forget":      ClassMemory,
+	"memory_admin":       ClassMemory,
+	"facts":              ClassMemory,
+	"ingest":             ClassMemory,
+	"rick_control":       ClassMemory,
+	"ollama_models":      ClassExec,
+}
+
+// classRank: Birden çok sınıf bildiren araçların gösterilecek (en yetkili) sınıfı için sıra
+var classRank = []string{ClassCode, ClassExec, ClassWrite, ClassNetwork, ClassMemory, ClassRead}
+
+// declared: Araçların kendi bildirdiği sınıflar (Manifest araçlarının 'capabilities' alanı)
+var (
+	declaredMu sync.RWMutex
+	declared   = map[string][]string{}
+)
+
+// Declare: Aracın ihtiyaç duyduğu sınıfları kaydeder; araç ancak seviye hepsine izin veriyorsa açılır.
+// Boş liste kaydı siler (Araç bilinmeyen sayılır: exec).
+func Declare(tool string, classes []string) error {
+	for _, c := range classes {
+		if !knownClass(c) {
+			return fmt.Errorf("bilinmeyen yetenek: '%s' (%s)", c, strings.Join(classRank, ", "))
+		}
+	}
+	declaredMu.Lock()
+	defer declaredMu.Unlock()
+	if len(classes) == 0 {
+		delete(declared, tool)
+		return nil
+	}
+	declared[tool] = append([]string(nil), classes...)
+	return nil
+}
+
+func knownClass(c string) bool {
+	for _, known := range classRank {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// levelClasses: Seviyelerin izin verdiği sınıflar
+var levelClasses = map[string][]string{
+	LevelGodMode:    {ClassRead, ClassWrite, ClassExec, ClassNetwork, ClassCode, ClassMemory},
+	LevelStandard:   {ClassRead, ClassWrite, ClassExec, ClassNetwork, ClassMemory},
+	LevelRestricted: {ClassRead, ClassMemory},
+}
+
+// pathArgs: Yerel dosya yolu taşıyan argüman adları (ssh_tool'un 'remote' yolu uzaktadır, denetlenmez)
+var pathArgs = []string{"path", "work_dir", "local", "key_path"}
+
+// defaultProtected: god_mode dışında yazılamayan/silinemeyen yollar (Rick'in kendisi).
+// tools: İzleyici buraya düşen araçları canlı yükler (Kod sınıfını atlatmanın yolu olmasın).
+// logs ve hafıza/bilgi veritabanları: Model kendi denetim zincirini ve hafızasını dosya araçlarıyla ezemesin.
+var defaultProtected = []string{".git", "go.mod", "go.sum", "internal", "cmd", "config", "tools", "logs",
+	"rick_memory.db", "rick_memory.db-wal", "rick_memory.db-shm", "rick_memory.db.hnsw",
+	"rick_memory.json", "rick_memory.json.hnsw",
+	"rick_facts.db", "rick_facts.db-wal", "rick_facts.db-shm"}
+
+// systemDirs: İşletim sisteminin kritik klasörleri (Korunan yollara eklenir)
+var systemDirs = map[string][]string{
+	"windows": {`C:\Windows`, `C:\Program Files`, `C:\Program Files (x86)`},
+	"unix":    {"/etc", "/bin", "/sbin", "/usr", "/lib", "/lib64", "/boot", "/sys", "/proc", "/dev"},
+}
+
+// defaultSecrets: god_mode dışında okunamayan yollar da dahil hiç dokunulamayan yollar
+var defaultSecrets = []string{"config/config.yaml", ".env", "~/.ssh", "~/.aws", "~/.gnupg"}
+
+// Options: Politikanın yapılandırması (config: security)
+type Options struct {
+	Level     string   // Boşsa standard
+	Tools     []string // Açılacak araç kalıpları (path.Match, Örn: "fs_*"); boşsa seviyenin izin verdiği hepsi
+	Deny      []string // Her durumda kapalı araç kalıpları
+	Roots     []string // Doluysa tüm yol argümanları bu klasörlerin içinde olmalı
+	Protected []string // Varsayılanlara eklenen, yazılamayan/silinemeyen yollar
+}
+
+// Policy: Hangi aracın, hangi argümanlarla çağrılabileceğine karar verir.
+type Policy struct {
+	level     string
+	classes   map[string]bool
+	tools     []string
+	deny      []string
+	roots     []string
+	protected []string
+	secrets   []string
+}
+
+// Violation: Politikanın reddettiği çağrı
+type Violation struct {
+	Tool   string
+	Reason string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("🛑 GÜVENLİK POLİTİKASI: '%s' reddedildi: %s", v.Tool, v.Reason)
+}
+
+// New: Seçenekleri doğrular; yolları mutlak hale getirir.
+func New(opts Options) (*Policy, error) {
+	level := strings.TrimSpace(opts.Level)
+	if level == "" {
+		level = LevelStandard
+	}
+	classes, ok := levelClasses[level]
+	if !ok {
+		return nil, fmt.Errorf("bilinmeyen güvenlik seviyesi: '%s' (god_mode, standard, restricted)", level)
+	}
+	p := &Policy{level: level, classes: make(map[string]bool), tools: opts.Tools, deny: opts.Deny}
+	for _, c := range classes {
+		p.classes[c] = true
+	}
+	for _, pattern := range append(append([]string{}, opts.Tools...), opts.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("geçersiz araç kalıbı: '%s'", pattern)
+		}
+	}
+	for _, r := range opts.Roots {
+		p.roots = append(p.roots, absPath(r))
+	}
+	osKey := "unix"
+	if runtime.GOOS == "windows" {
+		osKey = "windows"
+	}
+	protected := append(append(append([]string{}, defaultProtected...), systemDirs[osKey]...), opts.Protected...)
+	for _, r := range protected {
+		p.protected = append(p.protected, absPath(r))
+	}
+	for _, r := range defaultSecrets {
+		p.secrets = append(p.secrets, absPath(r))
+	}
+	return p, nil
+}
+
+// Level: Etkin güvenlik seviyesi
+func (p *Policy) Level() string { return p.level }
+
+// Class: Aracın sınıfı (Birden çok sınıf bildirdiyse en yetkilisi)
+func Class(tool string) string {
+	classes := Classes(tool)
+	for _, c := range classRank {
+		for _, have := range classes {
+			if c == have {
+				return c
+			}
+		}
+	}
+	return ClassExec
+}
+
+// Classes: Aracın ihtiyaç duyduğu tüm sınıflar
+func Classes(tool string) []string {
+	if c, ok := toolClasses[tool]; ok {
+		return []string{c}
+	}
+	declaredMu.RLock()
+	defer declaredMu.RUnlock()
+	if classes, ok := declared[tool]; ok {
+		return classes
+	}
+	return []string{ClassExec}
+}
+
+func hasClass(classes []string, class string) bool {
+	for _, c := range classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Exposed: Araç bu politika altında görünür/çağrılabilir mi?
+func (p *Policy) Exposed(tool string) bool {
+	if matchAny(p.deny, tool) {
+		return false
+	}
+	if len(p.tools) > 0 && !matchAny(p.tools, tool) {
+		return false
+	}
+	for _, c := range Classes(tool) {
+		if !p.classes[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// Check: Çağrıyı argümanlarıyla denetler; izin yoksa *Violation döner.
+func (p *Policy) Check(tool string, args map[string]interface{}) error {
+	classes := Classes(tool)
+	if !p.Exposed(tool) {
+		if p.level == LevelStandard && hasClass(classes, ClassCode) {
+			return &Violation{Tool: tool, Reason: "'standard' seviyesinde araç yazmak/değiştirmek yönetici onayı ister (Yönetici '/' komutuyla çalıştırabilir)"}
+		}
+		return &Violation{Tool: tool, Reason: fmt.Sprintf("'%s' seviyesinde bu araca izin yok", p.level)}
+	}
+	return p.CheckPaths(tool, args)
+}
+
+// CheckPaths: Sadece yol kurallarını (İzinli klasörler, gizli ve korunan yollar) denetler; seviyenin araç
+// kısıtını uygulamaz. Yönetici komutları bununla denetlenir.
+func (p *Policy) CheckPaths(tool string, args map[string]interface{}) error {
+	classes := Classes(tool)
+	for _, key := range pathArgs {
+		raw, ok := args[key].(string)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		target := absPath(raw)
+		if len(p.roots) > 0 && !underAny(p.roots, target) {
+			return &Violation{Tool: tool, Reason: fmt.Sprintf("'%s' izin verilen klasörlerin dışında", raw)}
+		}
+		if p.level == LevelGodMode {
+			continue
+		}
+		if underAny(p.secrets, target) {
+			return &Violation{Tool: tool, Reason: fmt.Sprintf("'%s' gizli bilgi içeren bir yol", raw)}
+		}
+		if hasClass(classes, ClassWrite) && (underAny(p.protected, target) || containsAny(p.protected, target)) {
+			return &Violation{Tool: tool, Reason: fmt.Sprintf("'%s' yolu sistem için kritik", raw)}
+		}
+	}
+	return nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// absPath: "~" açılır, göreli yollar çalışma klasörüne göre mutlak yapılır; var olan kısmın sembolik bağları çözülür.
+func absPath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return filepath.Clean(p)
+	}
+	// Var olan en uzun öneki çöz (Hedef henüz yoksa da bağ üzerinden kaçılamasın)
+	rest := ""
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(real, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+	}
+}
+
+// underAny: target köklerden birinin kendisi veya altında mı?
+func underAny(roots []string, target string) bool {
+	for _, root := range roots {
+		if target == root {
+			return true
+		}
+		rel, err := filepath.Rel(root, target)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny: target korunan yollardan birini içeren bir üst klasör mü? (Örn: proje kökünü silmek)
+func containsAny(protected []string, target string) bool {
+	for _, p := range protected {
+		if target != p && underAny([]string{target}, p) {
+			return true
+		}
+	}
+	return false
+}
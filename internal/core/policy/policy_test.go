@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		tool  string
+		args  map[string]interface{}
+		allow bool
+	}{
+		{"standard okuma", Options{}, "fs_read", map[string]interface{}{"path": "notes/todo.md"}, true},
+		{"standard yazma", Options{}, "fs_write", map[string]interface{}{"path": "notes/todo.md"}, true},
+		{"standard gizli okuma", Options{}, "fs_read", map[string]interface{}{"path": "config/config.yaml"}, false},
+		{"standard korunan yazma", Options{}, "fs_write", map[string]interface{}{"path": "internal/x.go"}, false},
+		{"standard korunan okuma", Options{}, "fs_read", map[string]interface{}{"path": "internal/x.go"}, true},
+		{"standard araç manifesti yazma", Options{}, "fs_write", map[string]interface{}{"path": "tools/x.tool.yaml"}, false},
+		{"standard denetim kaydı silme", Options{}, "fs_delete", map[string]interface{}{"path": "logs/audit.jsonl"}, false},
+		{"standard hafıza veritabanı yazma", Options{}, "fs_write", map[string]interface{}{"path": "rick_memory.db-wal"}, false},
+		{"god_mode araç manifesti yazma", Options{Level: LevelGodMode}, "fs_write", map[string]interface{}{"path": "tools/x.tool.yaml"}, true},
+		{"standard korunanı içeren klasörü silme", Options{}, "fs_delete", map[string]interface{}{"path": "."}, false},
+		{"standard kod aracı", Options{}, "dev_studio", nil, false},
+		{"restricted yazma", Options{Level: LevelRestricted}, "fs_write", map[string]interface{}{"path": "notes/todo.md"}, false},
+		{"restricted okuma", Options{Level: LevelRestricted}, "fs_read", map[string]interface{}{"path": "notes/todo.md"}, true},
+		{"god_mode gizli okuma", Options{Level: LevelGodMode}, "fs_read", map[string]interface{}{"path": "config/config.yaml"}, true},
+		{"god_mode kök dışı", Options{Level: LevelGodMode, Roots: []string{"workspace"}}, "fs_read", map[string]interface{}{"path": "notes/todo.md"}, false},
+		{"kök içi", Options{Roots: []string{"workspace"}}, "fs_read", map[string]interface{}{"path": "workspace/a.txt"}, true},
+		{"yasaklı araç", Options{Deny: []string{"fs_*"}}, "fs_read", map[string]interface{}{"path": "notes/todo.md"}, false},
+		{"araç listesi dışı", Options{Tools: []string{"memory_*"}}, "fs_read", nil, false},
+		{"bilinmeyen araç exec sayılır", Options{Level: LevelRestricted}, "weather_py", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.opts)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			err = p.Check(tt.tool, tt.args)
+			if tt.allow && err != nil {
+				t.Fatalf("izin bekleniyordu: %v", err)
+			}
+			if !tt.allow {
+				var v *Violation
+				if !errors.As(err, &v) {
+					t.Fatalf("*Violation bekleniyordu, alınan: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPathsIgnoresLevel(t *testing.T) {
+	p, err := New(Options{Level: LevelRestricted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckPaths("fs_write", map[string]interface{}{"path": "notes/todo.md"}); err != nil {
+		t.Fatalf("seviye kısıtı uygulanmamalıydı: %v", err)
+	}
+	if err := p.CheckPaths("fs_read", map[string]interface{}{"path": "config/config.yaml"}); err == nil {
+		t.Fatal("gizli yol reddedilmeliydi")
+	}
+	if err := p.CheckPaths("fs_write", map[string]interface{}{"path": "go.mod"}); err == nil {
+		t.Fatal("korunan yola yazma reddedilmeliydi")
+	}
+}
+
+func TestNewRejectsBadOptions(t *testing.T) {
+	if _, err := New(Options{Level: "root"}); err == nil {
+		t.Fatal("bilinmeyen seviye reddedilmeliydi")
+	}
+	if _, err := New(Options{Deny: []string{"["}}); err == nil {
+		t.Fatal("geçersiz kalıp reddedilmeliydi")
+	}
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Archive: Kayıtları canlı hafızadan arşive taşır (Aramada görünmezler ama Restore ile geri alınabilirler).
+// Arşivlenen kayıt sayısını döner.
+func (vs *VectorStore) Archive(ctx context.Context, reason string, ids ...string) (int, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	var docs []Document
+	var found []string
+	for _, id := range ids {
+		if idx, ok := vs.byID[id]; ok {
+			docs = append(docs, vs.docs[idx])
+			found = append(found, id)
+		}
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	if err := vs.backend.Archive(reason, docs...); err != nil {
+		return 0, fmt.Errorf("hafıza kaydı arşivlenemedi: %v", err)
+	}
+	vs.removeLocked(found)
+	return len(found), nil
+}
+
+// Restore: Arşivdeki kayıtları canlı hafızaya geri alır. Geri alınan kayıt sayısını döner.
+func (vs *VectorStore) Restore(ctx context.Context, ids ...string) (int, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	restored, err := vs.backend.Unarchive(ids...)
+	if err != nil {
+		return 0, fmt.Errorf("arşivden geri alınamadı: %v", err)
+	}
+	for _, doc := range restored {
+		doc.EmbeddingDim = len(doc.Embedding)
+		if _, exists := vs.byID[doc.ID]; exists {
+			continue
+		}
+		vs.byID[doc.ID] = len(vs.docs)
+		vs.docs = append(vs.docs, doc)
+		vs.lexical.Add(doc.ID, doc.Content)
+		vs.countIndexableLocked(doc, 1)
+		if vs.ann != nil && vs.ann.Model == doc.EmbeddingModel {
+			vs.ann.Add(doc.ID, doc.Embedding)
+		}
+	}
+	return len(restored), nil
+}
+
+// Archived: Arşivdeki kayıtları en yeni arşivlenen en üstte olacak şekilde listeler (namespace boşsa hepsi).
+func (vs *VectorStore) Archived(ctx context.Context, namespace string) ([]ArchivedDocument, error) {
+	all, err := vs.backend.Archived()
+	if err != nil {
+		return nil, err
+	}
+	var out []ArchivedDocument
+	for _, a := range all {
+		if namespace == "" || a.Namespace == namespace {
+			out = append(out, a)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ArchivedAt.After(out[j].ArchivedAt) })
+	return out, nil
+}
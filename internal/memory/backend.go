@@ -0,0 +1,253 @@
+package memory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// Backend: VectorStore'un kalıcı depolama katmanı (JSON dosyası veya SQLite).
+// Arama RAM'deki kopya üzerinden yapılır, backend sadece artımlı yazma/silme yapar.
+type Backend interface {
+	Load() ([]Document, error)
+	Put(docs ...Document) error // Ekle veya güncelle (ID'ye göre)
+	Delete(ids ...string) error
+	Archive(reason string, docs ...Document) error // Dokümanları arşive taşır (Canlı hafızadan çıkar, silinmez)
+	Archived() ([]ArchivedDocument, error)
+	Unarchive(ids ...string) ([]Document, error) // Arşivden çıkarır ve canlı hafızaya geri yazar
+	Meta(key string) string                      // Store geneli ayarlar (Örn: aktif embedding modeli)
+	SetMeta(key, value string) error
+	Close() error
+}
+
+// ArchivedDocument: Birleştirme veya süre aşımıyla canlı hafızadan çıkarılmış, geri alınabilir kayıt
+type ArchivedDocument struct {
+	Document
+	ArchivedAt time.Time `json:"archived_at"`
+	Reason     string    `json:"reason"` // Örn: "merged:<özet ID>", "ttl", "manual"
+}
+
+// jsonBackend: Eski tek dosyalı hafıza formatı (rick_memory.json).
+// Her yazmada dosya atomik olarak baştan yazılır; küçük hafızalar ve geriye uyumluluk için.
+type jsonBackend struct {
+	path string
+	docs []Document
+	mu   sync.Mutex
+}
+
+func newJSONBackend(path string) *jsonBackend {
+	return &jsonBackend{path: path}
+}
+
+func (b *jsonBackend) Load() ([]Document, error) {
+	docs, err := readJSONDocuments(b.path)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.docs = append([]Document(nil), docs...)
+	b.mu.Unlock()
+	return docs, nil
+}
+
+func (b *jsonBackend) Put(docs ...Document) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, doc := range docs {
+		replaced := false
+		for i := range b.docs {
+			if b.docs[i].ID == doc.ID {
+				b.docs[i] = doc
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			b.docs = append(b.docs, doc)
+		}
+	}
+	return b.save()
+}
+
+func (b *jsonBackend) Delete(ids ...string) error {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	kept := b.docs[:0]
+	for _, doc := range b.docs {
+		if !drop[doc.ID] {
+			kept = append(kept, doc)
+		}
+	}
+	b.docs = kept
+	return b.save()
+}
+
+func (b *jsonBackend) Close() error { return nil }
+
+// Archive: JSON formatında arşiv yan dosyada ('.archive.json') tutulur. Önce arşiv yazılır, sonra canlı dosya.
+func (b *jsonBackend) Archive(reason string, docs ...Document) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	archived, err := b.readArchive()
+	if err != nil {
+		return err
+	}
+	drop := make(map[string]bool, len(docs))
+	now := time.Now()
+	for _, doc := range docs {
+		drop[doc.ID] = true
+		archived = append(archived, ArchivedDocument{Document: doc, ArchivedAt: now, Reason: reason})
+	}
+	if err := b.writeArchive(archived); err != nil {
+		return err
+	}
+
+	kept := b.docs[:0]
+	for _, doc := range b.docs {
+		if !drop[doc.ID] {
+			kept = append(kept, doc)
+		}
+	}
+	b.docs = kept
+	return b.save()
+}
+
+func (b *jsonBackend) Archived() ([]ArchivedDocument, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.readArchive()
+}
+
+// Unarchive: Önce canlı dosya yazılır, sonra arşivden çıkarılır (Yarıda kalırsa kayıt iki yerde de olur, kaybolmaz).
+func (b *jsonBackend) Unarchive(ids ...string) ([]Document, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	archived, err := b.readArchive()
+	if err != nil {
+		return nil, err
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	var restored []Document
+	kept := archived[:0]
+	for _, a := range archived {
+		if want[a.ID] {
+			restored = append(restored, a.Document)
+			continue
+		}
+		kept = append(kept, a)
+	}
+	if len(restored) == 0 {
+		return nil, nil
+	}
+
+	b.docs = append(b.docs, restored...)
+	if err := b.save(); err != nil {
+		return nil, err
+	}
+	return restored, b.writeArchive(kept)
+}
+
+func (b *jsonBackend) readArchive() ([]ArchivedDocument, error) {
+	var archived []ArchivedDocument
+	data, err := os.ReadFile(b.path + ".archive.json")
+	if os.IsNotExist(err) {
+		return archived, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &archived); err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+func (b *jsonBackend) writeArchive(archived []ArchivedDocument) error {
+	data, err := json.MarshalIndent(archived, "", "  ")
+	if err != nil {
+		return err
+	}
+	tempPath := b.path + ".archive.json.tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, b.path+".archive.json")
+}
+
+// Meta: JSON formatında ayarlar yan dosyada ('.meta.json') tutulur.
+func (b *jsonBackend) Meta(key string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.readMeta()[key]
+}
+
+func (b *jsonBackend) SetMeta(key, value string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	meta := b.readMeta()
+	meta[key] = value
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.path+".meta.json", data, 0644)
+}
+
+func (b *jsonBackend) readMeta() map[string]string {
+	meta := make(map[string]string)
+	if data, err := os.ReadFile(b.path + ".meta.json"); err == nil {
+		json.Unmarshal(data, &meta)
+	}
+	return meta
+}
+
+// save: Kilit altında çağrılmalıdır.
+func (b *jsonBackend) save() error {
+	data, err := json.MarshalIndent(b.docs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(b.path); dir != "" {
+		os.MkdirAll(dir, 0755)
+	}
+
+	// 🚀 ATOMIC WRITE: Yazma yarıda kesilirse eski hafıza bozulmasın
+	tempPath := b.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, b.path)
+}
+
+// readJSONDocuments: JSON hafıza dosyasını okur (Dosya yoksa boş liste döner).
+func readJSONDocuments(path string) ([]Document, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var docs []Document
+	if err := json.Unmarshal(data, &docs); err != nil {
+		logger.Warn("⚠️ Hafıza dosyası bozuk (%s): %v", path, err)
+		return nil, err
+	}
+	return docs, nil
+}
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// ConsolidateOptions: Hafıza toparlama (birleştirme + süre aşımı) ayarları. Sıfır değerler varsayılanla doldurulur.
+type ConsolidateOptions struct {
+	Similarity     float64       // Aynı kümeye girmek için en düşük kosinüs benzerliği (Varsayılan: 0.9)
+	MaxCluster     int           // Tek özete birleştirilecek en fazla kayıt (Varsayılan: 8)
+	TTL            time.Duration // Bu yaştan eski otomatik kayıtlar (sohbet dökümleri, özetler) arşivlenir (0 = kapalı)
+	KeepImportance float64       // Bu önem puanı ve üstündeki kayıtlar süre aşımıyla arşivlenmez (Varsayılan: 0.8)
+	DryRun         bool          // Sadece rapor üret, hiçbir şeyi değiştirme
+}
+
+// ConsolidateReport: Toparlama işinin özeti
+type ConsolidateReport struct {
+	Clusters  int      `json:"clusters"`  // Bulunan benzer kayıt kümesi
+	Merged    int      `json:"merged"`    // Özete birleştirilip arşivlenen kayıt
+	Summaries []string `json:"summaries"` // Oluşturulan özet kayıtlarının ID'leri
+	Expired   int      `json:"expired"`   // Süre aşımıyla arşivlenen kayıt
+	Skipped   int      `json:"skipped"`   // Özeti üretilemediği için dokunulmayan küme
+	DryRun    bool     `json:"dry_run"`
+}
+
+func (o ConsolidateOptions) withDefaults() ConsolidateOptions {
+	if o.Similarity <= 0 {
+		o.Similarity = 0.9
+	}
+	if o.MaxCluster < 2 {
+		o.MaxCluster = 8
+	}
+	if o.KeepImportance <= 0 {
+		o.KeepImportance = 0.8
+	}
+	return o
+}
+
+// Consolidate: Aynı ad alanındaki neredeyse aynı kayıtları kümeler, her kümeyi beyne yazdırılan tek bir özetle
+// değiştirir (özet kaynak ID'lerini taşır) ve süresi dolan kayıtları arşivler. Hiçbir kayıt silinmez;
+// birleştirilen ve süresi dolan kayıtlar arşive taşınır ve Restore ile geri alınabilir.
+func (vs *VectorStore) Consolidate(ctx context.Context, opts ConsolidateOptions) (*ConsolidateReport, error) {
+	opts = opts.withDefaults()
+	report := &ConsolidateReport{DryRun: opts.DryRun}
+	started := time.Now()
+
+	clusters := vs.findClusters(opts)
+	report.Clusters = len(clusters)
+
+	merged := make(map[string]bool)
+	for _, cluster := range clusters {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+		for _, doc := range cluster {
+			merged[doc.ID] = true
+		}
+		if opts.DryRun {
+			report.Merged += len(cluster)
+			continue
+		}
+
+		id, err := vs.mergeCluster(ctx, cluster)
+		if err != nil {
+			logger.Warn("⚠️ Hafıza kümesi birleştirilemedi (%d kayıt): %v", len(cluster), err)
+			report.Skipped++
+			continue
+		}
+		report.Summaries = append(report.Summaries, id)
+		report.Merged += len(cluster)
+	}
+
+	if opts.TTL > 0 {
+		expired := vs.expiredIDs(opts, merged, started)
+		if opts.DryRun {
+			report.Expired = len(expired)
+		} else if len(expired) > 0 {
+			n, err := vs.Archive(ctx, "ttl", expired...)
+			if err != nil {
+				return report, err
+			}
+			report.Expired = n
+		}
+	}
+
+	if !opts.DryRun && (report.Merged > 0 || report.Expired > 0) {
+		logger.Success("🧹 Hafıza toparlandı: %d kayıt %d özete birleştirildi, %d kayıt süre aşımıyla arşivlendi (%v)",
+			report.Merged, len(report.Summaries), report.Expired, time.Since(started).Round(time.Millisecond))
+	}
+	return report, nil
+}
+
+// findClusters: Her kayıt için aynı ad alanında eşiği geçen komşularını toplar (Açgözlü, her kayıt en fazla bir kümede).
+// Sadece aktif embedding modelinin vektörleri kıyaslanır; sabitlenmiş kayıtlar kümelenmez.
+func (vs *VectorStore) findClusters(opts ConsolidateOptions) [][]Document {
+	model := vs.embeddingModel()
+
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	order := append([]Document(nil), vs.docs...)
+	sort.Slice(order, func(i, j int) bool { return order[i].CreatedAt.Before(order[j].CreatedAt) })
+
+	assigned := make(map[string]bool)
+	var clusters [][]Document
+	for _, seed := range order {
+		if assigned[seed.ID] || Pinned(seed) || len(seed.Embedding) == 0 || !compatible(seed, model, len(seed.Embedding)) {
+			continue
+		}
+		filter := func(doc Document) bool {
+			return doc.ID != seed.ID && doc.Namespace == seed.Namespace && !assigned[doc.ID] && !Pinned(doc)
+		}
+		hits := vs.vectorCandidates(model, seed.Embedding, opts.MaxCluster-1, opts.Similarity, filter)
+		if len(hits) == 0 {
+			continue
+		}
+
+		cluster := []Document{seed}
+		assigned[seed.ID] = true
+		for _, hit := range hits {
+			assigned[hit.ID] = true
+			cluster = append(cluster, vs.docs[vs.byID[hit.ID]])
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// mergeCluster: Kümeyi tek özet kayda çevirir ve kaynakları arşivler. Özet kaydın ID'sini döner.
+func (vs *VectorStore) mergeCluster(ctx context.Context, cluster []Document) (string, error) {
+	summary, err := vs.summarize(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	sources := make([]string, len(cluster))
+	tagSet := make(map[string]bool)
+	importance := 0.5
+	for i, doc := range cluster {
+		sources[i] = doc.ID
+		for _, tag := range kernel.MetadataStrings(doc.Metadata, "tags") {
+			tagSet[tag] = true
+		}
+		if imp := Importance(doc); imp > importance {
+			importance = imp
+		}
+	}
+	metadata := map[string]interface{}{
+		"source":     "consolidation",
+		"kind":       "summary",
+		"sources":    sources,
+		"importance": importance,
+	}
+	if len(tagSet) > 0 {
+		tags := make([]string, 0, len(tagSet))
+		for tag := range tagSet {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		metadata["tags"] = tags
+	}
+
+	id, err := vs.Save(ctx, cluster[0].Namespace, summary, metadata)
+	if err != nil {
+		return "", err
+	}
+	if _, err := vs.Archive(ctx, "merged:"+id, sources...); err != nil {
+		// Özet kaldı ama kaynaklar da canlı: Bilgi kaybı yok, bir sonraki turda tekrar denenir
+		return id, fmt.Errorf("kaynaklar arşivlenemedi: %v", err)
+	}
+	logger.Debug("🧹 %d kayıt özete birleştirildi [%s]", len(cluster), id)
+	return id, nil
+}
+
+// summarize: Birebir aynı kayıtlar için beyne gitmeden tek kopyayı, değilse beynin yazdığı özeti döner.
+func (vs *VectorStore) summarize(ctx context.Context, cluster []Document) (string, error) {
+	first := strings.TrimSpace(cluster[0].Content)
+	identical := true
+	for _, doc := range cluster[1:] {
+		if strings.TrimSpace(doc.Content) != first {
+			identical = false
+			break
+		}
+	}
+	if identical {
+		return first, nil
+	}
+
+	var sb strings.Builder
+	for i, doc := range cluster {
+		sb.WriteString(fmt.Sprintf("%d. (%s) %s\n", i+1, doc.CreatedAt.Format("2006-01-02"), doc.Content))
+	}
+	history := []kernel.Message{
+		{Role: "system", Content: "Sen bir hafıza düzenleyicisisin. Sana verilen birbirine çok benzeyen hafıza kayıtlarını, " +
+			"içlerindeki tüm somut bilgileri (isimler, ID'ler, sunucu adları, tarihler, kararlar) koruyarak TEK ve kısa bir kayda birleştir. " +
+			"Çelişki varsa en yeni tarihli kaydı esas al. Sohbet süsünü ve tekrarları at. Sadece birleştirilmiş kaydı yaz, açıklama ekleme."},
+		{Role: "user", Content: sb.String()},
+	}
+	resp, err := vs.Brain.Chat(ctx, history, nil)
+	if err != nil {
+		return "", fmt.Errorf("özet üretilemedi: %v", err)
+	}
+	summary := strings.TrimSpace(resp.Content)
+	if summary == "" {
+		return "", fmt.Errorf("beyin boş özet döndü")
+	}
+	return summary, nil
+}
+
+// expirable: Süre aşımı yalnızca otomatik kayıtlara (sohbet dökümleri ve onların özetleri) uygulanır.
+// memory_save ile bilerek kaydedilenler ve içe alınan bilgi belgeleri süreyle arşivlenmez.
+func expirable(doc Document) bool {
+	switch doc.Metadata["kind"] {
+	case "transcript", "summary":
+		return true
+	}
+	return false
+}
+
+// expiredIDs: TTL'i aşmış, otomatik, sabitlenmemiş, önemsiz ve bu turda birleştirilmemiş kayıtlar.
+func (vs *VectorStore) expiredIDs(opts ConsolidateOptions, skip map[string]bool, now time.Time) []string {
+	cutoff := now.Add(-opts.TTL)
+
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	var ids []string
+	for _, doc := range vs.docs {
+		if skip[doc.ID] || !expirable(doc) || Pinned(doc) || Importance(doc) >= opts.KeepImportance || !doc.CreatedAt.Before(cutoff) {
+			continue
+		}
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+// StartConsolidation: Toparlama işini verilen aralıkla arka planda çalıştırır (ctx iptal edilene kadar).
+func (vs *VectorStore) StartConsolidation(ctx context.Context, interval time.Duration, opts ConsolidateOptions) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := vs.Consolidate(ctx, opts); err != nil && ctx.Err() == nil {
+					logger.Warn("⚠️ Hafıza toparlama hatası: %v", err)
+				}
+			}
+		}
+	}()
+}
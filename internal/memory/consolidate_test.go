@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiredIDsOnlyAutomaticRecords(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-100 * 24 * time.Hour)
+	vs := &VectorStore{docs: []Document{
+		{ID: "döküm", CreatedAt: old, Metadata: map[string]interface{}{"kind": "transcript", "importance": 0.3}},
+		{ID: "özet", CreatedAt: old, Metadata: map[string]interface{}{"kind": "summary"}},
+		{ID: "açık", CreatedAt: old, Metadata: map[string]interface{}{"source": "model"}},
+		{ID: "bilgi", CreatedAt: old, Metadata: map[string]interface{}{"kind": "knowledge"}},
+		{ID: "sabit", CreatedAt: old, Metadata: map[string]interface{}{"kind": "transcript", "pinned": true}},
+		{ID: "önemli", CreatedAt: old, Metadata: map[string]interface{}{"kind": "transcript", "importance": 0.9}},
+		{ID: "yeni", CreatedAt: now, Metadata: map[string]interface{}{"kind": "transcript"}},
+		{ID: "birleşti", CreatedAt: old, Metadata: map[string]interface{}{"kind": "transcript"}},
+	}}
+	opts := ConsolidateOptions{TTL: 90 * 24 * time.Hour}.withDefaults()
+	got := vs.expiredIDs(opts, map[string]bool{"birleşti": true}, now)
+	if len(got) != 2 || got[0] != "döküm" || got[1] != "özet" {
+		t.Fatalf("süresi dolanlar %v, beklenen [döküm özet]", got)
+	}
+}
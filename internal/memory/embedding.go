@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// metaEmbeddingModel: Store'un en son tamamen göç ettiği embedding modelinin meta anahtarı
+const metaEmbeddingModel = "embedding_model"
+
+// metaEmbeddingConfigured: Son tamamlanan göç sırasında config'te (başlangıçta) seçili olan model.
+// Config o zamandan beri değişmediyse yeniden başlatmada göçün modeli benimsenir.
+const metaEmbeddingConfigured = "embedding_model_configured"
+
+// embeddingModel: Beynin aktif embedding modeli (Beyin model bilgisi vermiyorsa boş)
+func (vs *VectorStore) embeddingModel() string {
+	if m, ok := vs.Brain.(kernel.EmbeddingModeler); ok {
+		return m.EmbeddingModel()
+	}
+	return ""
+}
+
+// compatible: Dokümanın vektörü verilen model/boyuttaki bir sorguyla kıyaslanabilir mi?
+// İki taraf da modelini biliyorsa modeller aynı olmalı; model bilgisi olmayan eski kayıtlarda boyut yeterlidir.
+func compatible(doc Document, model string, dim int) bool {
+	if len(doc.Embedding) != dim {
+		return false
+	}
+	if doc.EmbeddingModel != "" && model != "" {
+		return doc.EmbeddingModel == model
+	}
+	return true
+}
+
+// checkEmbeddingModel: Model bilgisi olmayan eski kayıtları store'un kayıtlı modeline bağlar ve
+// kayıtlı model ile beynin modeli farklıysa uyarır. Eski modelin vektörleri göç tamamlanana kadar
+// anlamsal aramada görünmez (kelime araması çalışmaya devam eder).
+// Config'teki model 'reembed' ile göç edildiğinden beri değişmediyse beyin göçün modeline geçirilir.
+func (vs *VectorStore) checkEmbeddingModel() {
+	current := vs.embeddingModel()
+	if current == "" {
+		return
+	}
+	vs.configuredModel = current
+	recorded := vs.backend.Meta(metaEmbeddingModel)
+	if recorded == "" {
+		// İlk çalıştırma veya model kaydı öncesi store: Mevcut vektörlerin bu modelle üretildiği varsayılır
+		recorded = current
+		if err := vs.backend.SetMeta(metaEmbeddingModel, current); err != nil {
+			logger.Warn("⚠️ Embedding modeli kaydedilemedi: %v", err)
+		}
+	} else if recorded != current && vs.backend.Meta(metaEmbeddingConfigured) == current {
+		vs.Brain.(kernel.EmbeddingModeler).SetEmbeddingModel(recorded)
+		logger.Info("🔁 Embedding modeli hafızanın göç edildiği modele ayarlandı: %s (config: %s)", recorded, current)
+		current = recorded
+	}
+
+	stale := 0
+	vs.mu.Lock()
+	for i := range vs.docs {
+		if vs.docs[i].EmbeddingModel == "" {
+			vs.docs[i].EmbeddingModel = recorded
+		}
+		if vs.docs[i].EmbeddingModel != current {
+			stale++
+		}
+	}
+	vs.recountIndexableLocked()
+	vs.mu.Unlock()
+
+	if stale > 0 {
+		logger.Warn("⚠️ %d kayıt farklı bir embedding modeliyle (%s) üretilmiş, aktif model: %s. Bu kayıtlar anlamsal aramada görünmez; 'memory_admin' aracıyla 'reembed' çalıştır.",
+			stale, recorded, current)
+	}
+}
@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	importBatchSize = 200      // Bu kadar kayıtta bir diske toplu yazılır
+	maxImportLine   = 16 << 20 // Tek satır (vektörlü kayıt) için üst sınır
+)
+
+// ExportRecord: Taşınabilir JSONL dökümündeki tek satır. Vektör opsiyoneldir; yoksa içe aktarırken yeniden üretilir.
+type ExportRecord struct {
+	ID             string                 `json:"id"`
+	Namespace      string                 `json:"namespace"`
+	Content        string                 `json:"content"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	EmbeddingModel string                 `json:"embedding_model,omitempty"`
+	Embedding      []float32              `json:"embedding,omitempty"`
+}
+
+// ExportOptions: Dışa aktarma ayarları
+type ExportOptions struct {
+	Namespaces []string // Boşsa tüm ad alanları
+	Embeddings bool     // Vektörler de yazılsın mı (Aynı embedding modelini kullanan hedefte yeniden üretmeyi önler)
+}
+
+// ImportOptions: İçe aktarma ayarları
+type ImportOptions struct {
+	Namespace string // Doluysa tüm kayıtlar bu ad alanına yazılır (Örn: başka bir ekibin hafızasını project:x altına almak)
+}
+
+// ImportReport: İçe aktarma sonucu
+type ImportReport struct {
+	Imported   int      `json:"imported"`   // Yazılan kayıt
+	Reembedded int      `json:"reembedded"` // Vektörü olmadığı veya farklı modelden geldiği için yeniden üretilen
+	Skipped    int      `json:"skipped"`    // ID'si hafızada zaten olduğu için atlanan
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// Lookup: Dokümanı (embedding bilgisiyle birlikte) ID'si ile getirir.
+func (vs *VectorStore) Lookup(id string) (Document, bool) {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	idx, ok := vs.byID[id]
+	if !ok {
+		return Document{}, false
+	}
+	return vs.docs[idx], true
+}
+
+// Export: Kayıtları oluşturulma sırasıyla, satır başına bir JSON nesnesi (JSONL) olarak yazar. Yazılan kayıt sayısını döner.
+func (vs *VectorStore) Export(ctx context.Context, w io.Writer, opts ExportOptions) (int, error) {
+	filter := QueryFilter(kernel.MemoryQuery{Namespaces: opts.Namespaces})
+
+	vs.mu.RLock()
+	var docs []Document
+	for _, doc := range vs.docs {
+		if filter(doc) {
+			docs = append(docs, doc)
+		}
+	}
+	vs.mu.RUnlock()
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].CreatedAt.Before(docs[j].CreatedAt) })
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	for i, doc := range docs {
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+		rec := ExportRecord{
+			ID:             doc.ID,
+			Namespace:      doc.Namespace,
+			Content:        doc.Content,
+			Metadata:       doc.Metadata,
+			CreatedAt:      doc.CreatedAt,
+			EmbeddingModel: doc.EmbeddingModel,
+		}
+		if opts.Embeddings {
+			rec.Embedding = doc.Embedding
+		}
+		if err := enc.Encode(rec); err != nil {
+			return i, fmt.Errorf("kayıt yazılamadı [%s]: %v", doc.ID, err)
+		}
+	}
+	return len(docs), bw.Flush()
+}
+
+// Import: JSONL dökümünü okur ve hafızada olmayan kayıtları ID'leri, tarihleri ve metadata'larıyla ekler.
+// Vektörü olmayan veya başka bir embedding modeliyle üretilmiş kayıtlar aktif modelle yeniden embed edilir.
+// Bozuk satırlar atlanıp rapora yazılır; aynı döküm tekrar aktarılırsa hiçbir şey çoğalmaz.
+func (vs *VectorStore) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	if opts.Namespace != "" {
+		if err := kernel.ValidNamespace(opts.Namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	model := vs.embeddingModel()
+	report := &ImportReport{}
+	started := time.Now()
+	seen := make(map[string]bool)
+	var batch []Document
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := vs.insert(batch...); err != nil {
+			return err
+		}
+		report.Imported += len(batch)
+		batch = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		var rec ExportRecord
+		if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("satır %d: geçersiz JSON: %v", line, err))
+			continue
+		}
+		if strings.TrimSpace(rec.Content) == "" {
+			report.Errors = append(report.Errors, fmt.Sprintf("satır %d: içerik boş", line))
+			continue
+		}
+		if rec.ID == "" {
+			rec.ID = uuid.New().String()
+		}
+		if _, exists := vs.Lookup(rec.ID); exists || seen[rec.ID] {
+			report.Skipped++
+			continue
+		}
+		if opts.Namespace != "" {
+			rec.Namespace = opts.Namespace
+		}
+		if rec.Namespace == "" {
+			rec.Namespace = kernel.NamespaceShared
+		}
+		if err := kernel.ValidNamespace(rec.Namespace); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("satır %d: %v", line, err))
+			continue
+		}
+		if rec.CreatedAt.IsZero() {
+			rec.CreatedAt = time.Now()
+		}
+
+		// Vektör ancak aynı modelden geliyorsa kullanılır (Farklı modellerin vektörleri kıyaslanamaz)
+		vector, vecModel := rec.Embedding, rec.EmbeddingModel
+		if len(vector) == 0 || (model != "" && vecModel != model) {
+			vec, err := vs.Brain.Embed(ctx, rec.Content)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("satır %d: embedding hatası: %v", line, err))
+				continue
+			}
+			vector, vecModel = vec, model
+			report.Reembedded++
+		}
+
+		seen[rec.ID] = true
+		batch = append(batch, Document{
+			ID:             rec.ID,
+			Namespace:      rec.Namespace,
+			Content:        rec.Content,
+			Metadata:       rec.Metadata,
+			Embedding:      vector,
+			EmbeddingModel: vecModel,
+			EmbeddingDim:   len(vector),
+			CreatedAt:      rec.CreatedAt,
+		})
+		if len(batch) >= importBatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("döküm okunamadı (satır %d): %v", line+1, err)
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	logger.Success("📥 Hafıza içe aktarıldı: %d kayıt (%d yeniden embed), %d zaten vardı, %d hata (%v)",
+		report.Imported, report.Reembedded, report.Skipped, len(report.Errors), time.Since(started).Round(time.Millisecond))
+	return report, nil
+}
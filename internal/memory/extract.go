@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"html"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// textBlock: Dosyanın yapısal birimi (Markdown bölümü, fonksiyon/sınıf veya paragraf)
+type textBlock struct {
+	Heading string // Bölüm başlığı zinciri veya fonksiyon imzası (Parçaya bağlam olarak eklenir)
+	Start   int    // 1 tabanlı ilk satır
+	Lines   []string
+}
+
+// textChunk: Embedding'e gidecek tekil parça
+type textChunk struct {
+	Heading    string
+	Start, End int // 1 tabanlı satır aralığı
+	Text       string
+}
+
+// fileKind: Uzantıya göre çıkarıcı türü (Desteklenmeyen dosyalar için boş)
+func fileKind(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".md", ".markdown", ".mdx":
+		return "markdown"
+	case ".html", ".htm":
+		return "html"
+	case ".txt", ".rst", ".adoc", ".org", ".log", ".csv", ".ini", ".cfg", ".conf", ".toml", ".yaml", ".yml", ".json":
+		return "text"
+	case ".go", ".py", ".js", ".jsx", ".ts", ".tsx", ".java", ".kt", ".c", ".h", ".cc", ".cpp", ".hpp", ".cs",
+		".rs", ".rb", ".php", ".swift", ".scala", ".sh", ".bash", ".ps1", ".lua", ".sql":
+		return "code"
+	}
+	switch strings.ToLower(filepath.Base(path)) {
+	case "readme", "license", "makefile", "dockerfile", "changelog":
+		return "text"
+	}
+	return ""
+}
+
+// extractBlocks: Dosya içeriğini türüne göre yapısal bloklara ayırır.
+func extractBlocks(kind, content string) []textBlock {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	switch kind {
+	case "markdown":
+		return markdownBlocks(strings.Split(content, "\n"))
+	case "html":
+		return markdownBlocks(strings.Split(htmlToText(content), "\n"))
+	case "code":
+		return codeBlocks(strings.Split(content, "\n"))
+	}
+	return paragraphBlocks(strings.Split(content, "\n"))
+}
+
+var mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// markdownBlocks: Her başlık yeni bir bölüm açar; başlık zinciri ("Kurulum > Linux") bölüme eklenir.
+// Kod blokları (```) içindeki '#' satırları başlık sayılmaz.
+func markdownBlocks(lines []string) []textBlock {
+	var blocks []textBlock
+	var trail []string // Seviye -> başlık
+	current := textBlock{Start: 1}
+	inFence := false
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if m := mdHeading.FindStringSubmatch(line); m != nil && !inFence {
+			if len(current.Lines) > 0 {
+				blocks = append(blocks, current)
+			}
+			level := len(m[1])
+			if len(trail) >= level {
+				trail = trail[:level-1]
+			}
+			for len(trail) < level-1 {
+				trail = append(trail, "")
+			}
+			trail = append(trail, m[2])
+			current = textBlock{Heading: joinHeadings(trail), Start: i + 1}
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	if len(current.Lines) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+func joinHeadings(trail []string) string {
+	var parts []string
+	for _, h := range trail {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
+
+// codeDecl: Girintisiz fonksiyon/sınıf/tip tanımı başlangıçları (Çoğu dilde üst seviye birimler)
+var codeDecl = regexp.MustCompile(`^(func |def |async def |class |function |async function |export |public |private |protected |static |fn |pub |impl |type |interface |struct |enum |module |CREATE |create )`)
+
+// codeBlocks: Kodu üst seviye tanımlardan böler. Tanımın hemen üstündeki yorum/dekoratör satırları tanıma dahil edilir.
+func codeBlocks(lines []string) []textBlock {
+	var blocks []textBlock
+	current := textBlock{Start: 1}
+
+	for _, line := range lines {
+		if codeDecl.MatchString(line) && len(current.Lines) > 0 {
+			// Yorum ve dekoratörleri yeni tanıma taşı
+			split := len(current.Lines)
+			for split > 0 && isLeadingComment(current.Lines[split-1]) {
+				split--
+			}
+			head := textBlock{Heading: current.Heading, Start: current.Start, Lines: current.Lines[:split]}
+			if strings.TrimSpace(strings.Join(head.Lines, "")) != "" {
+				blocks = append(blocks, head)
+			}
+			current = textBlock{Start: current.Start + split, Lines: append([]string(nil), current.Lines[split:]...)}
+		}
+		if codeDecl.MatchString(line) {
+			current.Heading = strings.TrimSpace(strings.TrimRight(line, "{:"))
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	if len(current.Lines) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+func isLeadingComment(line string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, "//") || strings.HasPrefix(t, "#") || strings.HasPrefix(t, "@") ||
+		strings.HasPrefix(t, "/*") || strings.HasPrefix(t, "*") || strings.HasPrefix(t, "--")
+}
+
+// paragraphBlocks: Düz metni boş satırlarla ayrılan paragraflara böler.
+func paragraphBlocks(lines []string) []textBlock {
+	var blocks []textBlock
+	current := textBlock{Start: 1}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if len(current.Lines) > 0 {
+				blocks = append(blocks, current)
+			}
+			current = textBlock{Start: i + 2}
+			continue
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	if len(current.Lines) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+var (
+	htmlDrop    = regexp.MustCompile(`(?is)<(script|style|noscript|svg|head)[^>]*>.*?</(script|style|noscript|svg|head)>`)
+	htmlHeading = regexp.MustCompile(`(?is)<h([1-6])[^>]*>(.*?)</h[1-6]>`)
+	htmlBreak   = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/tr|/pre|/blockquote|/section|/article)[^>]*>`)
+	htmlTag     = regexp.MustCompile(`(?s)<[^>]+>`)
+	blankRuns   = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText: HTML'i başlıkları Markdown'a çevrilmiş düz metne indirger (Bölümleme markdownBlocks ile yapılır).
+func htmlToText(src string) string {
+	src = htmlDrop.ReplaceAllString(src, "")
+	src = htmlHeading.ReplaceAllStringFunc(src, func(m string) string {
+		parts := htmlHeading.FindStringSubmatch(m)
+		level := int(parts[1][0] - '0')
+		title := strings.Join(strings.Fields(htmlTag.ReplaceAllString(parts[2], "")), " ")
+		return "\n" + strings.Repeat("#", level) + " " + title + "\n"
+	})
+	src = htmlBreak.ReplaceAllString(src, "\n")
+	src = html.UnescapeString(htmlTag.ReplaceAllString(src, ""))
+
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
+
+// chunkBlocks: Küçük blokları boyut sınırına kadar birleştirir, sınırı aşan tek bloğu satır satır
+// örtüşmeli pencerelere böler. Parçalar asla iki ayrı bölümün/fonksiyonun ortasından kesilmez.
+func chunkBlocks(blocks []textBlock, size, overlap int) []textChunk {
+	var chunks []textChunk
+	var cur *textChunk
+
+	flush := func() {
+		if cur != nil && strings.TrimSpace(cur.Text) != "" {
+			chunks = append(chunks, *cur)
+		}
+		cur = nil
+	}
+
+	for _, b := range blocks {
+		text := strings.Join(b.Lines, "\n")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		end := b.Start + len(b.Lines) - 1
+
+		if len(text) > size {
+			flush()
+			chunks = append(chunks, splitBlock(b, size, overlap)...)
+			continue
+		}
+		if cur != nil && len(cur.Text)+len(text)+1 > size {
+			flush()
+		}
+		if cur == nil {
+			cur = &textChunk{Heading: b.Heading, Start: b.Start, End: end, Text: text}
+			continue
+		}
+		cur.Text += "\n" + text
+		cur.End = end
+	}
+	flush()
+	return chunks
+}
+
+// splitBlock: Tek büyük bloğu, her biri bir öncekinin son 'overlap' karakterlik satırlarıyla başlayan pencerelere böler.
+func splitBlock(b textBlock, size, overlap int) []textChunk {
+	var chunks []textChunk
+	i := 0
+	for i < len(b.Lines) {
+		j, length := i, 0
+		for j < len(b.Lines) && (j == i || length+len(b.Lines[j])+1 <= size) {
+			length += len(b.Lines[j]) + 1
+			j++
+		}
+		text := strings.Join(b.Lines[i:j], "\n")
+		if strings.TrimSpace(text) != "" {
+			chunks = append(chunks, textChunk{Heading: b.Heading, Start: b.Start + i, End: b.Start + j - 1, Text: text})
+		}
+		if j >= len(b.Lines) {
+			break
+		}
+
+		// Örtüşme: Sonraki pencere, bu pencerenin son satırlarını tekrar içerir (ama her turda ilerler)
+		back, olen := j, 0
+		for back-1 > i && olen+len(b.Lines[back-1])+1 <= overlap {
+			back--
+			olen += len(b.Lines[back]) + 1
+		}
+		i = back
+	}
+	return chunks
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+const (
+	maxFactKeyLen   = 100
+	maxFactValueLen = 2000
+)
+
+const factsSchema = `
+CREATE TABLE IF NOT EXISTS facts (
+	scope        TEXT NOT NULL,
+	key          TEXT NOT NULL,
+	value        TEXT NOT NULL,
+	set_by       TEXT NOT NULL DEFAULT '',
+	conversation TEXT NOT NULL DEFAULT '',
+	created_at   INTEGER NOT NULL,
+	updated_at   INTEGER NOT NULL,
+	PRIMARY KEY (scope, key)
+);`
+
+// FactStore: SQLite üzerinde kapsam (scope) + anahtar bazlı birebir bilgi deposu.
+type FactStore struct {
+	db *sql.DB
+}
+
+// OpenFactStore: Bilgi deposunu açar ve şemayı kurar.
+func OpenFactStore(path string) (*FactStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		os.MkdirAll(dir, 0755)
+	}
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("bilgi deposu açılamadı: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(factsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("bilgi deposu şeması kurulamadı: %v", err)
+	}
+	return &FactStore{db: db}, nil
+}
+
+// NormalizeFactKey: Anahtarı küçük harfe çevirir, boşlukları '_' yapar ("User Language" -> "user_language").
+func NormalizeFactKey(key string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimSpace(key))), "_")
+}
+
+// SetFact: Bilgiyi ekler veya günceller. İlk oluşturulma zamanı korunur, kaynak ve güncelleme zamanı yenilenir.
+func (s *FactStore) SetFact(ctx context.Context, fact kernel.Fact) (kernel.Fact, error) {
+	fact.Key = NormalizeFactKey(fact.Key)
+	fact.Value = strings.TrimSpace(fact.Value)
+	if fact.Scope == "" {
+		fact.Scope = kernel.NamespaceShared
+	}
+	switch {
+	case fact.Key == "":
+		return fact, fmt.Errorf("HATA: bilgi anahtarı boş olamaz")
+	case len(fact.Key) > maxFactKeyLen:
+		return fact, fmt.Errorf("HATA: bilgi anahtarı en fazla %d karakter olabilir", maxFactKeyLen)
+	case fact.Value == "":
+		return fact, fmt.Errorf("HATA: bilgi değeri boş olamaz (Silmek için 'delete' kullan)")
+	case len(fact.Value) > maxFactValueLen:
+		return fact, fmt.Errorf("HATA: bilgi değeri en fazla %d karakter olabilir; uzun metinler için hafızayı (memory_save) kullan", maxFactValueLen)
+	}
+	if err := kernel.ValidNamespace(fact.Scope); err != nil {
+		return fact, err
+	}
+
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO facts (scope, key, value, set_by, conversation, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(scope, key) DO UPDATE SET value = excluded.value, set_by = excluded.set_by,
+		conversation = excluded.conversation, updated_at = excluded.updated_at`,
+		fact.Scope, fact.Key, fact.Value, fact.SetBy, fact.Conversation, now.UnixNano(), now.UnixNano())
+	if err != nil {
+		return fact, fmt.Errorf("bilgi kaydedilemedi: %v", err)
+	}
+
+	saved, err := s.GetFact(ctx, fact.Scope, fact.Key)
+	if err != nil {
+		return fact, err
+	}
+	return *saved, nil
+}
+
+// GetFact: Kapsam ve anahtarla tek bilgiyi getirir.
+func (s *FactStore) GetFact(ctx context.Context, scope, key string) (*kernel.Fact, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT scope, key, value, set_by, conversation, created_at, updated_at
+		FROM facts WHERE scope = ? AND key = ?`, scope, NormalizeFactKey(key))
+	fact, err := scanFact(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("'%s' anahtarlı bilgi bulunamadı (%s)", key, scope)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fact, nil
+}
+
+// ListFacts: Verilen kapsamlardaki (boşsa hepsi) ve önekle başlayan bilgileri anahtar sırasıyla listeler.
+func (s *FactStore) ListFacts(ctx context.Context, scopes []string, prefix string) ([]kernel.Fact, error) {
+	query := `SELECT scope, key, value, set_by, conversation, created_at, updated_at FROM facts WHERE 1=1`
+	var args []interface{}
+	if len(scopes) > 0 {
+		query += ` AND scope IN (?` + strings.Repeat(",?", len(scopes)-1) + `)`
+		for _, sc := range scopes {
+			args = append(args, sc)
+		}
+	}
+	if prefix = NormalizeFactKey(prefix); prefix != "" {
+		query += ` AND substr(key, 1, ?) = ?`
+		args = append(args, len(prefix), prefix)
+	}
+	query += ` ORDER BY key, scope`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var facts []kernel.Fact
+	for rows.Next() {
+		fact, err := scanFact(rows)
+		if err != nil {
+			return nil, err
+		}
+		facts = append(facts, fact)
+	}
+	return facts, rows.Err()
+}
+
+// DeleteFact: Bilgiyi siler; bulunduysa true döner.
+func (s *FactStore) DeleteFact(ctx context.Context, scope, key string) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM facts WHERE scope = ? AND key = ?`, scope, NormalizeFactKey(key))
+	if err != nil {
+		return false, fmt.Errorf("bilgi silinemedi: %v", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (s *FactStore) Close() error {
+	return s.db.Close()
+}
+
+func scanFact(row interface{ Scan(...interface{}) error }) (kernel.Fact, error) {
+	var (
+		f                kernel.Fact
+		created, updated int64
+	)
+	if err := row.Scan(&f.Scope, &f.Key, &f.Value, &f.SetBy, &f.Conversation, &created, &updated); err != nil {
+		return f, err
+	}
+	f.CreatedAt = time.Unix(0, created)
+	f.UpdatedAt = time.Unix(0, updated)
+	return f, nil
+}
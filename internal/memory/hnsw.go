@@ -0,0 +1,497 @@
+package memory
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// HNSW Varsayılanları (Malkov & Yashunin, 2016)
+const (
+	hnswM              = 16  // Üst katmanlarda düğüm başına bağlantı
+	hnswM0             = 32  // En alt katmanda düğüm başına bağlantı
+	hnswEfConstruction = 128 // Ekleme sırasında taranan aday sayısı
+	hnswEfSearch       = 64  // Sorgu sırasında taranan aday sayısı (k'dan küçükse k kullanılır)
+	hnswFormatVersion  = 2   // v2: Embedding modeli indekse kaydedilir
+	hnswCompactMin     = 256 // Bundan az silinmiş düğüm için sıkıştırmaya değmez
+)
+
+// hnswNode: Grafikteki tek doküman. Silinen düğümler yönlendirme için grafikte kalır (tombstone);
+// düğümlerin dörtte birini geçince indeks Compact ile yeniden kurulur.
+type hnswNode struct {
+	ID      string
+	Vec     []float32 // Birim uzunluğa normalize edilmiş
+	Level   int
+	Links   [][]int32 // Katman -> komşu düğüm indeksleri
+	Deleted bool
+}
+
+// hnswIndex: Bellek içi yaklaşık en yakın komşu (ANN) indeksi. Eşzamanlı okumaya güvenlidir.
+type hnswIndex struct {
+	Version  int
+	Model    string // Vektörleri üreten embedding modeli (Farklı modelin sorgusu bu indekse sorulmaz)
+	Dim      int
+	Entry    int32
+	MaxLevel int
+	Nodes    []hnswNode
+
+	ids     map[string]int32
+	live    int
+	pending atomic.Int64 // Son kayıttan beri eklenen/silinen düğüm sayısı
+	rng     *rand.Rand
+	visited sync.Pool
+	mu      sync.RWMutex
+}
+
+type hnswHit struct {
+	ID    string
+	Score float64 // Kosinüs benzerliği
+}
+
+func newHNSW(model string, dim int) *hnswIndex {
+	h := &hnswIndex{Version: hnswFormatVersion, Model: model, Dim: dim, Entry: -1}
+	h.init()
+	return h
+}
+
+func (h *hnswIndex) init() {
+	h.ids = make(map[string]int32, len(h.Nodes))
+	h.live = 0
+	for i, n := range h.Nodes {
+		if !n.Deleted {
+			h.ids[n.ID] = int32(i)
+			h.live++
+		}
+	}
+	h.rng = rand.New(rand.NewSource(int64(len(h.Nodes)) + 42))
+}
+
+// Len: Silinmemiş düğüm sayısı
+func (h *hnswIndex) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.live
+}
+
+// Pending: Son kayıttan beri diske yazılmamış değişiklik sayısı
+func (h *hnswIndex) Pending() int64 {
+	return h.pending.Load()
+}
+
+// Has: Doküman indekste var mı?
+func (h *hnswIndex) Has(id string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.ids[id]
+	return ok
+}
+
+// IDs: İndeksteki (silinmemiş) doküman ID'leri
+func (h *hnswIndex) IDs() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make([]string, 0, len(h.ids))
+	for id := range h.ids {
+		out = append(out, id)
+	}
+	return out
+}
+
+// Tombstones: Grafikte yönlendirici olarak kalan silinmiş düğüm sayısı
+func (h *hnswIndex) Tombstones() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.Nodes) - h.live
+}
+
+// NeedsCompaction: Silinmiş düğümler en az hnswCompactMin ve tüm düğümlerin dörtte biri kadarsa true
+func (h *hnswIndex) NeedsCompaction() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	dead := len(h.Nodes) - h.live
+	return dead >= hnswCompactMin && dead*4 >= len(h.Nodes)
+}
+
+// Compact: Sadece silinmemiş düğümlerden yeni bir indeks kurar (Grafik baştan inşa edilir).
+func (h *hnswIndex) Compact() *hnswIndex {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := newHNSW(h.Model, h.Dim)
+	for _, n := range h.Nodes {
+		if !n.Deleted {
+			out.Add(n.ID, n.Vec)
+		}
+	}
+	return out
+}
+
+// Add: Dokümanı grafiğe ekler. Boyutu uymayan veya zaten var olan vektörler reddedilir.
+func (h *hnswIndex) Add(id string, vec []float32) error {
+	q := normalize(vec)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.Dim == 0 && h.Entry < 0 {
+		h.Dim = len(vec) // Boş indeks ilk vektörün boyutunu benimser
+	}
+	if len(vec) != h.Dim {
+		return fmt.Errorf("vektör boyutu uyuşmuyor: %d (indeks: %d)", len(vec), h.Dim)
+	}
+
+	if _, ok := h.ids[id]; ok {
+		return nil
+	}
+
+	level := int(-math.Log(1-h.rng.Float64()) / math.Log(hnswM))
+	n := int32(len(h.Nodes))
+	h.Nodes = append(h.Nodes, hnswNode{ID: id, Vec: q, Level: level, Links: make([][]int32, level+1)})
+	h.ids[id] = n
+	h.live++
+	h.pending.Add(1)
+
+	if h.Entry < 0 {
+		h.Entry, h.MaxLevel = n, level
+		return nil
+	}
+
+	ep := h.Entry
+	for l := h.MaxLevel; l > level; l-- {
+		ep = h.greedy(q, ep, l)
+	}
+
+	for l := min(level, h.MaxLevel); l >= 0; l-- {
+		cands := h.searchLayer(q, ep, hnswEfConstruction, l)
+		neighbors := h.selectNeighbors(cands, hnswM)
+		h.Nodes[n].Links[l] = neighbors
+
+		for _, nb := range neighbors {
+			links := append(h.Nodes[nb].Links[l], n)
+			if limit := maxLinks(l); len(links) > limit {
+				links = h.prune(nb, links, limit)
+			}
+			h.Nodes[nb].Links[l] = links
+		}
+		ep = cands[0].node
+	}
+
+	if level > h.MaxLevel {
+		h.Entry, h.MaxLevel = n, level
+	}
+	return nil
+}
+
+// Delete: Dokümanı sonuçlardan çıkarır (Düğüm grafikte yönlendirici olarak kalır).
+func (h *hnswIndex) Delete(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if n, ok := h.ids[id]; ok {
+		h.Nodes[n].Deleted = true
+		delete(h.ids, id)
+		h.live--
+		h.pending.Add(1)
+	}
+}
+
+// Search: Sorguya en benzer k dokümanı (kosinüs benzerliğine göre azalan) döner.
+func (h *hnswIndex) Search(vec []float32, k int) []hnswHit {
+	if len(vec) != h.Dim || k <= 0 {
+		return nil
+	}
+	q := normalize(vec)
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.Entry < 0 {
+		return nil
+	}
+	ep := h.Entry
+	for l := h.MaxLevel; l > 0; l-- {
+		ep = h.greedy(q, ep, l)
+	}
+
+	ef := hnswEfSearch
+	if k > ef {
+		ef = k
+	}
+	hits := make([]hnswHit, 0, k)
+	for _, c := range h.searchLayer(q, ep, ef, 0) {
+		if h.Nodes[c.node].Deleted {
+			continue
+		}
+		hits = append(hits, hnswHit{ID: h.Nodes[c.node].ID, Score: 1 - float64(c.dist)})
+		if len(hits) == k {
+			break
+		}
+	}
+	return hits
+}
+
+// -- Grafik Algoritmaları (Kilit altında çağrılır) --
+
+func maxLinks(level int) int {
+	if level == 0 {
+		return hnswM0
+	}
+	return hnswM
+}
+
+func (h *hnswIndex) dist(q []float32, n int32) float32 {
+	return 1 - dot(q, h.Nodes[n].Vec)
+}
+
+// greedy: Üst katmanlarda sorguya en yakın düğüme açgözlü iniş
+func (h *hnswIndex) greedy(q []float32, ep int32, level int) int32 {
+	best := h.dist(q, ep)
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range h.Nodes[ep].Links[level] {
+			if d := h.dist(q, nb); d < best {
+				best, ep, changed = d, nb, true
+			}
+		}
+	}
+	return ep
+}
+
+// searchLayer: Tek katmanda ef genişliğinde arama; sonuçlar mesafeye göre artan sıralıdır.
+func (h *hnswIndex) searchLayer(q []float32, ep int32, ef int, level int) []hnswCandidate {
+	visited := h.acquireVisited()
+	defer h.visited.Put(visited)
+
+	visited.mark(ep)
+	start := hnswCandidate{node: ep, dist: h.dist(q, ep)}
+	cands := &minHeap{start}
+	results := &maxHeap{start}
+
+	for cands.Len() > 0 {
+		c := heap.Pop(cands).(hnswCandidate)
+		if c.dist > (*results)[0].dist && results.Len() >= ef {
+			break
+		}
+		for _, nb := range h.Nodes[c.node].Links[level] {
+			if visited.mark(nb) {
+				continue
+			}
+			d := h.dist(q, nb)
+			if results.Len() < ef || d < (*results)[0].dist {
+				heap.Push(cands, hnswCandidate{node: nb, dist: d})
+				heap.Push(results, hnswCandidate{node: nb, dist: d})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := make([]hnswCandidate, results.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(results).(hnswCandidate)
+	}
+	return out
+}
+
+// selectNeighbors: Sezgisel seçim; adaylar arasında birbirine çok yakın olanları eleyip grafiği çeşitlendirir.
+func (h *hnswIndex) selectNeighbors(cands []hnswCandidate, m int) []int32 {
+	selected := make([]int32, 0, m)
+	var skipped []int32
+	for _, c := range cands {
+		if len(selected) >= m {
+			break
+		}
+		good := true
+		for _, s := range selected {
+			if 1-dot(h.Nodes[c.node].Vec, h.Nodes[s].Vec) < c.dist {
+				good = false
+				break
+			}
+		}
+		if good {
+			selected = append(selected, c.node)
+		} else {
+			skipped = append(skipped, c.node)
+		}
+	}
+	// Yer kaldıysa elenenlerle doldur (bağlantısız düğüm kalmasın)
+	for _, s := range skipped {
+		if len(selected) >= m {
+			break
+		}
+		selected = append(selected, s)
+	}
+	return selected
+}
+
+// prune: Komşu listesi taşınca düğüme en uygun 'limit' bağlantıyı bırakır.
+func (h *hnswIndex) prune(n int32, links []int32, limit int) []int32 {
+	cands := make([]hnswCandidate, len(links))
+	for i, l := range links {
+		cands[i] = hnswCandidate{node: l, dist: h.dist(h.Nodes[n].Vec, l)}
+	}
+	sortCandidates(cands)
+	return h.selectNeighbors(cands, limit)
+}
+
+// -- Kalıcılık (Store dosyasının yanında '.hnsw') --
+
+// Save: İndeksi atomik olarak diske yazar.
+func (h *hnswIndex) Save(path string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if dir := filepath.Dir(path); dir != "" {
+		os.MkdirAll(dir, 0755)
+	}
+	tempPath := path + ".tmp"
+	f, err := os.Create(tempPath)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(h); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	h.pending.Store(0)
+	return os.Rename(tempPath, path)
+}
+
+// loadHNSW: Diskteki indeksi okur. Sürüm uyuşmazsa hata döner (yeniden inşa edilmeli).
+func loadHNSW(path string) (*hnswIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := &hnswIndex{}
+	if err := gob.NewDecoder(f).Decode(h); err != nil {
+		return nil, fmt.Errorf("indeks dosyası bozuk: %v", err)
+	}
+	if h.Version != hnswFormatVersion {
+		return nil, fmt.Errorf("indeks sürümü eski: %d", h.Version)
+	}
+	h.init()
+	return h, nil
+}
+
+// -- Yardımcı Yapılar --
+
+type hnswCandidate struct {
+	node int32
+	dist float32
+}
+
+type minHeap []hnswCandidate
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].dist < h[j].dist }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(hnswCandidate)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+type maxHeap []hnswCandidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].dist > h[j].dist }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(hnswCandidate)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func sortCandidates(c []hnswCandidate) {
+	h := minHeap(c)
+	heap.Init(&h)
+	sorted := make([]hnswCandidate, 0, len(c))
+	for h.Len() > 0 {
+		sorted = append(sorted, heap.Pop(&h).(hnswCandidate))
+	}
+	copy(c, sorted)
+}
+
+// visitedList: Aramalar arasında yeniden kullanılan ziyaret işaretleri (her aramada map ayırmamak için)
+type visitedList struct {
+	marks []uint32
+	gen   uint32
+}
+
+// mark: Düğümü işaretler; zaten işaretliyse true döner.
+func (v *visitedList) mark(n int32) bool {
+	if v.marks[n] == v.gen {
+		return true
+	}
+	v.marks[n] = v.gen
+	return false
+}
+
+func (h *hnswIndex) acquireVisited() *visitedList {
+	v, _ := h.visited.Get().(*visitedList)
+	if v == nil {
+		v = &visitedList{}
+	}
+	if len(v.marks) < len(h.Nodes) {
+		v.marks = make([]uint32, len(h.Nodes)+len(h.Nodes)/4)
+		v.gen = 0
+	}
+	v.gen++
+	if v.gen == 0 { // Taşma: işaretleri sıfırla
+		for i := range v.marks {
+			v.marks[i] = 0
+		}
+		v.gen = 1
+	}
+	return v
+}
+
+func normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if norm == 0 {
+		return out
+	}
+	inv := float32(1 / math.Sqrt(norm))
+	for i, x := range v {
+		out[i] = x * inv
+	}
+	return out
+}
+
+// dot: Döngü açılımıyla (4'lü) iç çarpım; indeksin en sıcak noktası
+func dot(a, b []float32) float32 {
+	var s0, s1, s2, s3 float32
+	n := len(a)
+	b = b[:n]
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		s0 += a[i] * b[i]
+		s1 += a[i+1] * b[i+1]
+		s2 += a[i+2] * b[i+2]
+		s3 += a[i+3] * b[i+3]
+	}
+	for ; i < n; i++ {
+		s0 += a[i] * b[i]
+	}
+	return s0 + s1 + s2 + s3
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+// recallAt: HNSW sonuçlarının tam taramadaki ilk k ile örtüşme oranı
+func recallAt(idx *hnswIndex, docs [][]float32, live func(i int) bool, queries [][]float32, k int) float64 {
+	var hits, total int
+	for _, q := range queries {
+		var ids []int
+		var vecs [][]float32
+		for i, d := range docs {
+			if live(i) {
+				ids = append(ids, i)
+				vecs = append(vecs, d)
+			}
+		}
+		want := make(map[string]bool, k)
+		for _, j := range exactTopK(vecs, q, k) {
+			want[fmt.Sprint(ids[j])] = true
+		}
+		for _, h := range idx.Search(q, k) {
+			if want[h.ID] {
+				hits++
+			}
+		}
+		total += len(want)
+	}
+	return float64(hits) / float64(total)
+}
+
+func TestHNSWRecall(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	docs := syntheticEmbeddings(rng, 3000, 64)
+	queries := syntheticEmbeddings(rng, 50, 64)
+	idx := newHNSW("", 64)
+	for i, v := range docs {
+		idx.Add(fmt.Sprint(i), v)
+	}
+	all := func(int) bool { return true }
+	if r := recallAt(idx, docs, all, queries, 10); r < 0.9 {
+		t.Fatalf("recall@10 = %.3f, en az 0.9 bekleniyordu", r)
+	}
+}
+
+func TestHNSWCompact(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	docs := syntheticEmbeddings(rng, 1000, 32)
+	queries := syntheticEmbeddings(rng, 30, 32)
+	idx := newHNSW("m", 32)
+	for i, v := range docs {
+		idx.Add(fmt.Sprint(i), v)
+	}
+	deleted := func(i int) bool { return i%5 < 2 } // %40
+	for i := range docs {
+		if deleted(i) {
+			idx.Delete(fmt.Sprint(i))
+		}
+	}
+	if !idx.NeedsCompaction() {
+		t.Fatalf("%d silinmiş düğümle sıkıştırma gerekmeliydi", idx.Tombstones())
+	}
+
+	compacted := idx.Compact()
+	if compacted.Tombstones() != 0 || compacted.Len() != 600 || compacted.Model != "m" {
+		t.Fatalf("sıkıştırılmış indeks: %d canlı, %d silinmiş, model %q", compacted.Len(), compacted.Tombstones(), compacted.Model)
+	}
+	for _, q := range queries {
+		for _, h := range compacted.Search(q, 10) {
+			var i int
+			fmt.Sscan(h.ID, &i)
+			if deleted(i) {
+				t.Fatalf("silinmiş doküman döndü: %s", h.ID)
+			}
+		}
+	}
+	live := func(i int) bool { return !deleted(i) }
+	if r := recallAt(compacted, docs, live, queries, 10); r < 0.9 {
+		t.Fatalf("sıkıştırma sonrası recall@10 = %.3f", r)
+	}
+}
+
+// letterBrain: Harf sıklığından vektör üreten test beyni
+type letterBrain struct{}
+
+func (letterBrain) Chat(ctx context.Context, history []kernel.Message, tools []kernel.Tool) (*kernel.BrainResponse, error) {
+	return &kernel.BrainResponse{}, nil
+}
+
+func (letterBrain) Embed(ctx context.Context, text string) ([]float32, error) {
+	v := make([]float32, 26)
+	for _, r := range strings.ToLower(text) {
+		if r >= 'a' && r <= 'z' {
+			v[r-'a']++
+		}
+	}
+	v[0]++
+	return v, nil
+}
+
+func TestIndexUsableTracksDocuments(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	vs := NewVectorStore(filepath.Join(dir, "memory.json"), letterBrain{})
+	for _, text := range []string{"alpha", "bravo", "charlie"} {
+		if _, err := vs.Save(ctx, kernel.NamespaceShared, text, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vs.indexPath = filepath.Join(dir, "memory.json.hnsw")
+	vs.indexMinDocs = 1
+	vs.buildIndex()
+
+	usable := func(step string) {
+		t.Helper()
+		vs.mu.RLock()
+		defer vs.mu.RUnlock()
+		if !vs.indexUsable("", 26) || vs.annDocs != vs.ann.Len() {
+			t.Fatalf("%s: indeks kullanılamıyor (sayım %d, indeks %d)", step, vs.annDocs, vs.ann.Len())
+		}
+	}
+	usable("inşa")
+
+	id, err := vs.Save(ctx, kernel.NamespaceShared, "delta", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usable("ekleme")
+
+	if _, err := vs.Delete(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	usable("silme")
+
+	vs.mu.Lock()
+	vs.docs = append(vs.docs, Document{ID: "dışarıdan", Embedding: make([]float32, 26)})
+	vs.byID["dışarıdan"] = len(vs.docs) - 1
+	vs.countIndexableLocked(vs.docs[len(vs.docs)-1], 1)
+	stale := vs.indexUsable("", 26)
+	vs.mu.Unlock()
+	if stale {
+		t.Fatal("indekste olmayan doküman varken indeks kullanılmamalı")
+	}
+}
+
+func benchData(b *testing.B, n, dim int) (*hnswIndex, [][]float32, [][]float32) {
+	b.Helper()
+	rng := rand.New(rand.NewSource(3))
+	docs := syntheticEmbeddings(rng, n, dim)
+	idx := newHNSW("", dim)
+	for i, v := range docs {
+		idx.Add(fmt.Sprint(i), v)
+	}
+	return idx, docs, syntheticEmbeddings(rng, 100, dim)
+}
+
+func BenchmarkHNSWSearch(b *testing.B) {
+	idx, _, queries := benchData(b, 10000, 128)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Search(queries[i%len(queries)], 10)
+	}
+}
+
+func BenchmarkExactSearch(b *testing.B) {
+	_, docs, queries := benchData(b, 10000, 128)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		exactTopK(docs, queries[i%len(queries)], 10)
+	}
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"os"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+const (
+	defaultIndexMinDocs = 2000 // Bunun altında tam tarama zaten milisaniyeler sürer
+	indexSaveEvery      = 500  // Bu kadar değişiklikte indeks arka planda diske yazılır
+)
+
+// EnableIndex: Store'un yanında ('.hnsw') kalıcı ANN indeksini yükler veya arka planda inşa eder.
+// İndeks hazır olana kadar (ve eksik/bayat olduğu sürece) aramalar tam tarama ile yapılır.
+func (vs *VectorStore) EnableIndex(minDocs int) {
+	if minDocs <= 0 {
+		minDocs = defaultIndexMinDocs
+	}
+	vs.mu.Lock()
+	vs.indexPath = vs.FilePath + ".hnsw"
+	vs.indexMinDocs = minDocs
+	vs.mu.Unlock()
+
+	go vs.buildIndex()
+}
+
+// indexUsable: İndeks hazır, sorguyla aynı model/boyutta ve bu modelin tüm kayıtlarını içeriyorsa true. Kilit altında çağrılmalıdır.
+// Başka modelin (Örn: göç yarımken eski modelde kalan) kayıtları zaten kıyaslanmaz, sayıma (annDocs) girmez.
+func (vs *VectorStore) indexUsable(model string, queryDim int) bool {
+	if vs.ann == nil || len(vs.docs) < vs.indexMinDocs || vs.ann.Model != model || vs.ann.Dim != queryDim {
+		return false
+	}
+	return vs.ann.Len() == vs.annDocs
+}
+
+// countIndexableLocked: Eklenen (delta 1) veya çıkan (delta -1) dokümanı, indeksle uyumluysa annDocs'a yansıtır.
+// Boyutu henüz belli olmayan boş indeks ilk vektörün boyutunu benimsediği için her dokümanı sayar. Kilit altında çağrılmalıdır.
+func (vs *VectorStore) countIndexableLocked(doc Document, delta int) {
+	if vs.ann != nil && (vs.ann.Dim == 0 || compatible(doc, vs.ann.Model, vs.ann.Dim)) {
+		vs.annDocs += delta
+	}
+}
+
+// recountIndexableLocked: annDocs'u baştan sayar (İndeks değiştiğinde veya kayıtlar toplu güncellendiğinde). Kilit altında çağrılmalıdır.
+func (vs *VectorStore) recountIndexableLocked() {
+	vs.annDocs = 0
+	for i := range vs.docs {
+		vs.countIndexableLocked(vs.docs[i], 1)
+	}
+}
+
+// rebuildIndex: Silinmiş düğümler birikince indeksi arka planda sıkıştırarak yeniden kurar (Aynı anda tek inşa).
+func (vs *VectorStore) rebuildIndex() {
+	if vs.indexPath == "" || !vs.rebuilding.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer vs.rebuilding.Store(false)
+		vs.buildIndex()
+	}()
+}
+
+// buildIndex: Diskteki indeksi store ile uzlaştırır; eksikleri ekler, artık olmayanları siler.
+// Sadece aktif embedding modelinin vektörleri indekslenir. Sürüm, model veya boyut uyuşmazlığında indeks sıfırdan kurulur.
+func (vs *VectorStore) buildIndex() {
+	vs.mu.RLock()
+	snapshot := append([]Document(nil), vs.docs...)
+	path := vs.indexPath
+	vs.mu.RUnlock()
+
+	model := vs.embeddingModel()
+	dim := 0
+	for i := len(snapshot) - 1; i >= 0; i-- {
+		if model == "" || snapshot[i].EmbeddingModel == model {
+			dim = len(snapshot[i].Embedding) // Aktif modelin en yeni kaydının boyutu esas alınır
+			break
+		}
+	}
+
+	idx, err := loadHNSW(path)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Warn("⚠️ ANN indeksi okunamadı, yeniden inşa edilecek: %v", err)
+	}
+	if idx == nil || idx.Model != model || (dim != 0 && idx.Dim != dim) {
+		idx = newHNSW(model, dim)
+	}
+
+	present := make(map[string]bool, len(snapshot))
+	for _, doc := range snapshot {
+		present[doc.ID] = true
+	}
+	for id := range idx.ids {
+		if !present[id] {
+			idx.Delete(id)
+		}
+	}
+	if idx.NeedsCompaction() {
+		dropped := idx.Tombstones()
+		idx = idx.Compact()
+		logger.Info("🧭 ANN indeksi sıkıştırıldı: %d silinmiş düğüm atıldı", dropped)
+	}
+	indexable := func(doc Document) bool {
+		return idx.Dim == 0 || compatible(doc, model, idx.Dim)
+	}
+
+	started := time.Now()
+	added := 0
+	for _, doc := range snapshot {
+		if idx.Has(doc.ID) || !indexable(doc) {
+			continue
+		}
+		if err := idx.Add(doc.ID, doc.Embedding); err == nil {
+			added++
+		}
+	}
+
+	// İnşa sürerken eklenenleri kat, silinenleri çıkar ve indeksi devreye al
+	vs.mu.Lock()
+	for _, doc := range vs.docs {
+		if !idx.Has(doc.ID) && indexable(doc) {
+			idx.Add(doc.ID, doc.Embedding)
+		}
+	}
+	for _, id := range idx.IDs() {
+		if _, ok := vs.byID[id]; !ok {
+			idx.Delete(id)
+		}
+	}
+	vs.ann = idx
+	vs.recountIndexableLocked()
+	vs.mu.Unlock()
+
+	if added > 0 {
+		logger.Info("🧭 ANN indeksi hazır: %d doküman (%d yeni, %v)", idx.Len(), added, time.Since(started).Round(time.Millisecond))
+	}
+	if idx.Pending() > 0 {
+		vs.saveIndex(idx)
+	}
+}
+
+func (vs *VectorStore) saveIndex(idx *hnswIndex) {
+	vs.mu.RLock()
+	path := vs.indexPath
+	vs.mu.RUnlock()
+	if path == "" {
+		return
+	}
+	if err := idx.Save(path); err != nil {
+		logger.Warn("⚠️ ANN indeksi diske yazılamadı: %v", err)
+	}
+}
@@ -0,0 +1,343 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultChunkSize    = 1500    // Karakter
+	defaultChunkOverlap = 200     // Karakter
+	defaultMaxFileSize  = 2 << 20 // 2 MB üstü dosyalar (dump, log, minified) atlanır
+)
+
+// defaultIngestExcludes: Her zaman atlanan klasör ve dosyalar
+var defaultIngestExcludes = []string{
+	".git/**", "**/.git/**", "**/node_modules/**", "**/vendor/**", "**/__pycache__/**", "**/.venv/**", "**/venv/**",
+	"**/dist/**", "**/build/**", "**/*.min.js", "**/*.lock", "**/package-lock.json",
+}
+
+// ingestSecrets: Gizli bilgi taşıyan dosyalar. Mutlak yola göre eşlenir; hangi kökten aktarılırsa aktarılsın atlanır.
+var ingestSecrets = []string{
+	"**/config/config.yaml", "**/.env", "**/.env.*", "**/.netrc", "**/*.pem", "**/*.key", "**/id_rsa*", "**/id_ed25519*", "**/id_ecdsa*",
+	"**/.ssh/**", "**/.aws/**", "**/.gnupg/**",
+}
+
+// IngestOptions: Bilgi tabanı içe aktarma ayarları
+type IngestOptions struct {
+	Path         string   // Dosya veya klasör
+	Include      []string // Kök klasöre göre glob'lar (Örn: "**/*.md", "docs/**"). Boşsa desteklenen tüm dosyalar
+	Exclude      []string // Varsayılan hariç tutulanlara eklenir
+	Namespace    string   // Varsayılan: shared
+	Tags         []string
+	ChunkSize    int
+	ChunkOverlap int
+	MaxFileSize  int64
+}
+
+// IngestReport: İçe aktarma sonucu
+type IngestReport struct {
+	Files     int      `json:"files"`     // Taranan desteklenen dosya
+	Added     int      `json:"added"`     // İlk kez eklenen dosya
+	Updated   int      `json:"updated"`   // İçeriği değiştiği için yeniden işlenen dosya
+	Unchanged int      `json:"unchanged"` // Özeti (hash) aynı olduğu için atlanan dosya
+	Removed   int      `json:"removed"`   // Diskten silindiği için parçaları kaldırılan dosya
+	Chunks    int      `json:"chunks"`    // Yazılan yeni parça sayısı
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// Ingest: Klasörü (veya tek dosyayı) gezer, metni çıkarır, başlık/fonksiyon sınırlarına saygılı örtüşmeli parçalara
+// böler, embedding'lerini üretip hafızaya 'source' (dosya:satır) metadata'sıyla yazar.
+// Artımlıdır: Hash'i değişmeyen dosyalar atlanır, değişenlerin eski parçaları yenileriyle değiştirilir,
+// kökün altında artık olmayan dosyaların parçaları kaldırılır.
+func (vs *VectorStore) Ingest(ctx context.Context, opts IngestOptions) (*IngestReport, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = kernel.NamespaceShared
+	}
+	if err := kernel.ValidNamespace(opts.Namespace); err != nil {
+		return nil, err
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultChunkSize
+	}
+	if opts.ChunkOverlap < 0 || opts.ChunkOverlap >= opts.ChunkSize {
+		opts.ChunkOverlap = 0
+	} else if opts.ChunkOverlap == 0 {
+		opts.ChunkOverlap = defaultChunkOverlap
+	}
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = defaultMaxFileSize
+	}
+
+	root, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("HATA: yol okunamadı: %v", err)
+	}
+
+	include, err := compileGlobs(opts.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compileGlobs(append(append([]string(nil), defaultIngestExcludes...), opts.Exclude...))
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := compileGlobs(ingestSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bu kökten daha önce aktarılmış dosyalar: yol -> (hash, parça ID'leri)
+	existing := vs.ingestedFiles(root, opts.Namespace)
+
+	report := &IngestReport{}
+	seen := make(map[string]bool)
+	started := time.Now()
+
+	walkRoot := root
+	if !info.IsDir() {
+		walkRoot = filepath.Dir(root)
+	}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", path, err))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		rel, _ := filepath.Rel(walkRoot, path)
+		rel = filepath.ToSlash(rel)
+		abs := filepath.ToSlash(path)
+		if d.IsDir() {
+			if path != root && matchAny(exclude, rel+"/") {
+				return filepath.SkipDir
+			}
+			if matchAny(secrets, abs+"/") {
+				logger.Debug("🔒 Gizli klasör aktarılmadı: %s", path)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if matchAny(secrets, abs) {
+			logger.Debug("🔒 Gizli dosya aktarılmadı: %s", path)
+			return nil
+		}
+		if !d.Type().IsRegular() || matchAny(exclude, rel) || (len(include) > 0 && !matchAny(include, rel)) {
+			return nil
+		}
+		kind := fileKind(path)
+		if kind == "" {
+			return nil
+		}
+
+		report.Files++
+		seen[path] = true
+		if err := vs.ingestFile(ctx, path, rel, kind, root, opts, existing[path], report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", rel, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	// Diskten silinen dosyaların parçalarını kaldır
+	// (Filtre dışında kalan ama diskte duran dosyalara dokunulmaz; başka glob'larla aktarılmış olabilirler)
+	for path, prev := range existing {
+		if seen[path] {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if _, err := vs.Delete(ctx, prev.ids...); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		report.Removed++
+	}
+
+	logger.Success("📚 Bilgi tabanı aktarıldı (%s): %d dosya, %d yeni, %d güncel, %d değişmemiş, %d silinmiş, %d parça (%v)",
+		root, report.Files, report.Added, report.Updated, report.Unchanged, report.Removed, report.Chunks, time.Since(started).Round(time.Millisecond))
+	return report, nil
+}
+
+type ingestedFile struct {
+	hash string
+	ids  []string
+}
+
+// ingestedFiles: Verilen kökten (ve ad alanından) aktarılmış dosyaların hash'lerini ve parça ID'lerini toplar.
+func (vs *VectorStore) ingestedFiles(root, namespace string) map[string]*ingestedFile {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	files := make(map[string]*ingestedFile)
+	for _, doc := range vs.docs {
+		if doc.Namespace != namespace || doc.Metadata["kind"] != "knowledge" || doc.Metadata["ingest_root"] != root {
+			continue
+		}
+		path, _ := doc.Metadata["path"].(string)
+		f, ok := files[path]
+		if !ok {
+			hash, _ := doc.Metadata["file_hash"].(string)
+			f = &ingestedFile{hash: hash}
+			files[path] = f
+		}
+		f.ids = append(f.ids, doc.ID)
+	}
+	return files
+}
+
+// ingestFile: Tek dosyayı (hash'i değişmişse) parçalayıp yazar ve eski parçalarını kaldırır.
+func (vs *VectorStore) ingestFile(ctx context.Context, path, rel, kind, root string, opts IngestOptions, prev *ingestedFile, report *IngestReport) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() > opts.MaxFileSize {
+		return fmt.Errorf("dosya çok büyük (%d bayt), atlandı", info.Size())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !utf8.Valid(data) || strings.ContainsRune(string(data[:min(len(data), 8192)]), 0) {
+		return nil // İkili dosya
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if prev != nil && prev.hash == hash {
+		report.Unchanged++
+		return nil
+	}
+
+	chunks := chunkBlocks(extractBlocks(kind, string(data)), opts.ChunkSize, opts.ChunkOverlap)
+	model := vs.embeddingModel()
+	now := time.Now()
+	docs := make([]Document, 0, len(chunks))
+	for _, c := range chunks {
+		content := c.Text
+		if c.Heading != "" {
+			content = fmt.Sprintf("[%s > %s]\n%s", rel, c.Heading, c.Text)
+		} else {
+			content = fmt.Sprintf("[%s]\n%s", rel, c.Text)
+		}
+		vec, err := vs.Brain.Embed(ctx, content)
+		if err != nil {
+			return fmt.Errorf("embedding hatası: %v", err)
+		}
+		metadata := map[string]interface{}{
+			"source":      fmt.Sprintf("%s:%d-%d", path, c.Start, c.End),
+			"kind":        "knowledge",
+			"path":        path,
+			"lines":       fmt.Sprintf("%d-%d", c.Start, c.End),
+			"file_hash":   hash,
+			"ingest_root": root,
+			"pinned":      true, // Dosyadan türetilmiş bilgi aşınmaz, birleştirilmez; güncelliği yeniden aktarma sağlar
+		}
+		if len(opts.Tags) > 0 {
+			metadata["tags"] = opts.Tags
+		}
+		docs = append(docs, Document{
+			ID:             uuid.New().String(),
+			Namespace:      opts.Namespace,
+			Content:        content,
+			Metadata:       metadata,
+			Embedding:      vec,
+			EmbeddingModel: model,
+			EmbeddingDim:   len(vec),
+			CreatedAt:      now,
+		})
+	}
+
+	// Önce yeni parçalar yazılır, sonra eskiler silinir (Yarıda kalırsa bilgi kaybolmaz)
+	if len(docs) > 0 {
+		if err := vs.insert(docs...); err != nil {
+			return err
+		}
+	}
+	if prev != nil {
+		if _, err := vs.Delete(ctx, prev.ids...); err != nil {
+			return err
+		}
+		report.Updated++
+	} else {
+		report.Added++
+	}
+	report.Chunks += len(docs)
+	logger.Debug("📚 %s: %d parça", rel, len(docs))
+	return nil
+}
+
+// compileGlobs: "**" (herhangi sayıda klasör), "*" ve "?" destekli glob'ları regex'e çevirir.
+// Klasör ayırıcısı içermeyen desenler ("*.md") her derinlikteki dosya adıyla eşleşir.
+func compileGlobs(patterns []string) ([]*regexp.Regexp, error) {
+	var out []*regexp.Regexp
+	for _, p := range patterns {
+		p = strings.TrimSpace(filepath.ToSlash(p))
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			p = "**/" + p
+		}
+		var sb strings.Builder
+		sb.WriteString("^")
+		for i := 0; i < len(p); i++ {
+			switch c := p[i]; c {
+			case '*':
+				if i+1 < len(p) && p[i+1] == '*' {
+					i++
+					if i+1 < len(p) && p[i+1] == '/' {
+						i++
+						sb.WriteString("(?:.*/)?")
+					} else {
+						sb.WriteString(".*")
+					}
+				} else {
+					sb.WriteString("[^/]*")
+				}
+			case '?':
+				sb.WriteString("[^/]")
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		sb.WriteString("$")
+		re, err := regexp.Compile(sb.String())
+		if err != nil {
+			return nil, fmt.Errorf("HATA: geçersiz glob '%s': %v", p, err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+func matchAny(globs []*regexp.Regexp, rel string) bool {
+	for _, re := range globs {
+		if re.MatchString(rel) {
+			return true
+		}
+	}
+	return false
+}
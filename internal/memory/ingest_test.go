@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileGlobs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide.md", true}, // Eğik çizgisiz kalıp her derinlikte eşleşir
+		{"docs/*.md", "docs/guide.md", true},
+		{"docs/*.md", "docs/api/guide.md", false},
+		{"docs/**", "docs/api/guide.md", true},
+		{"**/node_modules/**", "web/node_modules/x/index.js", true},
+		{"**/node_modules/**", "node_modules/x/index.js", true},
+		{"**/*.min.js", "app.min.js", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"a.b", "axb", false}, // Nokta düz karakterdir
+	}
+	for _, tt := range tests {
+		globs, err := compileGlobs([]string{tt.pattern})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.pattern, err)
+		}
+		if got := matchAny(globs, tt.path); got != tt.match {
+			t.Errorf("'%s' ~ '%s' = %v, beklenen %v", tt.pattern, tt.path, got, tt.match)
+		}
+	}
+}
+
+func TestIngestSecretsMatchAbsolutePaths(t *testing.T) {
+	secrets, err := compileGlobs(ingestSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path   string
+		secret bool
+	}{
+		{"/srv/rick/config/config.yaml", true},
+		{"/srv/rick/config/config-example.yaml", false},
+		{"/srv/app/.env", true},
+		{"/srv/app/.env.production", true},
+		{"/home/u/.ssh/", true}, // Klasörün kendisi atlanır
+		{"/home/u/.ssh/id_ed25519", true},
+		{"/srv/app/certs/server.pem", true},
+		{"/srv/app/docs/env.md", false},
+	}
+	for _, tt := range tests {
+		if got := matchAny(secrets, tt.path); got != tt.secret {
+			t.Errorf("%s: gizli = %v, beklenen %v", tt.path, got, tt.secret)
+		}
+	}
+}
+
+func TestChunkBlocks(t *testing.T) {
+	long := make([]string, 10)
+	for i := range long {
+		long[i] = strings.Repeat("x", 9)
+	}
+	tests := []struct {
+		name   string
+		blocks []textBlock
+		size   int
+		want   []textChunk // Sadece Start, End ve Heading karşılaştırılır
+	}{
+		{
+			name:   "küçük bloklar birleşir",
+			blocks: []textBlock{{Heading: "A", Start: 1, Lines: []string{"bir"}}, {Heading: "B", Start: 3, Lines: []string{"iki"}}},
+			size:   100,
+			want:   []textChunk{{Heading: "A", Start: 1, End: 3}},
+		},
+		{
+			name:   "sığmayan blok yeni parçaya geçer",
+			blocks: []textBlock{{Heading: "A", Start: 1, Lines: []string{"12345"}}, {Heading: "B", Start: 2, Lines: []string{"67890"}}},
+			size:   8,
+			want:   []textChunk{{Heading: "A", Start: 1, End: 1}, {Heading: "B", Start: 2, End: 2}},
+		},
+		{
+			name:   "boş bloklar atlanır",
+			blocks: []textBlock{{Start: 1, Lines: []string{"  "}}, {Heading: "B", Start: 2, Lines: []string{"metin"}}},
+			size:   100,
+			want:   []textChunk{{Heading: "B", Start: 2, End: 2}},
+		},
+		{
+			name:   "büyük blok örtüşmeli bölünür",
+			blocks: []textBlock{{Heading: "F", Start: 1, Lines: long}},
+			size:   30,
+			want:   []textChunk{{Heading: "F", Start: 1, End: 3}, {Heading: "F", Start: 3, End: 5}, {Heading: "F", Start: 5, End: 7}, {Heading: "F", Start: 7, End: 9}, {Heading: "F", Start: 9, End: 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkBlocks(tt.blocks, tt.size, 10)
+			if len(got) != len(tt.want) {
+				t.Fatalf("%d parça beklendi, alınan %d: %+v", len(tt.want), len(got), got)
+			}
+			for i, c := range got {
+				w := tt.want[i]
+				if c.Heading != w.Heading || c.Start != w.Start || c.End != w.End {
+					t.Errorf("parça %d: %s %d-%d, beklenen %s %d-%d", i, c.Heading, c.Start, c.End, w.Heading, w.Start, w.End)
+				}
+			}
+		})
+	}
+}
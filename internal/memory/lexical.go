@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 Parametreleri
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// lexicalIndex: Hafıza içeriği üzerinde bellek içi BM25 tam metin indeksi.
+// Host adları, görev ID'leri (TSK-1A2B), dosya adları ve hata kodları gibi birebir ifadeleri yakalar.
+// VectorStore kilidi altında kullanılır, kendi kilidi yoktur.
+type lexicalIndex struct {
+	postings map[string]map[string]int // Terim -> doküman ID -> geçiş sayısı
+	terms    map[string][]string       // Doküman ID -> benzersiz terimleri (silme için)
+	lengths  map[string]int            // Doküman ID -> terim sayısı
+	total    int                       // Tüm dokümanların toplam terim sayısı
+}
+
+func newLexicalIndex() *lexicalIndex {
+	return &lexicalIndex{
+		postings: make(map[string]map[string]int),
+		terms:    make(map[string][]string),
+		lengths:  make(map[string]int),
+	}
+}
+
+func (li *lexicalIndex) Add(id, content string) {
+	if _, ok := li.lengths[id]; ok {
+		li.Delete(id)
+	}
+	terms := tokenize(content)
+	var unique []string
+	for _, t := range terms {
+		docs, ok := li.postings[t]
+		if !ok {
+			docs = make(map[string]int)
+			li.postings[t] = docs
+		}
+		if docs[id] == 0 {
+			unique = append(unique, t)
+		}
+		docs[id]++
+	}
+	li.terms[id] = unique
+	li.lengths[id] = len(terms)
+	li.total += len(terms)
+}
+
+func (li *lexicalIndex) Delete(id string) {
+	n, ok := li.lengths[id]
+	if !ok {
+		return
+	}
+	for _, t := range li.terms[id] {
+		docs := li.postings[t]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(li.postings, t)
+		}
+	}
+	delete(li.terms, id)
+	delete(li.lengths, id)
+	li.total -= n
+}
+
+type lexicalHit struct {
+	ID    string
+	Score float64
+}
+
+// Search: Sorgu terimlerine göre BM25 puanı en yüksek 'limit' dokümanı döner.
+func (li *lexicalIndex) Search(query string, limit int) []lexicalHit {
+	n := len(li.lengths)
+	if n == 0 {
+		return nil
+	}
+	avgLen := float64(li.total) / float64(n)
+
+	scores := make(map[string]float64)
+	seen := make(map[string]bool)
+	for _, t := range tokenize(query) {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		docs := li.postings[t]
+		if len(docs) == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(n)-float64(len(docs))+0.5)/(float64(len(docs))+0.5))
+		for id, tf := range docs {
+			f := float64(tf)
+			norm := f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*float64(li.lengths[id])/avgLen))
+			scores[id] += idf * norm
+		}
+	}
+
+	hits := make([]lexicalHit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, lexicalHit{id, s})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score == hits[j].Score {
+			return hits[i].ID < hits[j].ID
+		}
+		return hits[i].Score > hits[j].Score
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// tokenize: Metni küçük harfli terimlere böler. Tanımlayıcılar ('TSK-1A2B', 'db01.local', 'main.go', 'E_CONN')
+// hem bütün olarak hem de parçalarıyla indekslenir ki ikisiyle de bulunabilsin.
+func tokenize(text string) []string {
+	var terms []string
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	isJoiner := func(r rune) bool { return r == '-' || r == '_' || r == '.' || r == ':' || r == '/' }
+
+	for _, raw := range strings.FieldsFunc(text, func(r rune) bool { return !isWord(r) && !isJoiner(r) }) {
+		compound := strings.Trim(raw, "-_.:/")
+		if compound == "" {
+			continue
+		}
+		compound = strings.ToLower(compound)
+		parts := strings.FieldsFunc(compound, isJoiner)
+		if len(parts) > 1 {
+			terms = append(terms, compound)
+		}
+		terms = append(terms, parts...)
+	}
+	return terms
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+// Get: Kaydı kalıcı ID'si ile getirir.
+func (vs *VectorStore) Get(ctx context.Context, id string) (*kernel.MemoryRecord, error) {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	idx, ok := vs.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("'%s' ID'li hafıza kaydı bulunamadı", id)
+	}
+	rec := toRecord(vs.docs[idx], 0)
+	return &rec, nil
+}
+
+// Query: Etiket ve zaman filtreli arama. Sorgu metni yoksa filtreye uyan en yeni kayıtları döner.
+func (vs *VectorStore) Query(ctx context.Context, q kernel.MemoryQuery) ([]kernel.MemoryRecord, error) {
+	if q.Limit <= 0 {
+		q.Limit = vs.Defaults.Limit
+	}
+	filter := QueryFilter(q)
+
+	if q.Query != "" {
+		results, err := vs.SearchWithOptions(ctx, q.Query, SearchOptions{Limit: q.Limit, Filter: filter})
+		if err != nil {
+			return nil, err
+		}
+		records := make([]kernel.MemoryRecord, len(results))
+		for i, r := range results {
+			records[i] = toRecord(r.Doc, r.Score)
+		}
+		return records, nil
+	}
+
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	var records []kernel.MemoryRecord
+	for _, doc := range vs.docs {
+		if filter(doc) {
+			records = append(records, toRecord(doc, 0))
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.After(records[j].CreatedAt) })
+	if len(records) > q.Limit {
+		records = records[:q.Limit]
+	}
+	return records, nil
+}
+
+// Delete: Kayıtları kalıcı olarak siler (disk, RAM, BM25 ve ANN indeksi). Silinen kayıt sayısını döner.
+func (vs *VectorStore) Delete(ctx context.Context, ids ...string) (int, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	var found []string
+	for _, id := range ids {
+		if _, ok := vs.byID[id]; ok {
+			found = append(found, id)
+		}
+	}
+	if len(found) == 0 {
+		return 0, nil
+	}
+	if err := vs.backend.Delete(found...); err != nil {
+		return 0, fmt.Errorf("hafıza kaydı silinemedi: %v", err)
+	}
+
+	vs.removeLocked(found)
+	return len(found), nil
+}
+
+// removeLocked: Kayıtları RAM'den, BM25 ve ANN indeksinden çıkarır. Yazma kilidi altında çağrılmalıdır.
+func (vs *VectorStore) removeLocked(ids []string) {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+		vs.lexical.Delete(id)
+		if vs.ann != nil {
+			vs.ann.Delete(id)
+		}
+	}
+	kept := vs.docs[:0]
+	for _, doc := range vs.docs {
+		if !drop[doc.ID] {
+			kept = append(kept, doc)
+		} else {
+			vs.countIndexableLocked(doc, -1)
+		}
+	}
+	vs.docs = kept
+	vs.byID = make(map[string]int, len(kept))
+	for i, doc := range kept {
+		vs.byID[doc.ID] = i
+	}
+	if vs.ann != nil && vs.ann.NeedsCompaction() {
+		vs.rebuildIndex()
+	}
+}
+
+// QueryFilter: MemoryQuery'deki ad alanı, etiket, metadata ve zaman koşullarını doküman filtresine çevirir.
+func QueryFilter(q kernel.MemoryQuery) func(Document) bool {
+	namespaces := make(map[string]bool, len(q.Namespaces))
+	for _, ns := range q.Namespaces {
+		namespaces[ns] = true
+	}
+
+	return func(doc Document) bool {
+		if len(namespaces) > 0 && !namespaces[doc.Namespace] {
+			return false
+		}
+		if !q.Since.IsZero() && doc.CreatedAt.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && doc.CreatedAt.After(q.Until) {
+			return false
+		}
+		if len(q.Tags) > 0 && !containsAll(kernel.MetadataStrings(doc.Metadata, "tags"), q.Tags) {
+			return false
+		}
+		for key, want := range q.Metadata {
+			if !metadataMatches(doc.Metadata, key, want) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// metadataMatches: Liste alanlarda (tags vb.) değeri içeriyor mu, tekil alanlarda eşit mi?
+func metadataMatches(metadata map[string]interface{}, key string, want interface{}) bool {
+	v, ok := metadata[key]
+	if !ok {
+		return false
+	}
+	switch v.(type) {
+	case []string, []interface{}:
+		return containsAll(kernel.MetadataStrings(metadata, key), []string{fmt.Sprint(want)})
+	}
+	return fmt.Sprint(v) == fmt.Sprint(want)
+}
+
+func containsAll(have, want []string) bool {
+	set := make(map[string]bool, len(have))
+	for _, h := range have {
+		set[h] = true
+	}
+	for _, w := range want {
+		if !set[w] {
+			return false
+		}
+	}
+	return true
+}
+
+func toRecord(doc Document, score float64) kernel.MemoryRecord {
+	return kernel.MemoryRecord{
+		ID:        doc.ID,
+		Namespace: doc.Namespace,
+		Content:   doc.Content,
+		Metadata:  doc.Metadata,
+		CreatedAt: doc.CreatedAt,
+		Score:     score,
+	}
+}
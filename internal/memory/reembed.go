@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// ReembedStatus: Yeniden embedding göçünün anlık durumu
+type ReembedStatus struct {
+	Model    string    `json:"model"`
+	Total    int       `json:"total"`  // Göç edilecek kayıt sayısı
+	Done     int       `json:"done"`   // Yeni modele taşınan kayıt sayısı
+	Failed   int       `json:"failed"` // Embedding'i alınamayan kayıt sayısı (Bir sonraki çalıştırmada tekrar denenir)
+	Running  bool      `json:"running"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitempty"`
+	Err      string    `json:"error,omitempty"`
+}
+
+type reembedJob struct {
+	status ReembedStatus
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartReembed: Aktif embedding modelini değiştirir ve eski modelde kalan tüm kayıtları arka planda yeni modele taşır.
+// Her kayıt taşındığı anda diske yazılır; iş yarıda kesilirse aynı modelle tekrar başlatmak kaldığı yerden devam eder.
+// Göç sürerken anlamsal arama sadece yeni modele taşınmış kayıtları görür.
+func (vs *VectorStore) StartReembed(ctx context.Context, model string) error {
+	modeler, ok := vs.Brain.(kernel.EmbeddingModeler)
+	if !ok {
+		return fmt.Errorf("HATA: aktif beyin embedding modeli değiştirmeyi desteklemiyor")
+	}
+	if model == "" {
+		return fmt.Errorf("HATA: hedef embedding modeli boş olamaz")
+	}
+
+	vs.reembedMu.Lock()
+	defer vs.reembedMu.Unlock()
+	if vs.reembed != nil && vs.reembed.status.Running {
+		return fmt.Errorf("HATA: zaten süren bir göç var (%s: %d/%d)", vs.reembed.status.Model, vs.reembed.status.Done, vs.reembed.status.Total)
+	}
+
+	// Hatalı model adıyla bütün store'u bozmamak için önce modeli dene
+	if _, err := modeler.EmbedWithModel(ctx, model, "rick embedding probe"); err != nil {
+		return fmt.Errorf("HATA: '%s' modeliyle embedding üretilemedi: %v", model, err)
+	}
+
+	var pending []string
+	vs.mu.Lock()
+	for _, doc := range vs.docs {
+		if doc.EmbeddingModel != model {
+			pending = append(pending, doc.ID)
+		}
+	}
+	previous := modeler.EmbeddingModel()
+	modeler.SetEmbeddingModel(model)
+	if vs.ann != nil && vs.ann.Model != model {
+		vs.ann = nil // Eski modelin indeksi artık sorgulanamaz, göç bitince yeniden kurulur
+	}
+	vs.mu.Unlock()
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	job := &reembedJob{
+		status: ReembedStatus{Model: model, Total: len(pending), Running: true, Started: time.Now()},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	vs.reembed = job
+
+	logger.Action("🔁 Embedding göçü başladı: %s -> %s (%d kayıt)", previous, model, len(pending))
+	go vs.runReembed(jobCtx, job, modeler, pending)
+	return nil
+}
+
+// ReembedStatus: Son (veya süren) göçün durumu. Hiç göç başlatılmadıysa false döner.
+func (vs *VectorStore) ReembedStatus() (ReembedStatus, bool) {
+	vs.reembedMu.Lock()
+	defer vs.reembedMu.Unlock()
+	if vs.reembed == nil {
+		return ReembedStatus{}, false
+	}
+	return vs.reembed.status, true
+}
+
+func (vs *VectorStore) runReembed(ctx context.Context, job *reembedJob, modeler kernel.EmbeddingModeler, ids []string) {
+	defer close(job.done)
+	model := job.status.Model
+	lastPercent := 0
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		vs.mu.RLock()
+		idx, ok := vs.byID[id]
+		var doc Document
+		if ok {
+			doc = vs.docs[idx]
+		}
+		vs.mu.RUnlock()
+		if !ok || doc.EmbeddingModel == model {
+			vs.updateReembed(job, func(s *ReembedStatus) { s.Done++ }) // Bu arada silinmiş veya zaten taşınmış
+			continue
+		}
+
+		vec, err := modeler.EmbedWithModel(ctx, model, doc.Content)
+		if err == nil {
+			err = vs.applyReembed(id, doc.Content, model, vec)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			logger.Warn("⚠️ Kayıt yeni modele taşınamadı (%s): %v", id, err)
+			vs.updateReembed(job, func(s *ReembedStatus) { s.Failed++ })
+			continue
+		}
+
+		var status ReembedStatus
+		vs.updateReembed(job, func(s *ReembedStatus) { s.Done++; status = *s })
+		if percent := status.Done * 100 / status.Total; percent >= lastPercent+10 {
+			lastPercent = percent
+			logger.Info("🔁 Embedding göçü [%s]: %%%d (%d/%d)", model, percent, status.Done, status.Total)
+		}
+	}
+
+	var status ReembedStatus
+	vs.updateReembed(job, func(s *ReembedStatus) {
+		s.Running = false
+		s.Finished = time.Now()
+		if ctx.Err() != nil {
+			s.Err = "göç durduruldu, aynı modelle tekrar başlatılırsa kaldığı yerden devam eder"
+		}
+		status = *s
+	})
+
+	if status.Err == "" && status.Failed == 0 {
+		// Config'teki model değişmediği sürece yeniden başlatmada bu model benimsenir (Bkz. checkEmbeddingModel)
+		err := vs.backend.SetMeta(metaEmbeddingConfigured, vs.configuredModel)
+		if err == nil {
+			err = vs.backend.SetMeta(metaEmbeddingModel, model)
+		}
+		if err != nil {
+			logger.Warn("⚠️ Embedding modeli kaydedilemedi: %v", err)
+		}
+		logger.Success("✅ Embedding göçü tamamlandı: %d kayıt %s modeline taşındı (%v)", status.Done, model, status.Finished.Sub(status.Started).Round(time.Second))
+	} else {
+		logger.Warn("⚠️ Embedding göçü eksik kaldı: %d/%d taşındı, %d hata. %s", status.Done, status.Total, status.Failed, status.Err)
+	}
+
+	vs.mu.RLock()
+	indexed := vs.indexPath != ""
+	vs.mu.RUnlock()
+	if indexed && ctx.Err() == nil {
+		vs.buildIndex()
+	}
+}
+
+// applyReembed: Yeni vektörü kaydın güncel haline (Kilit altında yeniden okunur) yazar. Embedding üretilirken
+// kayıt silinmişse veya zaten taşınmışsa bir şey yapmaz; içeriği değişmişse vektör geçersizdir, hata döner.
+func (vs *VectorStore) applyReembed(id, content, model string, vec []float32) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	idx, ok := vs.byID[id]
+	if !ok || vs.docs[idx].EmbeddingModel == model {
+		return nil
+	}
+	doc := vs.docs[idx]
+	if doc.Content != content {
+		return fmt.Errorf("kayıt göç sırasında değişti, bir sonraki çalıştırmada tekrar denenecek")
+	}
+	doc.Embedding, doc.EmbeddingModel, doc.EmbeddingDim = vec, model, len(vec)
+	if err := vs.backend.Put(doc); err != nil {
+		return err
+	}
+	vs.countIndexableLocked(vs.docs[idx], -1)
+	vs.docs[idx] = doc
+	vs.countIndexableLocked(doc, 1)
+	return nil
+}
+
+func (vs *VectorStore) updateReembed(job *reembedJob, fn func(*ReembedStatus)) {
+	vs.reembedMu.Lock()
+	defer vs.reembedMu.Unlock()
+	fn(&job.status)
+}
+
+// stopReembed: Süren göçü iptal eder ve son kaydın diske yazılmasını bekler.
+func (vs *VectorStore) stopReembed() {
+	vs.reembedMu.Lock()
+	job := vs.reembed
+	vs.reembedMu.Unlock()
+	if job == nil {
+		return
+	}
+	job.cancel()
+	<-job.done
+}
@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// SearchOptions: Hibrit (BM25 + vektör) aramanın sorgu bazında ayarlanabilen parametreleri.
+// Sıfır değerler store'un varsayılanlarıyla (Defaults) doldurulur.
+type SearchOptions struct {
+	Limit         int
+	VectorWeight  float64       // RRF'de vektör sıralamasının ağırlığı (0 ve LexicalWeight>0 ise sadece kelime araması)
+	LexicalWeight float64       // RRF'de BM25 sıralamasının ağırlığı
+	Threshold     float64       // Vektör adayları için en düşük kosinüs benzerliği. Negatif = eşik yok
+	RRFK          float64       // Reciprocal Rank Fusion sabiti (k)
+	DecayHalfLife time.Duration // Zaman aşınması: Bu yaştaki kaydın ağırlığı yarıya iner (en fazla %50). Negatif = kapalı
+
+	Filter func(Document) bool // Opsiyonel: Sadece true dönen dokümanlar aday olur
+}
+
+// DefaultSearchOptions: Eski davranışa yakın varsayılanlar (0.4 eşik) + eşit ağırlıklı kelime araması
+func DefaultSearchOptions() SearchOptions {
+	return SearchOptions{Limit: 5, VectorWeight: 1, LexicalWeight: 1, Threshold: 0.4, RRFK: 60, DecayHalfLife: 30 * 24 * time.Hour}
+}
+
+// SearchResult: Birleştirilmiş puanıyla birlikte tek bir arama sonucu
+type SearchResult struct {
+	Doc          Document
+	Score        float64 // RRF puanı
+	VectorScore  float64 // Kosinüs benzerliği (Vektör adayı değilse 0)
+	LexicalScore float64 // BM25 puanı (Kelime eşleşmesi yoksa 0)
+}
+
+// WithDefaults: Boş alanları verilen varsayılanlarla doldurur.
+func (o SearchOptions) WithDefaults(d SearchOptions) SearchOptions {
+	if o.Limit <= 0 {
+		o.Limit = d.Limit
+	}
+	if o.VectorWeight == 0 && o.LexicalWeight == 0 {
+		o.VectorWeight, o.LexicalWeight = d.VectorWeight, d.LexicalWeight
+	}
+	if o.Threshold == 0 {
+		o.Threshold = d.Threshold
+	}
+	if o.RRFK <= 0 {
+		o.RRFK = d.RRFK
+	}
+	if o.DecayHalfLife == 0 {
+		o.DecayHalfLife = d.DecayHalfLife
+	}
+	return o
+}
+
+// SearchWithOptions: Vektör ve BM25 sıralamalarını Reciprocal Rank Fusion ile birleştirir:
+// puan = (wv/(k+sıra_v) + wl/(k+sıra_l)) * önem * aşınma. Eşiğin altındaki vektör adayları elenir ama
+// kelime eşleşmesi olan dokümanlar (ID, host adı, hata kodu) yine de bulunur.
+func (vs *VectorStore) SearchWithOptions(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	opts = opts.WithDefaults(vs.Defaults)
+	pool := opts.Limit * 4 // Her sıralamadan alınacak aday sayısı
+	if pool < 20 {
+		pool = 20
+	}
+
+	// 1. Sorgunun vektörünü al (Sadece kelime araması isteniyorsa gerek yok)
+	model := vs.embeddingModel()
+	var queryVector []float32
+	if opts.VectorWeight > 0 {
+		vec, err := vs.Brain.Embed(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		queryVector = vec
+	}
+
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	fused := make(map[string]*SearchResult)
+	entry := func(id string) *SearchResult {
+		r, ok := fused[id]
+		if !ok {
+			r = &SearchResult{Doc: vs.docs[vs.byID[id]]}
+			fused[id] = r
+		}
+		return r
+	}
+
+	// 2. Vektör sıralaması
+	if opts.VectorWeight > 0 {
+		threshold := opts.Threshold
+		if threshold < 0 {
+			threshold = math.Inf(-1) // 0 "varsayılan" demek olduğundan eşiği kapatmanın yolu negatif değerdir
+		}
+		for rank, hit := range vs.vectorCandidates(model, queryVector, pool, threshold, opts.Filter) {
+			r := entry(hit.ID)
+			r.VectorScore = hit.Score
+			r.Score += opts.VectorWeight / (opts.RRFK + float64(rank+1))
+		}
+	}
+
+	// 3. Kelime (BM25) sıralaması
+	if opts.LexicalWeight > 0 {
+		rank := 0
+		for _, hit := range vs.lexical.Search(query, 0) {
+			idx, ok := vs.byID[hit.ID]
+			if !ok || (opts.Filter != nil && !opts.Filter(vs.docs[idx])) {
+				continue
+			}
+			if rank >= pool {
+				break
+			}
+			r := entry(hit.ID)
+			r.LexicalScore = hit.Score
+			r.Score += opts.LexicalWeight / (opts.RRFK + float64(rank+1))
+			rank++
+		}
+	}
+
+	// 4. Önem ve zaman aşınmasıyla ağırlıklandır, sonra sırala (En yüksek puan en üstte)
+	now := time.Now()
+	results := make([]SearchResult, 0, len(fused))
+	for _, r := range fused {
+		r.Score *= rankWeight(r.Doc, now, opts.DecayHalfLife)
+		results = append(results, *r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].VectorScore > results[j].VectorScore
+		}
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+// vectorCandidates: İndeks güncelse HNSW, değilse tam tarama ile eşiği geçen en benzer adayları döner.
+// Sadece sorguyla aynı modelin vektörleri kıyaslanır. Filtre varsa HNSW'den geniş bir havuz istenir;
+// yine de yetmezse tam taramaya düşülür. Kilit altında çağrılmalıdır.
+func (vs *VectorStore) vectorCandidates(model string, queryVector []float32, limit int, threshold float64, filter func(Document) bool) []hnswHit {
+	var hits []hnswHit
+	if vs.indexUsable(model, len(queryVector)) {
+		want := limit
+		if filter != nil {
+			want = limit * 10
+		}
+		for _, hit := range vs.ann.Search(queryVector, want) {
+			idx, ok := vs.byID[hit.ID]
+			if !ok || hit.Score <= threshold || (filter != nil && !filter(vs.docs[idx])) {
+				continue
+			}
+			hits = append(hits, hit)
+			if len(hits) == limit {
+				return hits
+			}
+		}
+		if filter == nil {
+			return hits
+		}
+		hits = nil
+	}
+
+	for _, doc := range vs.docs {
+		if !compatible(doc, model, len(queryVector)) || (filter != nil && !filter(doc)) {
+			continue
+		}
+		score := cosineSimilarity(queryVector, doc.Embedding)
+		if score > threshold { // Eşik değer (Çok alakasızları ele)
+			hits = append(hits, hnswHit{ID: doc.ID, Score: score})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// Importance: Kaydın 0-1 arası önem puanı (metadata["importance"], yoksa 0.5)
+func Importance(doc Document) float64 {
+	switch v := doc.Metadata["importance"].(type) {
+	case float64:
+		return math.Max(0, math.Min(1, v))
+	case int:
+		return math.Max(0, math.Min(1, float64(v)))
+	}
+	return 0.5
+}
+
+// Pinned: Sabitlenmiş kayıtlar aşınmaz, birleştirilmez ve süre aşımıyla arşivlenmez.
+func Pinned(doc Document) bool {
+	pinned, _ := doc.Metadata["pinned"].(bool)
+	return pinned
+}
+
+// rankWeight: Önem (0.5x - 1.5x) ve yaşa göre yarı ömürlü aşınma (1x - 0.5x) çarpanı.
+// Aşınma 0.5'te durur ki eski ama birebir eşleşen kayıtlar yine bulunabilsin.
+func rankWeight(doc Document, now time.Time, halfLife time.Duration) float64 {
+	weight := 0.5 + Importance(doc)
+	if halfLife > 0 && !Pinned(doc) {
+		age := now.Sub(doc.CreatedAt)
+		if age > 0 {
+			weight *= 0.5 + 0.5*math.Exp2(-float64(age)/float64(halfLife))
+		}
+	}
+	return weight
+}
+
+// formatResult: Metadata'yı da ekle ki bağlam kopmasın
+func formatResult(doc Document) string {
+	if val, ok := doc.Metadata["source"]; ok {
+		return fmt.Sprintf("[%v] %s", val, doc.Content)
+	}
+	return doc.Content
+}
@@ -0,0 +1,362 @@
+package memory
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS documents (
+	id         TEXT PRIMARY KEY,
+	namespace  TEXT NOT NULL DEFAULT 'shared',
+	content    TEXT NOT NULL,
+	metadata   TEXT NOT NULL DEFAULT '{}',
+	embedding  BLOB,
+	embedding_model TEXT NOT NULL DEFAULT '',
+	embedding_dim   INTEGER NOT NULL DEFAULT 0,
+	created_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS archive (
+	id         TEXT PRIMARY KEY,
+	namespace  TEXT NOT NULL DEFAULT 'shared',
+	content    TEXT NOT NULL,
+	metadata   TEXT NOT NULL DEFAULT '{}',
+	embedding  BLOB,
+	embedding_model TEXT NOT NULL DEFAULT '',
+	embedding_dim   INTEGER NOT NULL DEFAULT 0,
+	created_at  INTEGER NOT NULL,
+	archived_at INTEGER NOT NULL,
+	reason      TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);`
+
+// sqliteBackend: Her dokümanı tek satır olarak tutan, artımlı ve işlem (transaction) güvenli depolama.
+type sqliteBackend struct {
+	db *sql.DB
+}
+
+// openSQLiteBackend: Veritabanını açar (WAL modu), şemayı kurar ve eski JSON hafızasını bir kereye mahsus taşır.
+func openSQLiteBackend(dbPath, legacyJSONPath string) (*sqliteBackend, error) {
+	if dir := filepath.Dir(dbPath); dir != "" {
+		os.MkdirAll(dir, 0755)
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)", dbPath)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("hafıza veritabanı açılamadı: %v", err)
+	}
+	// SQLite tek yazıcılıdır; bağlantı havuzu kilit çekişmesi yaratmasın
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("hafıza şeması kurulamadı: %v", err)
+	}
+
+	b := &sqliteBackend{db: db}
+	if err := b.upgrade(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("hafıza şeması güncellenemedi: %v", err)
+	}
+	if legacyJSONPath != "" {
+		if err := b.migrateJSON(legacyJSONPath); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// upgrade: Eski sürümlerde oluşturulmuş tablolara sonradan eklenen kolonları ekler.
+func (b *sqliteBackend) upgrade() error {
+	rows, err := b.db.Query(`PRAGMA table_info(documents)`)
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, ctype      string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		columns[name] = true
+	}
+	rows.Close()
+
+	added := []struct{ name, ddl string }{
+		{"namespace", `ALTER TABLE documents ADD COLUMN namespace TEXT NOT NULL DEFAULT 'shared'`},
+		{"embedding_model", `ALTER TABLE documents ADD COLUMN embedding_model TEXT NOT NULL DEFAULT ''`},
+		{"embedding_dim", `ALTER TABLE documents ADD COLUMN embedding_dim INTEGER NOT NULL DEFAULT 0`},
+	}
+	for _, col := range added {
+		if columns[col.name] {
+			continue
+		}
+		if _, err := b.db.Exec(col.ddl); err != nil {
+			return err
+		}
+		logger.Info("🧠 Hafıza şeması güncellendi: %s kolonu eklendi", col.name)
+	}
+	_, err = b.db.Exec(`CREATE INDEX IF NOT EXISTS idx_documents_namespace ON documents(namespace)`)
+	return err
+}
+
+// migrateJSON: rick_memory.json içeriğini tek transaction'da veritabanına aktarır ve dosyayı '.migrated' olarak işaretler.
+func (b *sqliteBackend) migrateJSON(path string) error {
+	if b.Meta("json_migrated") != "" {
+		return nil
+	}
+
+	docs, err := readJSONDocuments(path)
+	if err != nil {
+		return fmt.Errorf("eski JSON hafızası okunamadı (%s): %v", path, err)
+	}
+
+	if len(docs) > 0 {
+		if err := b.Put(docs...); err != nil {
+			return fmt.Errorf("JSON hafızası SQLite'a taşınamadı: %v", err)
+		}
+		if err := os.Rename(path, path+".migrated"); err != nil {
+			logger.Warn("⚠️ Eski hafıza dosyası yeniden adlandırılamadı: %v", err)
+		}
+		logger.Success("🧠 Hafıza taşındı: %s -> SQLite (%d kayıt)", path, len(docs))
+	}
+	return b.SetMeta("json_migrated", time.Now().Format(time.RFC3339))
+}
+
+func (b *sqliteBackend) Load() ([]Document, error) {
+	rows, err := b.db.Query(`SELECT id, namespace, content, metadata, embedding, embedding_model, created_at FROM documents ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []Document
+	for rows.Next() {
+		var (
+			doc     Document
+			meta    string
+			vec     []byte
+			created int64
+		)
+		if err := rows.Scan(&doc.ID, &doc.Namespace, &doc.Content, &meta, &vec, &doc.EmbeddingModel, &created); err != nil {
+			return nil, err
+		}
+		if meta != "" && meta != "{}" {
+			json.Unmarshal([]byte(meta), &doc.Metadata)
+		}
+		doc.Embedding = decodeVector(vec)
+		doc.CreatedAt = time.Unix(0, created)
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+const sqliteUpsert = `INSERT INTO documents (id, namespace, content, metadata, embedding, embedding_model, embedding_dim, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET namespace = excluded.namespace, content = excluded.content, metadata = excluded.metadata,
+	embedding = excluded.embedding, embedding_model = excluded.embedding_model, embedding_dim = excluded.embedding_dim,
+	created_at = excluded.created_at`
+
+func (b *sqliteBackend) Put(docs ...Document) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(sqliteUpsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, doc := range docs {
+		args, err := docArgs(doc)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// docArgs: Dokümanı documents/archive tablolarının ortak kolon sırasına çevirir.
+func docArgs(doc Document) ([]interface{}, error) {
+	meta := []byte("{}")
+	if len(doc.Metadata) > 0 {
+		var err error
+		if meta, err = json.Marshal(doc.Metadata); err != nil {
+			return nil, fmt.Errorf("metadata paketlenemedi (%s): %v", doc.ID, err)
+		}
+	}
+	namespace := doc.Namespace
+	if namespace == "" {
+		namespace = "shared"
+	}
+	return []interface{}{doc.ID, namespace, doc.Content, string(meta), encodeVector(doc.Embedding),
+		doc.EmbeddingModel, len(doc.Embedding), doc.CreatedAt.UnixNano()}, nil
+}
+
+func (b *sqliteBackend) Delete(ids ...string) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		if _, err := tx.Exec(`DELETE FROM documents WHERE id = ?`, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Archive: Kayıtları tek işlemde arşiv tablosuna kopyalar ve canlı tablodan çıkarır.
+func (b *sqliteBackend) Archive(reason string, docs ...Document) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UnixNano()
+	for _, doc := range docs {
+		args, err := docArgs(doc)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO archive (id, namespace, content, metadata, embedding, embedding_model, embedding_dim, created_at, archived_at, reason)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, append(args, now, reason)...); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM documents WHERE id = ?`, doc.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (b *sqliteBackend) Archived() ([]ArchivedDocument, error) {
+	rows, err := b.db.Query(`SELECT id, namespace, content, metadata, embedding, embedding_model, created_at, archived_at, reason
+		FROM archive ORDER BY archived_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var archived []ArchivedDocument
+	for rows.Next() {
+		var (
+			a             ArchivedDocument
+			meta          string
+			vec           []byte
+			created, when int64
+		)
+		if err := rows.Scan(&a.ID, &a.Namespace, &a.Content, &meta, &vec, &a.EmbeddingModel, &created, &when, &a.Reason); err != nil {
+			return nil, err
+		}
+		if meta != "" && meta != "{}" {
+			json.Unmarshal([]byte(meta), &a.Metadata)
+		}
+		a.Embedding = decodeVector(vec)
+		a.EmbeddingDim = len(a.Embedding)
+		a.CreatedAt = time.Unix(0, created)
+		a.ArchivedAt = time.Unix(0, when)
+		archived = append(archived, a)
+	}
+	return archived, rows.Err()
+}
+
+// Unarchive: Kayıtları tek işlemde canlı tabloya geri yazar ve arşivden çıkarır.
+func (b *sqliteBackend) Unarchive(ids ...string) ([]Document, error) {
+	all, err := b.Archived()
+	if err != nil {
+		return nil, err
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var restored []Document
+	for _, a := range all {
+		if !want[a.ID] {
+			continue
+		}
+		args, err := docArgs(a.Document)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(sqliteUpsert, args...); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`DELETE FROM archive WHERE id = ?`, a.ID); err != nil {
+			return nil, err
+		}
+		restored = append(restored, a.Document)
+	}
+	return restored, tx.Commit()
+}
+
+func (b *sqliteBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *sqliteBackend) Meta(key string) string {
+	var value string
+	if err := b.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, key).Scan(&value); err != nil {
+		return ""
+	}
+	return value
+}
+
+func (b *sqliteBackend) SetMeta(key, value string) error {
+	_, err := b.db.Exec(`INSERT INTO meta (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value)
+	return err
+}
+
+// -- Vektör Kodlama (float32 -> little-endian BLOB) --
+
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return v
+}
@@ -2,12 +2,10 @@ package memory
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"math"
-	"os"
-	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
@@ -18,125 +16,217 @@ import (
 // Document: Hafızadaki tekil bilgi birimi
 type Document struct {
 	ID        string                 `json:"id"`
+	Namespace string                 `json:"namespace,omitempty"` // Boşsa ortak (shared) hafıza
 	Content   string                 `json:"content"`
 	Metadata  map[string]interface{} `json:"metadata"`
 	Embedding []float32              `json:"embedding"`
-	CreatedAt time.Time              `json:"created_at"`
+	// Vektörü üreten model ve boyutu; farklı modellerin vektörleri asla kıyaslanmaz (Boş model = eski kayıt)
+	EmbeddingModel string    `json:"embedding_model,omitempty"`
+	EmbeddingDim   int       `json:"embedding_dim,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
-// VectorStore: Basit, yerel vektör veritabanı
+// VectorStore: Basit, yerel vektör veritabanı.
+// Dokümanlar aramada hızlı erişim için RAM'de tutulur, kalıcılık Backend'e (JSON veya SQLite) devredilir.
 type VectorStore struct {
 	FilePath string
-	Brain    kernel.Brain // Embedding üretmek için
+	Brain    kernel.Brain  // Embedding üretmek için
+	Defaults SearchOptions // Sorguda belirtilmeyen arama ayarları
+	backend  Backend
 	docs     []Document
+	byID     map[string]int // Doküman ID -> docs indeksi
+	lexical  *lexicalIndex  // BM25 tam metin indeksi
 	mu       sync.RWMutex
+
+	// ANN (HNSW) indeksi: EnableIndex çağrılana kadar kapalıdır, hazır değilken tam tarama yapılır
+	ann          *hnswIndex
+	indexPath    string
+	indexMinDocs int
+	annDocs      int // ann ile uyumlu (indekslenebilir) doküman sayısı; indexUsable her aramada taramasın diye tutulur
+	saving       atomic.Bool
+	rebuilding   atomic.Bool // Silinmiş düğümler birikince başlatılan yeniden inşa
+
+	// Yeniden embedding göçü (Aynı anda tek iş)
+	reembed         *reembedJob
+	reembedMu       sync.Mutex
+	configuredModel string // Başlangıçta (config'ten) gelen embedding modeli
 }
 
+// NewVectorStore: Eski tek dosyalı JSON hafızasını kullanan store (Geriye uyumluluk için).
 func NewVectorStore(path string, brain kernel.Brain) *VectorStore {
 	store := &VectorStore{
 		FilePath: path,
 		Brain:    brain,
+		backend:  newJSONBackend(path),
+		Defaults: DefaultSearchOptions(),
 		docs:     []Document{},
+		byID:     make(map[string]int),
+		lexical:  newLexicalIndex(),
 	}
 	store.load() // Başlarken yükle
 	return store
 }
 
-// Add: Hafızaya yeni bilgi ekler
-func (vs *VectorStore) Add(ctx context.Context, content string, metadata map[string]interface{}) error {
-	// 1. Embedding üret
-	vector, err := vs.Brain.Embed(ctx, content)
+// NewSQLiteStore: SQLite destekli hafıza. legacyJSONPath doluysa eski JSON hafızası bir kereye mahsus içeri aktarılır.
+func NewSQLiteStore(dbPath, legacyJSONPath string, brain kernel.Brain) (*VectorStore, error) {
+	backend, err := openSQLiteBackend(dbPath, legacyJSONPath)
 	if err != nil {
-		return fmt.Errorf("embedding hatası: %v", err)
+		return nil, err
 	}
-
-	doc := Document{
-		ID:        uuid.New().String(),
-		Content:   content,
-		Metadata:  metadata,
-		Embedding: vector,
-		CreatedAt: time.Now(),
+	store := &VectorStore{
+		FilePath: dbPath,
+		Brain:    brain,
+		backend:  backend,
+		Defaults: DefaultSearchOptions(),
+		docs:     []Document{},
+		byID:     make(map[string]int),
+		lexical:  newLexicalIndex(),
 	}
+	store.load()
+	return store, nil
+}
 
-	vs.mu.Lock()
-	vs.docs = append(vs.docs, doc)
-	vs.mu.Unlock()
-
-	// Diske kaydet
-	return vs.save()
+// Add: Hafızaya yeni bilgi ekler (Ad alanı metadata["namespace"] ile verilebilir, yoksa ortak hafıza)
+func (vs *VectorStore) Add(ctx context.Context, content string, metadata map[string]interface{}) error {
+	namespace := kernel.NamespaceShared
+	if ns, ok := metadata["namespace"].(string); ok && ns != "" {
+		namespace = ns
+		metadata = copyMetadata(metadata)
+		delete(metadata, "namespace")
+	}
+	_, err := vs.Save(ctx, namespace, content, metadata)
+	return err
 }
 
-// Search: Anlamsal arama yapar
-func (vs *VectorStore) Search(ctx context.Context, query string, limit int) ([]string, error) {
-	// 1. Sorgunun vektörünü al
-	queryVector, err := vs.Brain.Embed(ctx, query)
-	if err != nil {
-		return nil, err
+// Save: Hafızaya verilen ad alanında yeni bilgi ekler ve kalıcı ID'sini döner.
+func (vs *VectorStore) Save(ctx context.Context, namespace, content string, metadata map[string]interface{}) (string, error) {
+	if namespace == "" {
+		namespace = kernel.NamespaceShared
+	}
+	if err := kernel.ValidNamespace(namespace); err != nil {
+		return "", err
 	}
 
-	vs.mu.RLock()
-	defer vs.mu.RUnlock()
+	// 1. Embedding üret (Göç sürerken bile hep aktif modelle)
+	vector, err := vs.Brain.Embed(ctx, content)
+	if err != nil {
+		return "", fmt.Errorf("embedding hatası: %v", err)
+	}
 
-	type result struct {
-		doc   Document
-		score float64
+	doc := Document{
+		ID:             uuid.New().String(),
+		Namespace:      namespace,
+		Content:        content,
+		Metadata:       metadata,
+		Embedding:      vector,
+		EmbeddingModel: vs.embeddingModel(),
+		EmbeddingDim:   len(vector),
+		CreatedAt:      time.Now(),
 	}
 
-	var results []result
+	// 2. Önce diske yaz (sadece bu kayıt), başarılıysa RAM'e al ve indeksle
+	if err := vs.insert(doc); err != nil {
+		return "", err
+	}
+	return doc.ID, nil
+}
 
-	// 2. Benzerlik hesapla (Cosine Similarity)
-	for _, doc := range vs.docs {
-		score := cosineSimilarity(queryVector, doc.Embedding)
-		if score > 0.4 { // Eşik değer (Çok alakasızları ele)
-			results = append(results, result{doc, score})
-		}
+// insert: Dokümanları tek seferde diske yazar, başarılıysa RAM'e, BM25'e ve ANN indeksine ekler.
+func (vs *VectorStore) insert(docs ...Document) error {
+	if err := vs.backend.Put(docs...); err != nil {
+		return fmt.Errorf("hafıza kaydedilemedi: %v", err)
 	}
 
-	// 3. Sırala (En yüksek puan en üstte)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].score > results[j].score
-	})
+	vs.mu.Lock()
+	for _, doc := range docs {
+		vs.byID[doc.ID] = len(vs.docs)
+		vs.docs = append(vs.docs, doc)
+		vs.lexical.Add(doc.ID, doc.Content)
+		vs.countIndexableLocked(doc, 1)
+	}
+	ann := vs.ann
+	vs.mu.Unlock()
 
-	// 4. Sonuçları hazırla
-	var contents []string
-	for i := 0; i < limit && i < len(results); i++ {
-		// Metadata'yı da ekle ki bağlam kopmasın
-		metaStr := ""
-		if val, ok := results[i].doc.Metadata["source"]; ok {
-			metaStr = fmt.Sprintf("[%v] ", val)
+	// İndeksi artımlı güncelle (Sadece indeksin modeliyle üretilmiş vektörler girer)
+	if ann == nil {
+		return nil
+	}
+	for _, doc := range docs {
+		if ann.Model != doc.EmbeddingModel {
+			continue
+		}
+		if err := ann.Add(doc.ID, doc.Embedding); err != nil {
+			logger.Warn("⚠️ ANN indeksine eklenemedi (tam taramaya düşülecek): %v", err)
 		}
-		contents = append(contents, fmt.Sprintf("%s%s", metaStr, results[i].doc.Content))
 	}
-
-	return contents, nil
+	if ann.Pending() >= indexSaveEvery && vs.saving.CompareAndSwap(false, true) {
+		go func() {
+			defer vs.saving.Store(false)
+			vs.saveIndex(ann)
+		}()
+	}
+	return nil
 }
 
-// -- Persistence (Disk İşlemleri) --
-
-func (vs *VectorStore) save() error {
+// Close: Süren göçü durdurur, bekleyen indeks değişikliklerini yazar ve depolama katmanını kapatır.
+func (vs *VectorStore) Close() error {
+	vs.stopReembed()
 	vs.mu.RLock()
-	defer vs.mu.RUnlock()
+	ann := vs.ann
+	vs.mu.RUnlock()
+	if ann != nil && ann.Pending() > 0 {
+		vs.saveIndex(ann)
+	}
+	return vs.backend.Close()
+}
 
-	data, err := json.MarshalIndent(vs.docs, "", "  ")
+// Search: Hibrit (anlamsal + kelime) arama yapar, store varsayılanlarını kullanır.
+func (vs *VectorStore) Search(ctx context.Context, query string, limit int) ([]string, error) {
+	results, err := vs.SearchWithOptions(ctx, query, SearchOptions{Limit: limit})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var contents []string
+	for _, r := range results {
+		contents = append(contents, formatResult(r.Doc))
 	}
-	return os.WriteFile(vs.FilePath, data, 0644)
+	return contents, nil
 }
 
+// -- Persistence (Disk İşlemleri) --
+
 func (vs *VectorStore) load() {
-	if _, err := os.Stat(vs.FilePath); os.IsNotExist(err) {
+	docs, err := vs.backend.Load()
+	if err != nil {
+		logger.Warn("Hafıza okunamadı: %v", err)
 		return
 	}
 
-	data, err := os.ReadFile(vs.FilePath)
-	if err != nil {
-		logger.Warn("Hafıza dosyası okunamadı: %v", err)
-		return
+	vs.mu.Lock()
+	vs.docs = append(vs.docs[:0], docs...)
+	vs.byID = make(map[string]int, len(docs))
+	vs.lexical = newLexicalIndex()
+	for i, doc := range docs {
+		if doc.Namespace == "" {
+			vs.docs[i].Namespace = kernel.NamespaceShared // Ad alanı öncesi kayıtlar ortak hafızadır
+		}
+		vs.docs[i].EmbeddingDim = len(doc.Embedding)
+		vs.byID[doc.ID] = i
+		vs.lexical.Add(doc.ID, doc.Content)
 	}
+	vs.recountIndexableLocked()
+	vs.mu.Unlock()
+	logger.Info("🧠 Hafıza yüklendi: %d kayıt", len(docs))
+	vs.checkEmbeddingModel()
+}
 
-	json.Unmarshal(data, &vs.docs)
-	logger.Info("🧠 Hafıza yüklendi: %d kayıt", len(vs.docs))
+func copyMetadata(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
 }
 
 // -- Math Helpers --
@@ -155,4 +245,4 @@ func cosineSimilarity(a, b []float32) float64 {
 		return 0.0
 	}
 	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
-}
\ No newline at end of file
+}
@@ -6,7 +6,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
 )
 
 // PythonTool: Dinamik Python dosyalarını sarmalayan yapı.
@@ -14,7 +19,21 @@ type PythonTool struct {
 	name        string
 	description string
 	scriptPath  string
-	interpreter string // python veya venv/bin/python
+	interpreter string                 // python veya venv/bin/python
+	params      map[string]interface{} // Tipli parametre şeması (nil ise serbest 'args' nesnesi)
+	sandbox     *sandbox.Limits        // Doluysa script bu sınırlarla sandbox içinde çalışır
+}
+
+// ScriptRun: Scriptin ham çalışma sonucu
+type ScriptRun struct {
+	Output    string
+	ExitCode  int
+	Err       error // Süreç hatası veya sandbox ihlali (Başarılıysa nil)
+	Duration  time.Duration
+	Sandboxed bool
+	Truncated bool
+	Isolated  bool
+	Violation *sandbox.Violation
 }
 
 // NewPythonTool: Yeni bir Python aracı oluşturur.
@@ -27,6 +46,18 @@ func NewPythonTool(name, desc, path, pythonPath string) *PythonTool {
 	}
 }
 
+// WithParameters: Tipli parametre şemasını ayarlar (nil ise serbest 'args' nesnesi).
+func (p *PythonTool) WithParameters(schema map[string]interface{}) *PythonTool {
+	p.params = schema
+	return p
+}
+
+// WithSandbox: Scriptin sandbox sınırlarını ayarlar (nil ise doğrudan çalışır).
+func (p *PythonTool) WithSandbox(limits *sandbox.Limits) *PythonTool {
+	p.sandbox = limits
+	return p
+}
+
 func (p *PythonTool) Name() string {
 	return p.name
 }
@@ -35,9 +66,12 @@ func (p *PythonTool) Description() string {
 	return p.description
 }
 
-// Parameters: Dinamik araçlar için esnek parametre yapısı.
+// Parameters: Araç tipli bir şemayla yazıldıysa onu, yoksa esnek parametre yapısını döner.
 // Rick'in yazdığı scriptler genellikle JSON string veya argv bekler.
 func (p *PythonTool) Parameters() map[string]interface{} {
+	if p.params != nil {
+		return p.params
+	}
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
@@ -55,28 +89,128 @@ func (p *PythonTool) Parameters() map[string]interface{} {
 
 // Execute: Python kodunu çalıştırır ve çıktısını yakalar.
 func (p *PythonTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(p.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Script hatasını çıkış koduyla birlikte 'failed' olarak döner.
+func (p *PythonTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	run, err := p.Run(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if !run.Sandboxed {
+		if run.Err != nil {
+			// Hata durumunda stderr çıktısını da dönelim ki Rick hatayı görüp düzeltsin
+			return kernel.Failed(fmt.Sprintf("❌ Script Hatası (%s): %v\nÇıktı:\n%s", p.name, run.Err, run.Output),
+				map[string]interface{}{"exit_code": run.ExitCode, "script": p.scriptPath}), nil
+		}
+		// Başarılı çıktı
+		return kernel.Success(strings.TrimSpace(run.Output), map[string]interface{}{"exit_code": 0}), nil
+	}
+
+	data := map[string]interface{}{
+		"exit_code": run.ExitCode,
+		"script":    p.scriptPath,
+		"truncated": run.Truncated,
+		"isolated":  run.Isolated,
+	}
+	metrics := map[string]float64{"duration_ms": float64(run.Duration.Milliseconds())}
+
+	if v := run.Violation; v != nil {
+		data["violation"] = v.Kind
+		data["limit"] = v.Limit
+		data["detail"] = v.Detail
+		result := kernel.Failed(fmt.Sprintf("⛔ SANDBOX İHLALİ (%s): %s [%s, sınır: %s]\nÇıktı:\n%s", p.name, v.Detail, v.Kind, v.Limit, run.Output), data)
+		result.Metrics = metrics
+		return result, nil
+	}
+	if run.ExitCode != 0 {
+		result := kernel.Failed(fmt.Sprintf("❌ Script Hatası (%s): çıkış kodu %d\nÇıktı:\n%s", p.name, run.ExitCode, run.Output), data)
+		result.Metrics = metrics
+		return result, nil
+	}
+
+	result := kernel.Success(strings.TrimSpace(run.Output), data)
+	result.Metrics = metrics
+	return result, nil
+}
+
+// Run: Argümanları denetleyip scripti çalıştırır ve ham sonucu döner (Test senaryoları çıktıyı biçimlenmeden inceler).
+// Hata sadece argümanlar geçersizse veya süreç hiç başlatılamazsa döner.
+func (p *PythonTool) Run(ctx context.Context, args map[string]interface{}) (*ScriptRun, error) {
+	// 0. Tipli şema varsa script çalışmadan önce argümanları denetle
+	if p.params != nil {
+		// Eski alışkanlıkla {"args": {...}} gönderildiyse aç (Şemada 'args' adlı parametre yoksa)
+		if inner, ok := args["args"].(map[string]interface{}); ok && len(args) == 1 {
+			if props, _ := p.params["properties"].(map[string]interface{}); props["args"] == nil {
+				args = inner
+			}
+		}
+		if err := ValidateArgs(p.params, args); err != nil {
+			return nil, fmt.Errorf("HATA: '%s' aracının parametreleri geçersiz: %v", p.name, err)
+		}
+	}
+
 	// 1. Argümanları JSON'a çevir (Python tarafında json.loads ile okunacak)
 	jsonArgs, err := json.Marshal(args)
 	if err != nil {
-		return "", fmt.Errorf("argüman paketleme hatası: %v", err)
+		return nil, fmt.Errorf("argüman paketleme hatası: %v", err)
+	}
+
+	if p.sandbox != nil {
+		return p.runSandboxed(ctx, string(jsonArgs))
 	}
 
 	// 2. Komutu hazırla
 	// python script.py '{"key": "value"}'
 	cmd := exec.CommandContext(ctx, p.interpreter, p.scriptPath, string(jsonArgs))
-	
+
 	// Çevresel değişkenleri ayarla (Gerekirse API keyleri buraya eklenir)
 	cmd.Env = os.Environ()
 
 	// 3. Çalıştır
+	started := time.Now()
 	output, err := cmd.CombinedOutput()
-	result := string(output)
+	return &ScriptRun{
+		Output:   string(output),
+		ExitCode: kernel.ExitCode(err),
+		Err:      err,
+		Duration: time.Since(started),
+	}, nil
+}
 
+// runSandboxed: Scripti sandbox sınırlarıyla çalıştırır; sınır aşımı ScriptRun.Violation olarak döner.
+func (p *PythonTool) runSandboxed(ctx context.Context, jsonArgs string) (*ScriptRun, error) {
+	// Script kendi geçici klasöründe çalışacağı için yollar mutlak olmalı
+	scriptPath, err := filepath.Abs(p.scriptPath)
 	if err != nil {
-		// Hata durumunda stderr çıktısını da dönelim ki Rick hatayı görüp düzeltsin
-		return fmt.Sprintf("❌ Script Hatası (%s): %v\nÇıktı:\n%s", p.name, err, result), nil
+		return nil, fmt.Errorf("HATA: script yolu çözülemedi: %v", err)
+	}
+	interpreter := p.interpreter
+	if strings.ContainsRune(interpreter, filepath.Separator) {
+		if abs, err := filepath.Abs(interpreter); err == nil {
+			interpreter = abs
+		}
 	}
 
-	// Başarılı çıktı
-	return strings.TrimSpace(result), nil
-}
\ No newline at end of file
+	res, err := sandbox.Run(ctx, *p.sandbox, interpreter, scriptPath, jsonArgs)
+	if err != nil {
+		return nil, fmt.Errorf("HATA: '%s' sandbox içinde çalıştırılamadı: %v", p.name, err)
+	}
+	run := &ScriptRun{
+		Output:    res.Output,
+		ExitCode:  res.ExitCode,
+		Duration:  res.Duration,
+		Sandboxed: true,
+		Truncated: res.Truncated,
+		Isolated:  res.Isolated,
+		Violation: res.Violation,
+	}
+	if res.Violation != nil {
+		run.Err = res.Violation
+	} else if res.ExitCode != 0 {
+		run.Err = fmt.Errorf("çıkış kodu %d", res.ExitCode)
+	}
+	return run, nil
+}
@@ -7,11 +7,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
 )
 
 type ToolDeleter struct {
 	WorkspaceDir string
+	Reloader     ToolReloader // Silinen aracı çalışan yöneticiden de çıkarır (Opsiyonel)
 }
 
 func NewDeleter(workspaceDir string) *ToolDeleter {
@@ -21,7 +23,7 @@ func NewDeleter(workspaceDir string) *ToolDeleter {
 func (d *ToolDeleter) Name() string { return "delete_python_tool" }
 
 func (d *ToolDeleter) Description() string {
-	return "Gereksiz, hatalı veya artık kullanılmayan bir Python aracını sistemden TAMAMEN VE KALICI OLARAK siler."
+	return "Gereksiz, hatalı veya artık kullanılmayan bir Python aracını sistemden siler. Son hali sürüm geçmişinde kalır; gerekirse 'tool_history' ile geri getirilebilir."
 }
 
 func (d *ToolDeleter) Parameters() map[string]interface{} {
@@ -54,7 +56,14 @@ func (d *ToolDeleter) Execute(ctx context.Context, args map[string]interface{})
 	
 	fullPath := filepath.Join(d.WorkspaceDir, filename)
 
-	// 2. Silme İşlemi
+	// 2. Son hali sürüm geçmişine al (Geri getirilebilsin); alınamazsa silme
+	if _, err := os.Stat(fullPath); err == nil {
+		if _, err := ensureSnapshot(d.WorkspaceDir, filename, "delete_python_tool ile silinmeden önceki hal"); err != nil {
+			return "", fmt.Errorf("HATA: '%s' silinmeden önce sürüme alınamadı, silme iptal edildi: %v", filename, err)
+		}
+	}
+
+	// 3. Silme İşlemi
 	if err := os.Remove(fullPath); err != nil {
 		if os.IsNotExist(err) {
 			return "", fmt.Errorf("HATA: '%s' adında bir dosya çalışma alanında bulunamadı. Zaten silinmiş veya ismi yanlış olabilir.", filename)
@@ -67,5 +76,27 @@ func (d *ToolDeleter) Execute(ctx context.Context, args map[string]interface{})
 	// Registry'den kaldır
 	removeFromRegistryFile(d.WorkspaceDir, filename)
 
-	return fmt.Sprintf("✅ BAŞARILI: '%s' sistemden ve kayıtlardan tamamen silindi.", filename), nil
+	// Yöneticiden çıkar (Yoksa model silinmiş aracı çağırmaya devam eder)
+	if d.Reloader != nil {
+		d.Reloader.Remove(filename)
+	}
+
+	return fmt.Sprintf("✅ BAŞARILI: '%s' sistemden ve kayıtlardan silindi. (Geri getirmek için: tool_history rollback)", filename), nil
+}
+
+// ExecuteRich: Silinen dosya adını yapısal veri olarak döner.
+func (d *ToolDeleter) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	out, err := d.Execute(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	filename, _ := args["filename"].(string)
+	if strings.TrimSpace(filename) == "" {
+		filename, _ = args["name"].(string)
+	}
+	filename = filepath.Base(strings.TrimSpace(filename))
+	if !strings.HasSuffix(filename, ".py") {
+		filename += ".py"
+	}
+	return kernel.Success(out, map[string]interface{}{"filename": filename}), nil
 }
\ No newline at end of file
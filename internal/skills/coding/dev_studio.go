@@ -2,6 +2,7 @@ package coding
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,13 +10,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/skills"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
 )
 
 type DevStudioTool struct {
 	WorkspaceDir string
 	PipPath      string
 	PythonPath   string
+	Reloader     ToolReloader    // Yazılan araçları beklemeden yeteneklere ekler (Opsiyonel)
+	Sandbox      *sandbox.Limits // Test senaryoları araçla aynı sınırlarla çalışır (Opsiyonel)
 }
 
 func NewDevStudio(workspaceDir, pipPath, pythonPath string) *DevStudioTool {
@@ -41,6 +47,11 @@ func (t *DevStudioTool) Parameters() map[string]interface{} {
 						"step":     map[string]interface{}{"type": "string", "enum": []string{"write", "install", "run"}},
 						"filename": map[string]interface{}{"type": "string", "description": "Sadece 'write' için dosya adı (Örn: script.py)"},
 						"code":     map[string]interface{}{"type": "string", "description": "Sadece 'write' için Python kodunun tamamı"},
+						"parameters": map[string]interface{}{
+							"type":        "object",
+							"description": "Sadece 'write' için, opsiyonel. Aracın parametrelerinin JSON şeması (Örn: {\"type\": \"object\", \"properties\": {\"city\": {\"type\": \"string\"}}, \"required\": [\"city\"]}). Verilirse araç bu tiplerle sunulur, argümanlar çalıştırmadan önce denetlenir ve kodda args['city'] olarak okunur.",
+						},
+						"tests":    testCasesSchema("Sadece 'write' için, opsiyonel. Araca bağlanacak kalıcı test senaryoları; makro sonunda çalışır ve sonraki her 'edit_python_tool' değişikliğinde tekrar koşulur."),
 						"packages": map[string]interface{}{"type": "string", "description": "Sadece 'install' için paket adları (Örn: 'requests pandas')"},
 						"command":  map[string]interface{}{"type": "string", "description": "Sadece 'run' için terminal komutu (Örn: 'python script.py test')"},
 					},
@@ -53,9 +64,23 @@ func (t *DevStudioTool) Parameters() map[string]interface{} {
 }
 
 func (t *DevStudioTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Syntax ve çalışma hatalarını 'failed' olarak, yazılan dosyaları artifact olarak döner.
+func (t *DevStudioTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	actionsRaw, ok := args["actions"].([]interface{})
 	if !ok {
-		return "", fmt.Errorf("HATA: 'dev_studio' için 'actions' dizisi (array) gereklidir")
+		return nil, fmt.Errorf("HATA: 'dev_studio' için 'actions' dizisi (array) gereklidir")
+	}
+
+	var written []string
+	var artifacts []kernel.Artifact
+	schemas := make(map[string]map[string]interface{}) // Dosya adı -> parametre şeması
+	versions := make(map[string]int)                   // Dosya adı -> yazılan sürüm
+	tests := make(map[string][]ToolTestCase)           // Dosya adı -> yeni test senaryoları
+	violation := func(text, errStr string) (*kernel.ToolResult, error) {
+		return &kernel.ToolResult{Status: kernel.ToolStatusError, Text: text, Artifacts: artifacts}, errors.New(errStr)
 	}
 
 	var report strings.Builder
@@ -87,7 +112,7 @@ func (t *DevStudioTool) Execute(ctx context.Context, args map[string]interface{}
 			code, _ := act["code"].(string)
 			
 			if filename == "" || code == "" {
-				return "", fmt.Errorf("Adım %d [write] Başarısız: filename veya code eksik", i+1)
+				return nil, fmt.Errorf("Adım %d [write] Başarısız: filename veya code eksik", i+1)
 			}
 
 			// 🛡️ DİNAMİK YASAKLI İTHALAT (IMPORT) KONTROLÜ - HARD GUARDRAIL
@@ -103,24 +128,66 @@ func (t *DevStudioTool) Execute(ctx context.Context, args map[string]interface{}
 				if strings.Contains(code, importPattern) || strings.Contains(code, fromPattern) {
 					errStr := fmt.Sprintf("🚨 KURAL İHLALİ: '%s' bir Go SİSTEM ARACIDIR, Python kütüphanesi DEĞİLDİR! Kod içine import edemezsin. Lütfen '%s' importunu sil ve veriyi aracı kullanarak önceden çekip, Python'a parametre/değişken olarak ver.", tool, tool)
 					report.WriteString(fmt.Sprintf("❌ Adım %d [write]: %s\n", i+1, errStr))
-					return report.String(), fmt.Errorf(errStr) 
+					return violation(report.String(), errStr)
 				}
 			}
 
-			finalCode := formatPythonCode(filename, "Otonom Araç", code)
+			params, err := skills.NormalizeSchema(act["parameters"])
+			if err != nil {
+				report.WriteString(fmt.Sprintf("❌ Adım %d [write]: %v\n", i+1, err))
+				return violation(report.String(), err.Error())
+			}
+			schemas[filepath.Base(filename)] = params
+			cases, err := parseTestCases(act["tests"])
+			if err != nil {
+				report.WriteString(fmt.Sprintf("❌ Adım %d [write]: %v\n", i+1, err))
+				return violation(report.String(), err.Error())
+			}
+			if len(cases) > 0 {
+				tests[filepath.Base(filename)] = cases
+			}
+
+			finalCode := formatPythonCode(filename, "Otonom Araç", code, params)
 
 			fullPath := filepath.Join(t.WorkspaceDir, filepath.Base(filename))
+
+			// Var olan bir aracın üzerine yazılıyorsa önceki hali geçmişte kalsın
+			previous, readErr := os.ReadFile(fullPath)
+			if readErr == nil {
+				if _, err := ensureSnapshot(t.WorkspaceDir, filepath.Base(filename), "dev_studio üzerine yazmadan önceki hal"); err != nil {
+					logger.Warn("⚠️ [%s] Önceki sürüm alınamadı: %v", filename, err)
+				}
+			}
+
 			logger.Action("📝 [%d] Zırhlı kod yazılıyor: %s", i+1, fullPath)
 			
 			if err := os.WriteFile(fullPath, []byte(finalCode), 0644); err != nil {
-				return "", fmt.Errorf("Adım %d [write] Dosya yazılamadı: %v", i+1, err)
+				return nil, fmt.Errorf("Adım %d [write] Dosya yazılamadı: %v", i+1, err)
 			}
 
 			// 🚀 Syntax Kontrolü (Erken Hata Yakalama)
 			if err := checkSyntax(fullPath); err != nil {
-				os.Remove(fullPath) // Bozuk dosyayı sil, çöp bırakma
+				if readErr == nil {
+					os.WriteFile(fullPath, previous, 0644) // Var olan aracı bozma, önceki hale dön
+				} else {
+					os.Remove(fullPath) // Bozuk dosyayı sil, çöp bırakma
+				}
 				errStr := fmt.Sprintf("🚨 SYNTAX HATASI YAKALANDI!\nYazdığın yeni kod sözdizimi hatası içeriyor. İşlem iptal edildi.\nHata:\n%v", err)
-				return errStr, nil // Hata mesajını dön ki Rick düzeltsin
+				// Hata mesajını dön ki Rick düzeltsin
+				return &kernel.ToolResult{
+					Status:    kernel.ToolStatusFailed,
+					Text:      errStr,
+					Data:      map[string]interface{}{"step": i + 1, "stage": "syntax", "file": filepath.Base(filename)},
+					Artifacts: artifacts,
+				}, nil
+			}
+
+			written = append(written, fullPath)
+			artifacts = append(artifacts, kernel.Artifact{Kind: "file", Path: fullPath, MimeType: "text/x-python"})
+			if v, err := snapshotTool(t.WorkspaceDir, filepath.Base(filename), "dev_studio ile yazıldı", TestUntested); err != nil {
+				logger.Warn("⚠️ [%s] Sürüm kaydedilemedi: %v", filename, err)
+			} else {
+				versions[filepath.Base(filename)] = v.Version
 			}
 
 			report.WriteString(fmt.Sprintf("✅ Adım %d [write]: %s zırhlanarak diske kaydedildi ve Syntax testini geçti.\n", i+1, filename))
@@ -142,7 +209,7 @@ func (t *DevStudioTool) Execute(ctx context.Context, args map[string]interface{}
 						errStr := fmt.Sprintf("🚨 KURAL İHLALİ: '%s' bir Go SİSTEM ARACIDIR, PyPI'da bulunan bir Python kütüphanesi DEĞİLDİR! 'pip install %s' yapılamaz.", tool, tool)
 						logger.Error("❌ [%d/3] KURAL İHLALİ YAKALANDI: %s paketi kurulamaz!", i+1, tool)
 						report.WriteString(fmt.Sprintf("❌ Adım %d [install]: %s\n", i+1, errStr))
-						return report.String(), fmt.Errorf(errStr) 
+						return violation(report.String(), errStr)
 					}
 				}
 			}
@@ -160,7 +227,7 @@ func (t *DevStudioTool) Execute(ctx context.Context, args map[string]interface{}
 				logger.Error("❌ [%d/3] PIP KURULUMU PATLADI! Hata: %v", i+1, err)
 				errStr := fmt.Sprintf("Adım %d [install] Başarısız: %v\nÇıktı: %s", i+1, err, out)
 				report.WriteString("❌ " + errStr + "\n")
-				return report.String(), fmt.Errorf(errStr)
+				return violation(report.String(), errStr)
 			}
 			logger.Success("✅ [%d/3] KÜTÜPHANELER HAZIR: %s başarıyla sanal ortama (VENV) kuruldu.", i+1, packages)
 			report.WriteString(fmt.Sprintf("✅ Adım %d [install]: Paketler kuruldu.\n", i+1))
@@ -193,8 +260,20 @@ func (t *DevStudioTool) Execute(ctx context.Context, args map[string]interface{}
 
 			outputStr := strings.TrimSpace(string(out))
 
+			var lastFilename string
+			for j := i; j >= 0; j-- {
+				if a, ok := actionsRaw[j].(map[string]interface{}); ok && a["step"] == "write" {
+					lastFilename, _ = a["filename"].(string)
+					break
+				}
+			}
+			lastBase := filepath.Base(lastFilename)
+
 			// 🚀 Akıllı Hata Yönlendirmesi
 			if err != nil {
+				if v, ok := versions[lastBase]; ok {
+					markVersionTest(t.WorkspaceDir, lastBase, v, TestFailed)
+				}
 				systemPrompt := fmt.Sprintf(`🚨 KOD TEST SIRASINDA ÇÖKTÜ!
 Yazdığın kod çalışırken aşağıdaki hatayı verdi.
 
@@ -208,24 +287,78 @@ Yazdığın kod çalışırken aşağıdaki hatayı verdi.
 3. Sorunu tespit ettikten sonra 'edit_python_tool' aracını kullanarak dosyayı (replace veya write ile) onar ve tekrar test et!`, err, outputStr)
 
 				report.WriteString(fmt.Sprintf("❌ Adım %d [run]: KOD ÇÖKTÜ.\n", i+1))
-				return systemPrompt, nil
+				return &kernel.ToolResult{
+					Status:    kernel.ToolStatusFailed,
+					Text:      systemPrompt,
+					Data:      map[string]interface{}{"step": i + 1, "stage": "run", "command": command, "exit_code": kernel.ExitCode(err)},
+					Artifacts: artifacts,
+				}, nil
 			}
 
-			var lastFilename string
-			for j := i; j >= 0; j-- {
-				if a, ok := actionsRaw[j].(map[string]interface{}); ok && a["step"] == "write" {
-					lastFilename, _ = a["filename"].(string)
-					break
-				}
-			}
 			if lastFilename != "" {
-				updateRegistryFile(t.WorkspaceDir, lastFilename, "Otonom olarak geliştirilen ve testten geçen araç.")
+				updateRegistryFile(t.WorkspaceDir, lastFilename, "Otonom olarak geliştirilen ve testten geçen araç.", schemas[lastBase])
+				if v, ok := versions[lastBase]; ok {
+					markVersionTest(t.WorkspaceDir, lastBase, v, TestPassed)
+				}
 			}
 
 			report.WriteString(fmt.Sprintf("✅ Adım %d [run]: Başarılı.\nTerminal Çıktısı:\n%s\n", i+1, outputStr))
 		}
 	}
 
+	// Kalıcı test senaryolarını (yeni verilenler + aracın kayıtlı olanları) yazılan koda karşı çalıştır
+	for _, path := range written {
+		base := filepath.Base(path)
+		cases := mergeTestCases(loadTestCases(t.WorkspaceDir, base), tests[base])
+		if len(cases) == 0 {
+			continue
+		}
+		if len(tests[base]) > 0 {
+			if err := saveTestCases(t.WorkspaceDir, base, cases); err != nil {
+				logger.Warn("⚠️ [%s] Test senaryoları kaydedilemedi: %v", base, err)
+			}
+		}
+		params := schemas[base]
+		if params == nil {
+			params = toolParameters(t.WorkspaceDir, base)
+		}
+		logger.Action("🧪 [%s] %d test senaryosu çalıştırılıyor...", base, len(cases))
+		suite := runTestSuite(ctx, t.WorkspaceDir, base, t.PythonPath, t.Sandbox, params, cases)
+		report.WriteString(formatSuite(base, suite))
+		passed := suitePassed(suite)
+		if v, ok := versions[base]; ok {
+			result := TestFailed
+			if passed {
+				result = TestPassed
+			}
+			markVersionTest(t.WorkspaceDir, base, v, result)
+		}
+		if !passed {
+			return &kernel.ToolResult{
+				Status:    kernel.ToolStatusFailed,
+				Text:      report.String() + "🚨 Test senaryoları başarısız. Senaryolar araca kaydedildi; kodu 'edit_python_tool' ile düzelt (Her düzenlemede tüm senaryolar tekrar çalışır).",
+				Data:      map[string]interface{}{"stage": "tests", "file": base, "tests": suiteData(suite)},
+				Artifacts: artifacts,
+			}, nil
+		}
+	}
+
+	// Yeni araçlar yeniden başlatmaya gerek kalmadan hemen kullanılabilir
+	if t.Reloader != nil {
+		for _, path := range written {
+			if err := t.Reloader.Reload(path); err != nil {
+				logger.Warn("⚠️ Araç canlı yüklenemedi: %v", err)
+				continue
+			}
+			report.WriteString(fmt.Sprintf("🧩 %s araç olarak kaydedildi, hemen kullanılabilir.\n", filepath.Base(path)))
+		}
+	}
+
 	report.WriteString(strings.Repeat("-", 30) + "\n🏁 Dev Studio Makrosu başarıyla tamamlandı.")
-	return report.String(), nil
+	return &kernel.ToolResult{
+		Status:    kernel.ToolStatusSuccess,
+		Text:      report.String(),
+		Data:      map[string]interface{}{"files": written, "steps": len(actionsRaw), "versions": versions},
+		Artifacts: artifacts,
+	}, nil
 }
\ No newline at end of file
@@ -1,7 +1,9 @@
 package coding
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,13 +11,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/skills"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
 )
 
 type ToolEditor struct {
 	WorkspaceDir string
 	PipPath      string
 	PythonPath   string
+	Reloader     ToolReloader    // Güncellenen aracı çalışan yöneticide yeniler (Opsiyonel)
+	Sandbox      *sandbox.Limits // Test senaryoları araçla aynı sınırlarla çalışır (Opsiyonel)
 }
 
 func NewEditor(workspaceDir, pipPath, pythonPath string) *ToolEditor {
@@ -25,7 +32,7 @@ func NewEditor(workspaceDir, pipPath, pythonPath string) *ToolEditor {
 func (e *ToolEditor) Name() string { return "edit_python_tool" }
 
 func (e *ToolEditor) Description() string {
-	return "Mevcut bir Python aracını GÜVENLİ ŞEKİLDE günceller. Hata çıkarsa sistem otomatik olarak rollback yapar. 'replace' ile küçük değişiklikler, 'write' ile baştan yazma yapabilirsin. Gerekirse kütüphane kur ve kesinlikle ÇALIŞTIR (run)."
+	return "Mevcut bir Python aracını GÜVENLİ ŞEKİLDE günceller. Hata çıkarsa sistem otomatik olarak rollback yapar. 'replace' ile küçük değişiklikler, 'write' ile baştan yazma yapabilirsin. Gerekirse kütüphane kur ve kesinlikle ÇALIŞTIR (run). Aracın kayıtlı test senaryoları (Bkz. 'test_tool') her güncellemeden sonra otomatik çalışır; biri başarısız olursa değişiklik geri alınır. Her güncelleme sürüm geçmişine kaydedilir (Bkz. 'tool_history')."
 }
 
 func (e *ToolEditor) Parameters() map[string]interface{} {
@@ -33,6 +40,12 @@ func (e *ToolEditor) Parameters() map[string]interface{} {
 		"type": "object",
 		"properties": map[string]interface{}{
 			"filename": map[string]interface{}{"type": "string", "description": "Düzenlenecek mevcut dosya adı (örn: word_counter.py)"},
+			"reason":   map[string]interface{}{"type": "string", "description": "Opsiyonel. Değişikliğin kısa sebebi (Sürüm geçmişine yazılır, Örn: 'API anahtarı hatası düzeltildi')."},
+			"parameters": map[string]interface{}{
+				"type":        "object",
+				"description": "Opsiyonel. Aracın YENİ parametre şeması (JSON Schema, Örn: {\"type\": \"object\", \"properties\": {\"city\": {\"type\": \"string\"}}}). Verilmezse mevcut şema korunur.",
+			},
+			"tests": testCasesSchema("Opsiyonel. Araca eklenecek/güncellenecek kalıcı test senaryoları. Kayıtlı tüm senaryolar değişiklikten sonra çalışır; hepsi geçerse yenileri kaydedilir."),
 			"actions": map[string]interface{}{
 				"type":        "array",
 				"description": "Sırasıyla yapılacak güncelleme adımları.",
@@ -55,14 +68,19 @@ func (e *ToolEditor) Parameters() map[string]interface{} {
 }
 
 func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(e.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Syntax/test başarısızlıklarını (rollback ile) 'failed', kural ihlallerini 'error' olarak döner.
+func (e *ToolEditor) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	filename, ok := args["filename"].(string)
 	if !ok || filename == "" {
-		return "", fmt.Errorf("HATA: 'filename' parametresi eksik! Hangi dosyayı düzenleyeceğini belirtmelisin")
+		return nil, fmt.Errorf("HATA: 'filename' parametresi eksik! Hangi dosyayı düzenleyeceğini belirtmelisin")
 	}
 
 	actionsRaw, ok := args["actions"].([]interface{})
 	if !ok {
-		return "", fmt.Errorf("HATA: 'edit_python_tool' için 'actions' dizisi (array) gereklidir")
+		return nil, fmt.Errorf("HATA: 'edit_python_tool' için 'actions' dizisi (array) gereklidir")
 	}
 
 	if !strings.HasSuffix(filename, ".py") { filename += ".py" }
@@ -71,7 +89,29 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 
 	// 1. DOSYA VARLIK KONTROLÜ
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("HATA: '%s' adında bir dosya yok. Sıfırdan araç yapmak için 'dev_studio' kullanmalısın", filename)
+		return nil, fmt.Errorf("HATA: '%s' adında bir dosya yok. Sıfırdan araç yapmak için 'dev_studio' kullanmalısın", filename)
+	}
+
+	// Düzenleme bitene kadar klasör izleyicisi ara halleri yüklemesin (Testler geçmeden veya rollback'ten önce)
+	if e.Reloader != nil {
+		e.Reloader.Hold(filename)
+		defer e.Reloader.Release(filename)
+	}
+
+	// Parametre şeması: Yenisi verildiyse o, yoksa registry'deki veya script başlığındaki mevcut şema
+	newParams, err := skills.NormalizeSchema(args["parameters"])
+	if err != nil {
+		return nil, err
+	}
+	newTests, err := parseTestCases(args["tests"])
+	if err != nil {
+		return nil, err
+	}
+	params := newParams
+	if params == nil {
+		if params = registryParameters(e.WorkspaceDir, filename); params == nil {
+			params = skills.HeaderParameters(fullPath)
+		}
 	}
 
 	// ==========================================
@@ -79,10 +119,21 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 	// ==========================================
 	backupCode, err := os.ReadFile(fullPath)
 	if err != nil {
-		return "", fmt.Errorf("Güvenlik hatası: Mevcut dosyanın yedeği alınamadı: %v", err)
+		return nil, fmt.Errorf("Güvenlik hatası: Mevcut dosyanın yedeği alınamadı: %v", err)
 	}
 	logger.Action("🛡️ [%s] Yedek (Backup) hafızaya alındı. Ameliyat başlıyor...", filename)
 
+	// Düzenleme öncesi hal geçmişte yoksa (Elle değiştirilmiş veya eski araç) sürüm olarak sakla
+	if _, err := ensureSnapshot(e.WorkspaceDir, filename, "düzenleme öncesi hal"); err != nil {
+		logger.Warn("⚠️ [%s] Düzenleme öncesi sürüm alınamadı: %v", filename, err)
+	}
+	reason, _ := args["reason"].(string)
+	if strings.TrimSpace(reason) == "" {
+		reason = "edit_python_tool ile güncellendi"
+	}
+	test := TestUntested
+	revised := false // 'run' adımı yeni kodla başarılı oldu
+
 	var report strings.Builder
 	report.WriteString(fmt.Sprintf("🛠️ RICK GÜVENLİ GÜNCELLEME RAPORU\n%s\n", strings.Repeat("=", 30)))
 
@@ -112,7 +163,7 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 			
 			if searchText == "" {
 				e.rollback(fullPath, backupCode)
-				return "", fmt.Errorf("Adım %d [replace]: 'search_text' boş olamaz. Rollback yapıldı", i+1)
+				return nil, fmt.Errorf("Adım %d [replace]: 'search_text' boş olamaz. Rollback yapıldı", i+1)
 			}
 
 			// Kural İhlali Kontrolü
@@ -121,7 +172,7 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 				if strings.Contains(replaceText, fmt.Sprintf("import %s", tool)) || strings.Contains(replaceText, fmt.Sprintf("from %s", tool)) {
 					errStr := fmt.Sprintf("🚨 KURAL İHLALİ: '%s' bir Go SİSTEM ARACIDIR! Kod içine import edemezsin.", tool)
 					e.rollback(fullPath, backupCode)
-					return report.String(), fmt.Errorf(errStr)
+					return &kernel.ToolResult{Status: kernel.ToolStatusError, Text: report.String(), Data: map[string]interface{}{"file": filename, "rolled_back": true}}, errors.New(errStr)
 				}
 			}
 
@@ -130,7 +181,7 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 
 			if !strings.Contains(contentStr, searchText) {
 				e.rollback(fullPath, backupCode)
-				return "", fmt.Errorf("Adım %d [replace] HATA: 'search_text' dosya içinde bulunamadı. Tam olarak eşleştiğinden emin ol.", i+1)
+				return nil, fmt.Errorf("Adım %d [replace] HATA: 'search_text' dosya içinde bulunamadı. Tam olarak eşleştiğinden emin ol.", i+1)
 			}
 
 			newContent := strings.Replace(contentStr, searchText, replaceText, 1) // Sadece ilk eşleşmeyi değiştir
@@ -138,14 +189,14 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 			logger.Action("📝 [%d] Kod noktası '%s' içinde değiştiriliyor...", i+1, filename)
 			if err := os.WriteFile(fullPath, []byte(newContent), 0644); err != nil {
 				e.rollback(fullPath, backupCode)
-				return "", fmt.Errorf("Yazma hatası. Rollback yapıldı: %v", err)
+				return nil, fmt.Errorf("Yazma hatası. Rollback yapıldı: %v", err)
 			}
 
 			// Syntax Kontrolü
 			if err := checkSyntax(fullPath); err != nil {
 				e.rollback(fullPath, backupCode)
 				errStr := fmt.Sprintf("🚨 SYNTAX HATASI YAKALANDI (ROLLBACK YAPILDI)!\nYazdığın yeni kod parçası sözdizimi hatasına yol açtı:\n%v", err)
-				return errStr, nil
+				return kernel.Failed(errStr, map[string]interface{}{"step": i + 1, "stage": "syntax", "file": filename, "rolled_back": true}), nil
 			}
 
 			report.WriteString(fmt.Sprintf("✅ Adım %d [replace]: Belirtilen kod parçası başarıyla değiştirildi ve Syntax testini geçti.\n", i+1))
@@ -154,7 +205,7 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 			code, _ := act["code"].(string)
 			if code == "" {
 				e.rollback(fullPath, backupCode)
-				return "", fmt.Errorf("Adım %d [write]: Kod içeriği boş. Rollback yapıldı", i+1)
+				return nil, fmt.Errorf("Adım %d [write]: Kod içeriği boş. Rollback yapıldı", i+1)
 			}
 			
 			// Kural İhlali Kontrolü
@@ -163,23 +214,23 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 				if strings.Contains(code, fmt.Sprintf("import %s", tool)) || strings.Contains(code, fmt.Sprintf("from %s", tool)) {
 					errStr := fmt.Sprintf("🚨 KURAL İHLALİ: '%s' bir Go SİSTEM ARACIDIR! Kod içine import edemezsin.", tool)
 					e.rollback(fullPath, backupCode)
-					return report.String(), fmt.Errorf(errStr) 
+					return &kernel.ToolResult{Status: kernel.ToolStatusError, Text: report.String(), Data: map[string]interface{}{"file": filename, "rolled_back": true}}, errors.New(errStr) 
 				}
 			}
 
-			finalCode := formatPythonCode(filename, "Güncellenmiş Otonom Araç", code)
+			finalCode := formatPythonCode(filename, "Güncellenmiş Otonom Araç", code, params)
 
 			logger.Action("📝 [%d] Yeni kod '%s' üzerine zırhlanarak yazılıyor...", i+1, filename)
 			if err := os.WriteFile(fullPath, []byte(finalCode), 0644); err != nil {
 				e.rollback(fullPath, backupCode)
-				return "", fmt.Errorf("Yazma hatası. Rollback yapıldı: %v", err)
+				return nil, fmt.Errorf("Yazma hatası. Rollback yapıldı: %v", err)
 			}
 
 			// Syntax Kontrolü
 			if err := checkSyntax(fullPath); err != nil {
 				e.rollback(fullPath, backupCode)
 				errStr := fmt.Sprintf("🚨 SYNTAX HATASI YAKALANDI (ROLLBACK YAPILDI)!\nYazdığın yeni kod sözdizimi hatası içeriyor:\n%v", err)
-				return errStr, nil
+				return kernel.Failed(errStr, map[string]interface{}{"step": i + 1, "stage": "syntax", "file": filename, "rolled_back": true}), nil
 			}
 
 			report.WriteString(fmt.Sprintf("✅ Adım %d [write]: Yeni kod diske güvenle yazıldı ve Syntax testini geçti.\n", i+1))
@@ -196,7 +247,7 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 					if pkg == tool {
 						errStr := fmt.Sprintf("🚨 KURAL İHLALİ: '%s' bir Go SİSTEM ARACIDIR, PyPI'da bulunan bir Python kütüphanesi DEĞİLDİR!", tool)
 						e.rollback(fullPath, backupCode)
-						return report.String(), fmt.Errorf(errStr) 
+						return &kernel.ToolResult{Status: kernel.ToolStatusError, Text: report.String(), Data: map[string]interface{}{"file": filename, "rolled_back": true}}, errors.New(errStr) 
 					}
 				}
 			}
@@ -209,7 +260,7 @@ func (e *ToolEditor) Execute(ctx context.Context, args map[string]interface{}) (
 
 			if err != nil {
 				e.rollback(fullPath, backupCode)
-				return fmt.Sprintf("Kütüphane kurulumu başarısız (%v). Rollback yapıldı.\nÇıktı: %s", err, out), nil
+				return kernel.Failed(fmt.Sprintf("Kütüphane kurulumu başarısız (%v). Rollback yapıldı.\nÇıktı: %s", err, out), map[string]interface{}{"step": i + 1, "stage": "install", "file": filename, "rolled_back": true}), nil
 			}
 			report.WriteString(fmt.Sprintf("✅ Adım %d [install]: Paketler güncellendi.\n", i+1))
 
@@ -258,20 +309,77 @@ Yazdığın yeni kod (%s) çalışırken çöktüğü için sistem güvenliği g
 2. Çözümden %%100 emin olduktan sonra 'edit_python_tool' aracını tekrar kullan!`, filename, err, outputStr)
 
 				report.WriteString(fmt.Sprintf("❌ GÜNCELLEME PATLADI. Rollback devrede. Go Hatası: %v\n", err))
-				return systemPrompt, nil
+				return kernel.Failed(systemPrompt, map[string]interface{}{"step": i + 1, "stage": "run", "file": filename, "command": command, "exit_code": kernel.ExitCode(err), "rolled_back": true}), nil
 			}
 
-			updateRegistryFile(e.WorkspaceDir, filename, "Otonom olarak revize edilmiş araç.")
+			revised = true // Registry ancak test senaryoları da geçerse güncellenir
+			test = TestPassed
 			report.WriteString(fmt.Sprintf("✅ Adım %d [run]: Test Başarılı.\nTerminal Çıktısı:\n%s\n", i+1, outputStr))
 		}
 	}
 
+	// 3. KALICI TEST SENARYOLARI: Kayıtlı (ve yeni verilen) tüm senaryolar yeni kodla geçmeli
+	if cases := mergeTestCases(loadTestCases(e.WorkspaceDir, filename), newTests); len(cases) > 0 {
+		logger.Action("🧪 [%s] %d test senaryosu çalıştırılıyor...", filename, len(cases))
+		suite := runTestSuite(ctx, e.WorkspaceDir, filename, e.PythonPath, e.Sandbox, params, cases)
+		report.WriteString(formatSuite(filename, suite))
+		if !suitePassed(suite) {
+			e.rollback(fullPath, backupCode)
+			text := fmt.Sprintf("🚨 TEST SENARYOLARI BAŞARISIZ (ROLLBACK YAPILDI)!\nYeni kod (%s) kayıtlı senaryolardan en az birini bozdu, eski çalışan koda geri dönüldü.\n\n%s", filename, formatSuite(filename, suite))
+			if len(newTests) > 0 {
+				text += "Yeni verilen senaryolar kaydedilmedi.\n"
+			}
+			text += "\n🧠 [SİSTEM YÖNERGESİ]: Başarısız senaryoların çıktısını incele, kodu düzeltip 'edit_python_tool' ile tekrar dene. Beklenti yanlışsa senaryoyu 'tests' ile güncelle."
+			return kernel.Failed(text, map[string]interface{}{"stage": "tests", "file": filename, "rolled_back": true, "tests": suiteData(suite)}), nil
+		}
+		if len(newTests) > 0 {
+			if err := saveTestCases(e.WorkspaceDir, filename, cases); err != nil {
+				logger.Warn("⚠️ [%s] Test senaryoları kaydedilemedi: %v", filename, err)
+			}
+		}
+		test = TestPassed
+	}
+
+	// Kod veya şema değiştiyse registry'ye işle (Yükleyici registry'deki şemayı esas alır).
+	// Başarısız bir denemede registry'ye hiç dokunulmamış olur.
+	if revised || newParams != nil {
+		desc := ""
+		if revised {
+			desc = "Otonom olarak revize edilmiş araç."
+		}
+		updateRegistryFile(e.WorkspaceDir, filename, desc, newParams)
+	}
+	if e.Reloader != nil {
+		if err := e.Reloader.Reload(filename); err != nil {
+			logger.Warn("⚠️ Araç canlı yenilenemedi: %v", err)
+		}
+	}
+
+	data := map[string]interface{}{"file": filename}
+	if v, err := snapshotTool(e.WorkspaceDir, filename, strings.TrimSpace(reason), test); err != nil {
+		logger.Warn("⚠️ [%s] Sürüm kaydedilemedi: %v", filename, err)
+	} else {
+		data["version"] = v.Version
+		report.WriteString(fmt.Sprintf("📚 Sürüm v%d olarak kaydedildi (%s).\n", v.Version, testLabel(v.Test)))
+	}
+
 	report.WriteString(strings.Repeat("-", 30) + "\n🏁 Araç Başarıyla Güncellendi ve Testi Geçti!")
 	logger.Success("✏️ %s başarıyla revize edildi.", filename)
-	return report.String(), nil
+	return &kernel.ToolResult{
+		Status:    kernel.ToolStatusSuccess,
+		Text:      report.String(),
+		Data:      data,
+		Artifacts: []kernel.Artifact{{Kind: "file", Path: fullPath, MimeType: "text/x-python"}},
+	}, nil
 }
 
+// rollback: Başarısız denemeyi (diske yazıldıysa) geçmişe 'failed' olarak kaydeder ve yedeği geri yazar.
 func (e *ToolEditor) rollback(path string, backup []byte) {
 	logger.Warn("⚠️ ROLLBACK TETİKLENDİ: %s eski çalışan haline döndürülüyor.", filepath.Base(path))
+	if current, err := os.ReadFile(path); err == nil && !bytes.Equal(current, backup) {
+		if _, err := snapshotTool(e.WorkspaceDir, filepath.Base(path), "edit_python_tool: başarısız deneme (geri alındı)", TestFailed); err != nil {
+			logger.Warn("⚠️ Başarısız deneme sürüme alınamadı: %v", err)
+		}
+	}
 	os.WriteFile(path, backup, 0644)
 }
\ No newline at end of file
@@ -0,0 +1,272 @@
+package coding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// ToolHistory: Python araçlarının sürüm geçmişini listeler, sürümleri karşılaştırır ve eski sürüme döner.
+// Her yazma (dev_studio, edit_python_tool, delete_python_tool, rollback) bir sürüm bırakır.
+type ToolHistory struct {
+	WorkspaceDir string
+	Reloader     ToolReloader // Geri dönülen aracı çalışan yöneticide yeniler (Opsiyonel)
+}
+
+func NewToolHistory(workspaceDir string) *ToolHistory {
+	return &ToolHistory{WorkspaceDir: workspaceDir}
+}
+
+func (h *ToolHistory) Name() string { return "tool_history" }
+
+func (h *ToolHistory) Description() string {
+	return "Python araçlarının SÜRÜM GEÇMİŞİ. 'list' ile sürümleri (zaman, sebep, test sonucu) gör, 'show' ile bir sürümün kodunu oku, 'diff' ile iki sürümü karşılaştır, 'rollback' ile bozulan bir aracı çalışan eski sürümüne döndür (Silinmiş araçlar da geri getirilebilir)."
+}
+
+func (h *ToolHistory) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action":   map[string]interface{}{"type": "string", "enum": []string{"list", "show", "diff", "rollback"}},
+			"filename": map[string]interface{}{"type": "string", "description": "Araç dosyası (Örn: hava_durumu.py). 'list' için boş bırakılırsa geçmişi olan tüm araçlar listelenir."},
+			"version":  map[string]interface{}{"type": "integer", "description": "'show' ve 'rollback' için sürüm numarası. 'rollback'te verilmezse testi geçen en son (şu ankinden farklı) sürüme dönülür."},
+			"from":     map[string]interface{}{"type": "integer", "description": "'diff' için eski sürüm (Varsayılan: 'to'dan bir önceki)"},
+			"to":       map[string]interface{}{"type": "integer", "description": "'diff' için yeni sürüm (Varsayılan: diskteki mevcut hal)"},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (h *ToolHistory) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(h.ExecuteRich(ctx, args))
+}
+
+func (h *ToolHistory) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	action, _ := args["action"].(string)
+	rawName, _ := args["filename"].(string)
+	filename := toolFilename(rawName)
+
+	if action == "list" && filename == "" {
+		return h.listTools(), nil
+	}
+	if filename == "" {
+		return nil, fmt.Errorf("HATA: '%s' için 'filename' gerekli", action)
+	}
+	versions := listVersions(h.WorkspaceDir, filename)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("HATA: '%s' için kayıtlı sürüm yok", filename)
+	}
+
+	switch action {
+	case "list":
+		return h.list(filename, versions), nil
+	case "show":
+		version := intArg(args["version"])
+		if version == 0 {
+			version = versions[len(versions)-1].Version
+		}
+		content, v, err := versionContent(h.WorkspaceDir, filename, version)
+		if err != nil {
+			return nil, fmt.Errorf("HATA: %v", err)
+		}
+		text := fmt.Sprintf("📜 %s v%d (%s, %s, %s)\n%s\n%s", filename, v.Version, v.CreatedAt.Format("2006-01-02 15:04:05"), v.Reason, testLabel(v.Test), strings.Repeat("-", 30), content)
+		return kernel.Success(text, map[string]interface{}{"filename": filename, "version": v.Version, "test": v.Test}), nil
+	case "diff":
+		from := intArg(args["from"])
+		if from == 0 {
+			from = intArg(args["version"]) // Kısa yazım: "/tools diff hava.py 2 4"
+		}
+		return h.diff(filename, versions, from, intArg(args["to"]))
+	case "rollback":
+		return h.rollback(ctx, filename, versions, intArg(args["version"]))
+	}
+	return nil, fmt.Errorf("HATA: geçersiz eylem '%s' (list, show, diff, rollback)", action)
+}
+
+func (h *ToolHistory) listTools() *kernel.ToolResult {
+	names := trackedTools(h.WorkspaceDir)
+	if len(names) == 0 {
+		return kernel.Success("Henüz sürüm geçmişi olan araç yok.", map[string]interface{}{"count": 0})
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📚 Sürüm geçmişi olan %d araç:\n", len(names)))
+	for _, name := range names {
+		versions := listVersions(h.WorkspaceDir, name)
+		if len(versions) == 0 {
+			continue
+		}
+		last := versions[len(versions)-1]
+		state := ""
+		if _, err := os.Stat(filepath.Join(h.WorkspaceDir, name)); os.IsNotExist(err) {
+			state = " [silinmiş]"
+		}
+		sb.WriteString(fmt.Sprintf("- %s%s: %d sürüm, son v%d (%s, %s)\n", name, state, len(versions), last.Version, last.CreatedAt.Format("2006-01-02 15:04"), testLabel(last.Test)))
+	}
+	return kernel.Success(sb.String(), map[string]interface{}{"count": len(names), "tools": names})
+}
+
+func (h *ToolHistory) list(filename string, versions []ToolVersion) *kernel.ToolResult {
+	current := currentVersion(h.WorkspaceDir, filename, versions)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📚 %s sürüm geçmişi (%d sürüm):\n", filename, len(versions)))
+	items := make([]map[string]interface{}, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		marker := "  "
+		if v.Version == current {
+			marker = "▶ "
+		}
+		sb.WriteString(fmt.Sprintf("%sv%d  %s  %s  %s (%d bayt)\n", marker, v.Version, v.CreatedAt.Format("2006-01-02 15:04:05"), testLabel(v.Test), v.Reason, v.Size))
+		items = append(items, map[string]interface{}{
+			"version":    v.Version,
+			"created_at": v.CreatedAt,
+			"reason":     v.Reason,
+			"test":       v.Test,
+			"size":       v.Size,
+		})
+	}
+	if current == 0 {
+		if _, err := os.Stat(filepath.Join(h.WorkspaceDir, filename)); os.IsNotExist(err) {
+			sb.WriteString("⚠️ Araç şu an diskte yok (silinmiş); 'rollback' ile geri getirilebilir.\n")
+		} else {
+			sb.WriteString("⚠️ Diskteki hal hiçbir sürümle eşleşmiyor (Elle değiştirilmiş olabilir).\n")
+		}
+	}
+	return kernel.Success(sb.String(), map[string]interface{}{"filename": filename, "current": current, "versions": items})
+}
+
+func (h *ToolHistory) diff(filename string, versions []ToolVersion, from, to int) (*kernel.ToolResult, error) {
+	// 'to' verilmezse diskteki mevcut hal (Kayıtsız elle değişiklik de görünsün diye dosyanın kendisi)
+	var toText, toName string
+	if to == 0 {
+		if data, err := os.ReadFile(filepath.Join(h.WorkspaceDir, filename)); err == nil {
+			toText, toName = string(data), filename+" (mevcut)"
+			to = currentVersion(h.WorkspaceDir, filename, versions)
+			if to != 0 {
+				toName = fmt.Sprintf("%s v%d (mevcut)", filename, to)
+			} else if from == 0 {
+				from = versions[len(versions)-1].Version
+			}
+		} else {
+			to = versions[len(versions)-1].Version // Silinmiş araç: son sürüm
+		}
+	}
+	if toName == "" {
+		content, _, err := versionContent(h.WorkspaceDir, filename, to)
+		if err != nil {
+			return nil, fmt.Errorf("HATA: %v", err)
+		}
+		toText, toName = string(content), fmt.Sprintf("%s v%d", filename, to)
+	}
+	if from == 0 {
+		if from = previousVersion(versions, to); from == 0 {
+			return nil, fmt.Errorf("HATA: v%d ilk sürüm; karşılaştırılacak önceki sürüm yok", to)
+		}
+	}
+	fromContent, _, err := versionContent(h.WorkspaceDir, filename, from)
+	if err != nil {
+		return nil, fmt.Errorf("HATA: %v", err)
+	}
+	fromName := fmt.Sprintf("%s v%d", filename, from)
+
+	out := unifiedDiff(fromName, toName, string(fromContent), toText, 3)
+	data := map[string]interface{}{"filename": filename, "from": from, "to": to}
+	if out == "" {
+		return kernel.Success(fmt.Sprintf("🟰 %s ile %s aynı.", fromName, toName), data), nil
+	}
+	return kernel.Success(out, data), nil
+}
+
+func (h *ToolHistory) rollback(ctx context.Context, filename string, versions []ToolVersion, version int) (*kernel.ToolResult, error) {
+	current := currentVersion(h.WorkspaceDir, filename, versions)
+	if version == 0 {
+		// Testi geçen en son sürüm (Şu an çalışan hariç)
+		for i := len(versions) - 1; i >= 0; i-- {
+			if versions[i].Test == TestPassed && versions[i].Version != current {
+				version = versions[i].Version
+				break
+			}
+		}
+		if version == 0 {
+			return nil, fmt.Errorf("HATA: '%s' için testi geçmiş başka bir sürüm yok; 'version' ile sürüm seç", filename)
+		}
+	}
+	if version == current {
+		return kernel.Success(fmt.Sprintf("ℹ️ %s zaten v%d sürümünde.", filename, version), map[string]interface{}{"filename": filename, "version": version}), nil
+	}
+
+	content, target, err := versionContent(h.WorkspaceDir, filename, version)
+	if err != nil {
+		return nil, fmt.Errorf("HATA: %v", err)
+	}
+
+	// Kayıtsız bir elle değişiklik varsa kaybolmasın
+	fullPath := filepath.Join(h.WorkspaceDir, filename)
+	if _, err := os.Stat(fullPath); err == nil {
+		if _, err := ensureSnapshot(h.WorkspaceDir, filename, "rollback öncesi kayıtsız hal"); err != nil {
+			logger.Warn("⚠️ %s mevcut hali sürüme alınamadı: %v", filename, err)
+		}
+	}
+
+	tempPath := fullPath + ".tmp"
+	if err := os.WriteFile(tempPath, content, 0644); err != nil {
+		return nil, fmt.Errorf("HATA: araç yazılamadı: %v", err)
+	}
+	if err := os.Rename(tempPath, fullPath); err != nil {
+		return nil, fmt.Errorf("HATA: araç yazılamadı: %v", err)
+	}
+	restoreRegistryEntry(h.WorkspaceDir, filename, target.Description, target.Parameters)
+
+	v, err := snapshotTool(h.WorkspaceDir, filename, fmt.Sprintf("rollback: v%d sürümüne dönüldü (%s)", version, kernel.ActorFrom(ctx)), target.Test)
+	if err != nil {
+		return nil, fmt.Errorf("HATA: %v", err)
+	}
+	if h.Reloader != nil {
+		if err := h.Reloader.Reload(filename); err != nil {
+			logger.Warn("⚠️ Araç canlı yenilenemedi: %v", err)
+		}
+	}
+	logger.Action("⏪ %s v%d sürümüne döndürüldü (Yeni sürüm v%d)", filename, version, v.Version)
+
+	text := fmt.Sprintf("⏪ %s, v%d sürümüne döndürüldü (%s). Bu hal v%d olarak kaydedildi.", filename, version, testLabel(target.Test), v.Version)
+	if target.Test != TestPassed {
+		text += "\n⚠️ Bu sürüm testten geçmiş olarak kayıtlı değil; aracı çalıştırıp doğrula."
+	}
+	return &kernel.ToolResult{
+		Status:    kernel.ToolStatusSuccess,
+		Text:      text,
+		Data:      map[string]interface{}{"filename": filename, "restored": version, "version": v.Version, "test": target.Test},
+		Artifacts: []kernel.Artifact{{Kind: "file", Path: fullPath, MimeType: "text/x-python"}},
+	}, nil
+}
+
+// previousVersion: Verilen sürümden önceki kayıtlı sürüm (Yoksa 0)
+func previousVersion(versions []ToolVersion, version int) int {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].Version < version {
+			return versions[i].Version
+		}
+	}
+	return 0
+}
+
+// intArg: JSON'dan gelen sayıyı (float64, string) tam sayıya çevirir ("v3" de kabul edilir).
+func intArg(raw interface{}) int {
+	switch v := raw.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		var n int
+		fmt.Sscanf(strings.TrimPrefix(strings.TrimSpace(v), "v"), "%d", &n)
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,356 @@
+package coding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/skills"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
+)
+
+// testsDirName: Test senaryoları araç klasörünün altında tutulur (Araç silinse de geçmişle birlikte kalır)
+const testsDirName = ".tests"
+
+// testCaseTimeout: Sandbox kapalıyken tek senaryonun süre sınırı ('run' adımıyla aynı)
+const testCaseTimeout = 30 * time.Second
+
+// ToolTestCase: Araca bağlı kalıcı test senaryosu (Girdi argümanları + çıktı beklentileri)
+type ToolTestCase struct {
+	Name   string                 `json:"name"`
+	Args   map[string]interface{} `json:"args"`
+	Expect ToolTestExpect         `json:"expect"`
+}
+
+// ToolTestExpect: Beklentiler; boş alanlar denetlenmez. Çıkış kodu verilmezse 0 beklenir.
+type ToolTestExpect struct {
+	Contains    []string    `json:"contains,omitempty"`     // Çıktıda geçmesi gereken metinler
+	NotContains []string    `json:"not_contains,omitempty"` // Çıktıda geçmemesi gereken metinler
+	Equals      *string     `json:"equals,omitempty"`       // Çıktının tamamı (Baş/son boşluklar hariç)
+	Regex       string      `json:"regex,omitempty"`        // Çıktının eşleşmesi gereken düzenli ifade
+	JSON        interface{} `json:"json,omitempty"`         // Çıktı JSON ise bu alt kümeyi içermeli
+	ExitCode    *int        `json:"exit_code,omitempty"`
+	Error       *string     `json:"error,omitempty"` // Argümanlar reddedilmeli; hata mesajı bu metni içermeli
+}
+
+// TestCaseResult: Tek senaryonun sonucu
+type TestCaseResult struct {
+	Name     string
+	Passed   bool
+	Problems []string
+	Output   string
+	Duration time.Duration
+}
+
+func testsPath(workspaceDir, filename string) string {
+	return filepath.Join(workspaceDir, testsDirName, strings.TrimSuffix(filename, ".py")+".json")
+}
+
+// testCasesSchema: 'tests' parametresinin şeması (dev_studio, edit_python_tool ve test_tool ortak)
+func testCasesSchema(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "array",
+		"description": description,
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string", "description": "Senaryonun benzersiz adı (Aynı adla verilirse eskisinin yerine geçer)"},
+				"args": map[string]interface{}{"type": "object", "description": "Araca gönderilecek argümanlar (Aracın parametre şemasına uymalı)"},
+				"expect": map[string]interface{}{
+					"type":        "object",
+					"description": "Beklentiler. Çıkış kodu verilmezse 0 beklenir.",
+					"properties": map[string]interface{}{
+						"contains":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Çıktıda geçmesi gereken metinler"},
+						"not_contains": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Çıktıda geçmemesi gereken metinler"},
+						"equals":       map[string]interface{}{"type": "string", "description": "Çıktının tamamı"},
+						"regex":        map[string]interface{}{"type": "string", "description": "Çıktının eşleşmesi gereken düzenli ifade"},
+						"json":         map[string]interface{}{"type": "object", "description": "Çıktı JSON ise içermesi gereken alanlar (Alt küme)"},
+						"exit_code":    map[string]interface{}{"type": "integer", "description": "Beklenen çıkış kodu (Hata davranışını test etmek için)"},
+						"error":        map[string]interface{}{"type": "string", "description": "Argümanların şema denetiminde reddedilmesi bekleniyorsa hata mesajında geçecek metin (Boş metin: herhangi bir hata)"},
+					},
+				},
+			},
+			"required": []string{"name", "args", "expect"},
+		},
+	}
+}
+
+// loadTestCases: Aracın kayıtlı senaryoları (Yoksa boş)
+func loadTestCases(workspaceDir, filename string) []ToolTestCase {
+	var cases []ToolTestCase
+	if data, err := os.ReadFile(testsPath(workspaceDir, filename)); err == nil {
+		json.Unmarshal(data, &cases)
+	}
+	return cases
+}
+
+// saveTestCases: Senaryoları atomik olarak yazar (Boşsa dosyayı siler).
+func saveTestCases(workspaceDir, filename string, cases []ToolTestCase) error {
+	path := testsPath(workspaceDir, filename)
+	if len(cases) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("test klasörü oluşturulamadı: %v", err)
+	}
+	data, _ := json.MarshalIndent(cases, "", "  ")
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("test senaryoları yazılamadı: %v", err)
+	}
+	return os.Rename(tempPath, path)
+}
+
+// parseTestCases: Modelden gelen senaryo dizisini çözer ve denetler.
+func parseTestCases(raw interface{}) ([]ToolTestCase, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("HATA: test senaryoları okunamadı: %v", err)
+	}
+	var cases []ToolTestCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("HATA: 'tests' bir senaryo dizisi olmalı ([{\"name\": ..., \"args\": {...}, \"expect\": {...}}]): %v", err)
+	}
+	seen := make(map[string]bool)
+	for i := range cases {
+		c := &cases[i]
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			return nil, fmt.Errorf("HATA: %d. test senaryosunun 'name' alanı boş", i+1)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("HATA: '%s' adlı test senaryosu birden fazla kez verilmiş", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Args == nil {
+			c.Args = map[string]interface{}{}
+		}
+		if c.Expect.Regex != "" {
+			if _, err := regexp.Compile(c.Expect.Regex); err != nil {
+				return nil, fmt.Errorf("HATA: '%s' senaryosunun regex'i geçersiz: %v", c.Name, err)
+			}
+		}
+	}
+	return cases, nil
+}
+
+// mergeTestCases: Aynı adlı senaryoları yenisiyle değiştirir, diğerlerini sona ekler.
+func mergeTestCases(existing, updates []ToolTestCase) []ToolTestCase {
+	merged := append([]ToolTestCase(nil), existing...)
+	for _, u := range updates {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == u.Name {
+				merged[i] = u
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, u)
+		}
+	}
+	return merged
+}
+
+// runTestSuite: Senaryoları diskteki script üzerinde (loader ile aynı şema ve sandbox ayarlarıyla) çalıştırır.
+func runTestSuite(ctx context.Context, workspaceDir, filename, pythonPath string, limits *sandbox.Limits, params map[string]interface{}, cases []ToolTestCase) []TestCaseResult {
+	tool := skills.NewPythonTool(strings.TrimSuffix(filename, ".py"), "", filepath.Join(workspaceDir, filename), pythonPath).
+		WithParameters(params).
+		WithSandbox(limits)
+
+	results := make([]TestCaseResult, 0, len(cases))
+	for _, c := range cases {
+		runCtx, cancel := ctx, context.CancelFunc(func() {})
+		if limits == nil {
+			runCtx, cancel = context.WithTimeout(ctx, testCaseTimeout)
+		}
+		started := time.Now()
+		run, err := tool.Run(runCtx, c.Args)
+		cancel()
+
+		res := TestCaseResult{Name: c.Name, Duration: time.Since(started)}
+		switch {
+		case c.Expect.Error != nil:
+			if err == nil {
+				res.Output = strings.TrimSpace(run.Output)
+				res.Problems = []string{"argümanların reddedilmesi bekleniyordu ama script çalıştı"}
+			} else if !strings.Contains(err.Error(), *c.Expect.Error) {
+				res.Problems = []string{fmt.Sprintf("hata mesajında %q yok: %v", *c.Expect.Error, err)}
+			}
+		case err != nil:
+			res.Problems = []string{err.Error()}
+		default:
+			res.Output = strings.TrimSpace(run.Output)
+			res.Problems = checkExpectations(c.Expect, run)
+		}
+		res.Passed = len(res.Problems) == 0
+		results = append(results, res)
+	}
+	return results
+}
+
+// checkExpectations: Çalışma sonucunu beklentilerle karşılaştırır ve sorunları döner.
+func checkExpectations(expect ToolTestExpect, run *skills.ScriptRun) []string {
+	var problems []string
+	output := strings.TrimSpace(run.Output)
+
+	if run.Violation != nil {
+		problems = append(problems, fmt.Sprintf("sandbox ihlali: %s (%s)", run.Violation.Kind, run.Violation.Detail))
+	}
+	wantExit := 0
+	if expect.ExitCode != nil {
+		wantExit = *expect.ExitCode
+	}
+	if run.ExitCode != wantExit {
+		problems = append(problems, fmt.Sprintf("çıkış kodu %d bekleniyordu, %d geldi", wantExit, run.ExitCode))
+	}
+	for _, s := range expect.Contains {
+		if !strings.Contains(output, s) {
+			problems = append(problems, fmt.Sprintf("çıktıda %q yok", s))
+		}
+	}
+	for _, s := range expect.NotContains {
+		if strings.Contains(output, s) {
+			problems = append(problems, fmt.Sprintf("çıktıda %q olmamalıydı", s))
+		}
+	}
+	if expect.Equals != nil && output != strings.TrimSpace(*expect.Equals) {
+		problems = append(problems, fmt.Sprintf("çıktı %q olmalıydı", strings.TrimSpace(*expect.Equals)))
+	}
+	if expect.Regex != "" {
+		if re, err := regexp.Compile(expect.Regex); err != nil || !re.MatchString(output) {
+			problems = append(problems, fmt.Sprintf("çıktı /%s/ ile eşleşmiyor", expect.Regex))
+		}
+	}
+	if expect.JSON != nil {
+		actual, ok := parseJSONOutput(output)
+		switch {
+		case !ok:
+			problems = append(problems, "çıktı JSON olarak çözülemedi")
+		case !jsonSubset(expect.JSON, actual):
+			want, _ := json.Marshal(expect.JSON)
+			problems = append(problems, fmt.Sprintf("JSON çıktı beklenen alanları içermiyor: %s", want))
+		}
+	}
+	return problems
+}
+
+// parseJSONOutput: Çıktının tamamını, olmazsa son satırını JSON olarak çözer (Öncesinde log basan scriptler için).
+func parseJSONOutput(output string) (interface{}, bool) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(output), &v); err == nil {
+		return v, true
+	}
+	if i := strings.LastIndex(output, "\n"); i >= 0 {
+		if err := json.Unmarshal([]byte(strings.TrimSpace(output[i+1:])), &v); err == nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// jsonSubset: Beklenen nesnedeki her alan gerçek çıktıda aynı değerle var mı? (Diziler birebir karşılaştırılır)
+func jsonSubset(want, got interface{}) bool {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, wv := range w {
+			gv, ok := g[k]
+			if !ok || !jsonSubset(wv, gv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !jsonSubset(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// suitePassed: Tüm senaryolar geçti mi?
+func suitePassed(results []TestCaseResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// formatSuite: Senaryo sonuçlarının okunur raporu
+func formatSuite(filename string, results []TestCaseResult) string {
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🧪 %s test senaryoları: %d/%d geçti\n", filename, passed, len(results)))
+	for _, r := range results {
+		if r.Passed {
+			sb.WriteString(fmt.Sprintf("  ✅ %s (%d ms)\n", r.Name, r.Duration.Milliseconds()))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  ❌ %s: %s\n", r.Name, strings.Join(r.Problems, "; ")))
+		if r.Output != "" {
+			out := r.Output
+			if len(out) > 500 {
+				out = "...\n" + out[len(out)-500:]
+			}
+			sb.WriteString("     Çıktı: " + strings.ReplaceAll(out, "\n", "\n     ") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// suiteData: Sonuçların yapısal hali (ToolResult.Data["tests"])
+func suiteData(results []TestCaseResult) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		item := map[string]interface{}{"name": r.Name, "passed": r.Passed, "duration_ms": r.Duration.Milliseconds()}
+		if len(r.Problems) > 0 {
+			item["problems"] = r.Problems
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// toolParameters: Test argümanlarını denetlemek için aracın geçerli şeması (Registry, yoksa script başlığı)
+func toolParameters(workspaceDir, filename string) map[string]interface{} {
+	params := registryParameters(workspaceDir, filename)
+	if params == nil {
+		params = skills.HeaderParameters(filepath.Join(workspaceDir, filename))
+	}
+	normalized, err := skills.NormalizeSchema(params)
+	if err != nil {
+		return nil
+	}
+	return normalized
+}
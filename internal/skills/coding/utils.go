@@ -14,16 +14,27 @@ import (
 )
 
 type ToolMeta struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Filename    string `json:"filename"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Filename    string                 `json:"filename"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"` // Tipli parametre şeması (Yoksa serbest 'args')
+}
+
+// ToolReloader: Diskteki araç değişikliklerini çalışan yetenek yöneticisine yansıtır (skills.Loader uygular).
+// Nil ise araçlar bir sonraki başlangıçta (veya klasör izleyicisi fark edince) yüklenir.
+type ToolReloader interface {
+	Reload(filename string) error
+	Remove(filename string)
+	Hold(filename string)    // Düzenleme sürerken klasör izleyicisi dosyayı yüklemesin
+	Release(filename string) // Düzenleme bitti (Başarılı veya rollback)
 }
 
 // 🛡️ REGISTRY KİLİDİ: Aynı anda iki işlem registry dosyasını bozmasın diye.
 var registryMutex sync.Mutex
 
 // updateRegistryFile: Registry'ye yeni araç ekler veya günceller (Thread-Safe & Atomic)
-func updateRegistryFile(workspaceDir, filename, desc string) {
+// params nil ise kayıtlı şema korunur.
+func updateRegistryFile(workspaceDir, filename, desc string, params map[string]interface{}) {
 	registryMutex.Lock()
 	defer registryMutex.Unlock()
 
@@ -38,6 +49,9 @@ func updateRegistryFile(workspaceDir, filename, desc string) {
 	for i, t := range tools {
 		if t.Filename == filename {
 			if desc != "" { tools[i].Description = desc }
+			if params != nil {
+				tools[i].Parameters = params
+			}
 			tools[i].Name = strings.TrimSuffix(filename, ".py")
 			found = true
 			break
@@ -48,6 +62,7 @@ func updateRegistryFile(workspaceDir, filename, desc string) {
 			Name:        strings.TrimSuffix(filename, ".py"),
 			Description: desc,
 			Filename:    filename,
+			Parameters:  params,
 		})
 	}
 
@@ -59,6 +74,64 @@ func updateRegistryFile(workspaceDir, filename, desc string) {
 	os.Rename(tempPath, regPath) // İşletim sistemi bu işlemi milisaniyede ve güvenle yapar
 }
 
+// registryParameters: Registry'de araç için kayıtlı parametre şeması (Yoksa nil)
+func registryParameters(workspaceDir, filename string) map[string]interface{} {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	var tools []ToolMeta
+	if data, err := os.ReadFile(filepath.Join(workspaceDir, "registry.json")); err == nil {
+		json.Unmarshal(data, &tools)
+	}
+	for _, t := range tools {
+		if t.Filename == filename {
+			return t.Parameters
+		}
+	}
+	return nil
+}
+
+// registryMeta: Registry'de araç için kayıtlı metadata (Yoksa nil)
+func registryMeta(workspaceDir, filename string) *ToolMeta {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	var tools []ToolMeta
+	if data, err := os.ReadFile(filepath.Join(workspaceDir, "registry.json")); err == nil {
+		json.Unmarshal(data, &tools)
+	}
+	for _, t := range tools {
+		if t.Filename == filename {
+			return &t
+		}
+	}
+	return nil
+}
+
+// restoreRegistryEntry: Eski bir sürüme dönerken açıklama ve şemayı olduğu gibi geri yazar (nil şema siler).
+func restoreRegistryEntry(workspaceDir, filename, desc string, params map[string]interface{}) {
+	updateRegistryFile(workspaceDir, filename, desc, nil)
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	regPath := filepath.Join(workspaceDir, "registry.json")
+	var tools []ToolMeta
+	if data, err := os.ReadFile(regPath); err == nil {
+		json.Unmarshal(data, &tools)
+	}
+	for i, t := range tools {
+		if t.Filename == filename {
+			tools[i].Parameters = params
+		}
+	}
+
+	data, _ := json.MarshalIndent(tools, "", "  ")
+	tempPath := regPath + ".tmp"
+	os.WriteFile(tempPath, data, 0644)
+	os.Rename(tempPath, regPath)
+}
+
 // removeFromRegistryFile: Registry'den aracı siler (Thread-Safe & Atomic)
 func removeFromRegistryFile(workspaceDir, filename string) {
 	registryMutex.Lock()
@@ -127,7 +200,8 @@ func installDependencies(ctx context.Context, pipPath string, packagesStr string
 }
 
 // formatPythonCode: Rick'in koduna "God Mode" zırhı giydirir.
-func formatPythonCode(filename, desc, code string) string {
+// Parametre şeması verilirse başlığa tek satırlık JSON olarak yazılır (Registry olmadan da yüklenebilsin diye).
+func formatPythonCode(filename, desc, code string, params map[string]interface{}) string {
 	// Markdown kalıntılarını temizle
 	code = strings.TrimPrefix(code, "```python")
 	code = strings.TrimPrefix(code, "```")
@@ -135,9 +209,15 @@ func formatPythonCode(filename, desc, code string) string {
 	code = strings.TrimSpace(code)
 
 	// 🚀 ZIRHLI WRAPPER:
+	header := fmt.Sprintf("NAME: %s\nDESCRIPTION: %s", filename, desc)
+	if params != nil {
+		if data, err := json.Marshal(params); err == nil {
+			header += "\nPARAMETERS: " + string(data)
+		}
+	}
+
 	wrapper := `"""
-NAME: %s
-DESCRIPTION: %s
+%s
 """
 import sys, json, os, io, traceback
 
@@ -179,5 +259,5 @@ if len(sys.argv) > 1:
 # --- RICK'S AUTONOMOUS CODE END ---
 # ==========================================
 `
-	return fmt.Sprintf(wrapper, filename, desc, code)
+	return fmt.Sprintf(wrapper, header, code)
 }
\ No newline at end of file
@@ -0,0 +1,264 @@
+package coding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Test sonuçları (ToolVersion.Test)
+const (
+	TestPassed   = "passed"   // 'run' adımı başarılı
+	TestFailed   = "failed"   // Syntax, kurulum veya 'run' adımı başarısız (Değişiklik geri alındı)
+	TestUntested = "untested" // Yazıldı ama çalıştırılmadı
+)
+
+// historyDirName: Sürümler araç klasörünün altında tutulur (Yükleyici klasörleri atlar)
+const historyDirName = ".history"
+
+// maxToolVersions: Araç başına saklanan en fazla sürüm (Eskiler silinir, numaralar kaymaz)
+const maxToolVersions = 50
+
+// ToolVersion: Bir araç dosyasının kayıtlı anlık görüntüsü
+type ToolVersion struct {
+	Version     int                    `json:"version"`
+	CreatedAt   time.Time              `json:"created_at"`
+	Reason      string                 `json:"reason"`
+	Test        string                 `json:"test"`
+	SHA256      string                 `json:"sha256"`
+	Size        int                    `json:"size"`
+	Description string                 `json:"description,omitempty"` // Registry'deki açıklama (Geri dönüşte geri yazılır)
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`  // Registry'deki şema
+}
+
+// 🛡️ GEÇMİŞ KİLİDİ: Aynı anda iki araç aynı dosyanın geçmişine yazmasın diye.
+var historyMutex sync.Mutex
+
+func historyDir(workspaceDir, filename string) string {
+	return filepath.Join(workspaceDir, historyDirName, filename)
+}
+
+func versionPath(workspaceDir, filename string, version int) string {
+	return filepath.Join(historyDir(workspaceDir, filename), fmt.Sprintf("v%04d.py", version))
+}
+
+// snapshotTool: Aracın diskteki halini yeni sürüm olarak kaydeder. İçerik son sürümle aynıysa yeni sürüm
+// açılmaz; (daha bilgili ise) test sonucu ile registry bilgisi güncellenir ve son sürüm döner.
+func snapshotTool(workspaceDir, filename, reason, test string) (*ToolVersion, error) {
+	content, err := os.ReadFile(filepath.Join(workspaceDir, filename))
+	if err != nil {
+		return nil, fmt.Errorf("sürüm alınamadı: %v", err)
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	versions := readVersions(workspaceDir, filename)
+	sum := contentHash(content)
+	if n := len(versions); n > 0 && versions[n-1].SHA256 == sum {
+		last := &versions[n-1]
+		if test != "" && test != TestUntested {
+			last.Test = test
+		}
+		if meta := registryMeta(workspaceDir, filename); meta != nil {
+			last.Description = meta.Description
+			last.Parameters = meta.Parameters
+		}
+		if err := writeVersions(workspaceDir, filename, versions); err != nil {
+			return nil, err
+		}
+		return last, nil
+	}
+	return appendVersion(workspaceDir, filename, versions, content, sum, reason, test)
+}
+
+// ensureSnapshot: Diskteki içerik herhangi bir sürümde yoksa kaydeder (Elle yapılan değişiklikler veya
+// geçmişi olmayan eski araçlar). Varsa o sürümü döner.
+func ensureSnapshot(workspaceDir, filename, reason string) (*ToolVersion, error) {
+	content, err := os.ReadFile(filepath.Join(workspaceDir, filename))
+	if err != nil {
+		return nil, fmt.Errorf("sürüm alınamadı: %v", err)
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	versions := readVersions(workspaceDir, filename)
+	sum := contentHash(content)
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].SHA256 == sum {
+			return &versions[i], nil
+		}
+	}
+	return appendVersion(workspaceDir, filename, versions, content, sum, reason, TestUntested)
+}
+
+// markVersionTest: Kayıtlı bir sürümün test sonucunu günceller; registry testten sonra yazıldığı için
+// açıklama ve şemayı da tazeler.
+func markVersionTest(workspaceDir, filename string, version int, test string) {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	versions := readVersions(workspaceDir, filename)
+	for i := range versions {
+		if versions[i].Version == version {
+			versions[i].Test = test
+			if meta := registryMeta(workspaceDir, filename); meta != nil {
+				versions[i].Description = meta.Description
+				versions[i].Parameters = meta.Parameters
+			}
+			writeVersions(workspaceDir, filename, versions)
+			return
+		}
+	}
+}
+
+// listVersions: Aracın kayıtlı sürümleri (Eskiden yeniye)
+func listVersions(workspaceDir, filename string) []ToolVersion {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+	return readVersions(workspaceDir, filename)
+}
+
+// versionContent: Sürümün içeriği ve kaydı
+func versionContent(workspaceDir, filename string, version int) ([]byte, *ToolVersion, error) {
+	for _, v := range listVersions(workspaceDir, filename) {
+		if v.Version != version {
+			continue
+		}
+		content, err := os.ReadFile(versionPath(workspaceDir, filename, version))
+		if err != nil {
+			return nil, nil, fmt.Errorf("v%d içeriği okunamadı: %v", version, err)
+		}
+		return content, &v, nil
+	}
+	return nil, nil, fmt.Errorf("'%s' için v%d bulunamadı", filename, version)
+}
+
+// trackedTools: Geçmişi olan araç dosyaları (Silinmiş olanlar dahil)
+func trackedTools(workspaceDir string) []string {
+	entries, err := os.ReadDir(filepath.Join(workspaceDir, historyDirName))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// currentVersion: Diskteki içerikle aynı olan en yeni sürüm (Dosya yoksa veya kayıtsız bir değişiklikse 0)
+func currentVersion(workspaceDir, filename string, versions []ToolVersion) int {
+	content, err := os.ReadFile(filepath.Join(workspaceDir, filename))
+	if err != nil {
+		return 0
+	}
+	sum := contentHash(content)
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].SHA256 == sum {
+			return versions[i].Version
+		}
+	}
+	return 0
+}
+
+// appendVersion: Kilit altında çağrılmalıdır.
+func appendVersion(workspaceDir, filename string, versions []ToolVersion, content []byte, sum, reason, test string) (*ToolVersion, error) {
+	if test == "" {
+		test = TestUntested
+	}
+	next := 1
+	if n := len(versions); n > 0 {
+		next = versions[n-1].Version + 1
+	}
+
+	dir := historyDir(workspaceDir, filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("sürüm klasörü oluşturulamadı: %v", err)
+	}
+	if err := os.WriteFile(versionPath(workspaceDir, filename, next), content, 0644); err != nil {
+		return nil, fmt.Errorf("sürüm yazılamadı: %v", err)
+	}
+
+	v := ToolVersion{
+		Version:   next,
+		CreatedAt: time.Now(),
+		Reason:    reason,
+		Test:      test,
+		SHA256:    sum,
+		Size:      len(content),
+	}
+	if meta := registryMeta(workspaceDir, filename); meta != nil {
+		v.Description = meta.Description
+		v.Parameters = meta.Parameters
+	}
+	versions = append(versions, v)
+
+	// Eski sürümleri buda
+	for len(versions) > maxToolVersions {
+		os.Remove(versionPath(workspaceDir, filename, versions[0].Version))
+		versions = versions[1:]
+	}
+	if err := writeVersions(workspaceDir, filename, versions); err != nil {
+		return nil, err
+	}
+	return &versions[len(versions)-1], nil
+}
+
+func readVersions(workspaceDir, filename string) []ToolVersion {
+	var versions []ToolVersion
+	if data, err := os.ReadFile(filepath.Join(historyDir(workspaceDir, filename), "index.json")); err == nil {
+		json.Unmarshal(data, &versions)
+	}
+	return versions
+}
+
+// writeVersions: Atomik yazma (registry.json ile aynı yöntem)
+func writeVersions(workspaceDir, filename string, versions []ToolVersion) error {
+	data, _ := json.MarshalIndent(versions, "", "  ")
+	indexPath := filepath.Join(historyDir(workspaceDir, filename), "index.json")
+	tempPath := indexPath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("sürüm dizini yazılamadı: %v", err)
+	}
+	return os.Rename(tempPath, indexPath)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// testLabel: Test sonucunun okunur hali
+func testLabel(test string) string {
+	switch test {
+	case TestPassed:
+		return "✅ test geçti"
+	case TestFailed:
+		return "❌ test başarısız"
+	}
+	return "⚪ test edilmedi"
+}
+
+// toolFilename: Kullanıcı/model girdisini güvenli dosya adına çevirir ("hava" -> "hava.py")
+func toolFilename(raw string) string {
+	name := filepath.Base(strings.TrimSpace(raw))
+	if name == "." || name == string(filepath.Separator) || name == "" {
+		return ""
+	}
+	if !strings.HasSuffix(name, ".py") {
+		name += ".py"
+	}
+	return name
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
 )
 
@@ -31,24 +32,29 @@ func (t *WriteTool) Parameters() map[string]interface{} {
 }
 
 func (t *WriteTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Yazılan dosyayı artifact, yolu ve modu yapısal veri olarak döner.
+func (t *WriteTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	// 🛡️ GÜVENLİ TÜR DÖNÜŞÜMÜ (PANIC KORUMASI)
 	pathRaw, ok := args["path"]
 	if !ok || pathRaw == nil {
-		return "", fmt.Errorf("HATA: 'path' parametresi eksik. Nereye yazacağımı belirtmelisin")
+		return nil, fmt.Errorf("HATA: 'path' parametresi eksik. Nereye yazacağımı belirtmelisin")
 	}
 	pathStr, ok := pathRaw.(string)
 	if !ok {
-		return "", fmt.Errorf("HATA: 'path' parametresi metin (string) formatında olmalı")
+		return nil, fmt.Errorf("HATA: 'path' parametresi metin (string) formatında olmalı")
 	}
 	path := ResolvePath(pathStr)
 
 	contentRaw, ok := args["content"]
 	if !ok || contentRaw == nil {
-		return "", fmt.Errorf("HATA: 'content' parametresi eksik. Dosyaya ne yazacağımı belirtmelisin")
+		return nil, fmt.Errorf("HATA: 'content' parametresi eksik. Dosyaya ne yazacağımı belirtmelisin")
 	}
 	contentStr, ok := contentRaw.(string)
 	if !ok {
-		return "", fmt.Errorf("HATA: 'content' parametresi metin (string) formatında olmalı")
+		return nil, fmt.Errorf("HATA: 'content' parametresi metin (string) formatında olmalı")
 	}
 	content := contentStr
 
@@ -65,7 +71,7 @@ func (t *WriteTool) Execute(ctx context.Context, args map[string]interface{}) (s
 
 	// Klasör hiyerarşisini garantiye al
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Dosya hiç yoksa insert veya append yapamayız, mecburen overwrite moduna dönüyoruz
@@ -78,20 +84,20 @@ func (t *WriteTool) Execute(ctx context.Context, args map[string]interface{}) (s
 		// Dosyayı sadece yazma ve ekleme modunda aç
 		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		defer f.Close()
 		if _, err := f.WriteString("\n" + content); err != nil {
-			return "", err
+			return nil, err
 		}
 		logger.Success("💾 Dosya Sonuna Eklendi: %s", path)
-		return fmt.Sprintf("✅ İşlem Başarılı: %s dosyasına içerik eklendi (append).", path), nil
+		return written(path, mode, fmt.Sprintf("✅ İşlem Başarılı: %s dosyasına içerik eklendi (append).", path)), nil
 
 	case "insert":
 		// Dosyayı oku ve satırlara böl
 		fileBytes, err := os.ReadFile(path)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		
 		lines := strings.Split(string(fileBytes), "\n")
@@ -113,17 +119,27 @@ func (t *WriteTool) Execute(ctx context.Context, args map[string]interface{}) (s
 		finalContent := strings.Join(newLines, "\n")
 		
 		if err := os.WriteFile(path, []byte(finalContent), 0644); err != nil {
-			return "", err
+			return nil, err
 		}
 		logger.Success("💾 Dosyaya Satır Eklendi (Satır: %d): %s", line, path)
-		return fmt.Sprintf("✅ İşlem Başarılı: %s dosyasının %d. satırına içerik yerleştirildi (insert).", path, line), nil
+		return written(path, mode, fmt.Sprintf("✅ İşlem Başarılı: %s dosyasının %d. satırına içerik yerleştirildi (insert).", path, line)), nil
 
 	default: // "overwrite"
 		// Mevcut sistemin çalıştığı gibi her şeyi ezer 
 		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-			return "", err
+			return nil, err
 		}
 		logger.Success("💾 Dosya Üzerine Yazıldı: %s", path)
-		return fmt.Sprintf("✅ İşlem Başarılı: %s dosyası baştan yaratıldı (overwrite).", path), nil
+		return written(path, mode, fmt.Sprintf("✅ İşlem Başarılı: %s dosyası baştan yaratıldı (overwrite).", path)), nil
+	}
+}
+
+// written: Yazma işlemlerinin ortak başarılı sonucu
+func written(path, mode, text string) *kernel.ToolResult {
+	return &kernel.ToolResult{
+		Status:    kernel.ToolStatusSuccess,
+		Text:      text,
+		Data:      map[string]interface{}{"path": path, "mode": mode},
+		Artifacts: []kernel.Artifact{{Kind: "file", Path: path}},
 	}
-}
\ No newline at end of file
+}
@@ -1,82 +1,354 @@
 package skills
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
 )
 
-// Loader: Diskten araçları okuyup Manager'a yükler.
+// Loader: Diskten araçları okuyup Manager'a yükler ve diskteki değişiklikleri çalışan yöneticiye yansıtır.
 type Loader struct {
 	Manager    *Manager
 	ToolsDir   string
-	PythonPath string // venv/bin/python
+	PythonPath string          // venv/bin/python
+	Sandbox    *sandbox.Limits // Doluysa araçlar bu sınırlarla sandbox içinde çalışır
+
+	loaded map[string]loadedTool // Dosya adı -> yöneticiye kaydedilen araç
+	held   map[string]int        // Düzenlemesi süren dosyalar (Sync bunlara dokunmaz)
+	mu     sync.Mutex
+}
+
+// loadedTool: Bir dosyadan kaydedilen aracın, değişikliği fark etmeye yetecek özeti
+type loadedTool struct {
+	name    string
+	desc    string
+	params  string // Şemanın JSON hali (Değişikliği fark etmek için)
+	modTime time.Time
+	size    int64
+	blocked bool // Yerleşik bir araçla aynı adı taşıdığı veya manifesti geçersiz olduğu için kaydedilmedi (Uyarı bir kez verilir)
 }
 
+type registryEntry struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Filename    string                 `json:"filename"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// headerParamsPrefix: formatPythonCode'un yazdığı başlıktaki şema satırı
+const headerParamsPrefix = "PARAMETERS:"
+
 func NewLoader(mgr *Manager, toolsDir, pythonPath string) *Loader {
 	return &Loader{
 		Manager:    mgr,
 		ToolsDir:   toolsDir,
 		PythonPath: pythonPath,
+		loaded:     make(map[string]loadedTool),
+		held:       make(map[string]int),
 	}
 }
 
 // LoadAll: Klasörü tarar ve geçerli araçları yükler.
 func (l *Loader) LoadAll() error {
-	// 1. Registry dosyasını oku (Metadata için)
-	regPath := filepath.Join(l.ToolsDir, "registry.json")
-	var registry []struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Filename    string `json:"filename"`
+	if _, err := os.ReadDir(l.ToolsDir); err != nil {
+		return fmt.Errorf("araç klasörü okunamadı: %v", err)
 	}
+	l.Sync()
 
-	data, err := os.ReadFile(regPath)
-	if err == nil {
-		json.Unmarshal(data, &registry)
+	l.mu.Lock()
+	count := 0
+	for _, t := range l.loaded {
+		if !t.blocked {
+			count++
+		}
 	}
+	l.mu.Unlock()
+	logger.Info("📂 Diskten %d adet yetenek yüklendi.", count)
+	return nil
+}
+
+// Sync: Klasörü ve registry.json'ı yüklü araçlarla karşılaştırır; yeni/değişen dosyaları kaydeder,
+// diskten silinenleri yöneticiden çıkarır. Değişiklik sayısını döner.
+func (l *Loader) Sync() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	// 2. Python dosyalarını bul
 	entries, err := os.ReadDir(l.ToolsDir)
 	if err != nil {
-		return fmt.Errorf("araç klasörü okunamadı: %v", err)
+		return 0
 	}
+	registry := l.readRegistry()
 
-	count := 0
+	changes := 0
+	present := make(map[string]bool)
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".py") {
+		if entry.IsDir() || (!strings.HasSuffix(entry.Name(), ".py") && !IsManifest(entry.Name())) {
+			continue
+		}
+		present[entry.Name()] = true
+		if l.held[entry.Name()] > 0 {
 			continue
 		}
+		if l.loadLocked(entry.Name(), registry) {
+			changes++
+		}
+	}
+	for filename := range l.loaded {
+		if !present[filename] && l.held[filename] == 0 {
+			l.removeLocked(filename)
+			changes++
+		}
+	}
+	return changes
+}
+
+// Reload: Tek dosyayı (yeni yazılmış veya düzenlenmiş) hemen yeniden kaydeder.
+func (l *Loader) Reload(filename string) error {
+	filename = filepath.Base(filename)
+	if _, err := os.Stat(filepath.Join(l.ToolsDir, filename)); err != nil {
+		return fmt.Errorf("araç dosyası bulunamadı: %s", filename)
+	}
 
-		filename := entry.Name()
-		name := strings.TrimSuffix(filename, ".py")
-		desc := "Otomatik yüklenen Python aracı."
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if prev, ok := l.loaded[filename]; ok {
+		prev.modTime = time.Time{} // Değişmemiş görünse bile yeniden kaydet
+		l.loaded[filename] = prev
+	}
+	l.loadLocked(filename, l.readRegistry())
+	return nil
+}
 
-		// Registry'den açıklama bulmaya çalış
-		for _, meta := range registry {
-			if meta.Filename == filename {
-				desc = meta.Description
-				if meta.Name != "" {
-					name = meta.Name
+// Remove: Dosyadan kaydedilmiş aracı yöneticiden çıkarır.
+func (l *Loader) Remove(filename string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeLocked(filepath.Base(filename))
+}
+
+// Hold: Dosyanın düzenlemesi bitene kadar Sync onu yüklemez veya çıkarmaz (Test edilmemiş ara hal canlıya geçmesin).
+// Reload ile yapılan açık yenileme etkilenmez. Her Hold bir Release ile kapatılmalıdır.
+func (l *Loader) Hold(filename string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held[filepath.Base(filename)]++
+}
+
+// Release: Hold'u kaldırır; dosyanın son hali bir sonraki Sync'te yansıtılır.
+func (l *Loader) Release(filename string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	filename = filepath.Base(filename)
+	if l.held[filename]--; l.held[filename] <= 0 {
+		delete(l.held, filename)
+	}
+}
+
+// Watch: Klasörü verilen aralıkla yoklar ve elle yapılan değişiklikleri de canlı yansıtır (ctx iptal edilene kadar).
+func (l *Loader) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n := l.Sync(); n > 0 {
+					logger.Debug("🔄 Araç klasöründe %d değişiklik yansıtıldı.", n)
 				}
-				break
 			}
 		}
+	}()
+}
 
-		fullPath := filepath.Join(l.ToolsDir, filename)
-		
-		// Adapter ile sarmala
-		tool := NewPythonTool(name, desc, fullPath, l.PythonPath)
-		
-		// Manager'a kaydet
-		l.Manager.Register(tool)
-		count++
+// loadLocked: Dosyayı (yeni veya değişmişse) kaydeder; kaydettiyse true döner. Kilit altında çağrılmalıdır.
+func (l *Loader) loadLocked(filename string, registry []registryEntry) bool {
+	fullPath := filepath.Join(l.ToolsDir, filename)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return false
+	}
+	if IsManifest(filename) {
+		return l.loadManifestLocked(filename, info)
 	}
 
-	logger.Info("📂 Diskten %d adet yetenek yüklendi.", count)
+	name := strings.TrimSuffix(filename, ".py")
+	desc := "Otomatik yüklenen Python aracı."
+	var params map[string]interface{}
+	// Registry'den açıklama ve şema bulmaya çalış
+	for _, meta := range registry {
+		if meta.Filename == filename {
+			if meta.Description != "" {
+				desc = meta.Description
+			}
+			if meta.Name != "" {
+				name = meta.Name
+			}
+			params = meta.Parameters
+			break
+		}
+	}
+	// Registry'de şema yoksa script başlığına bak (Testi henüz geçmemiş veya elle kopyalanmış araçlar)
+	if params == nil {
+		params = HeaderParameters(fullPath)
+	}
+	if params != nil {
+		normalized, err := NormalizeSchema(params)
+		if err != nil {
+			logger.Warn("⚠️ %s parametre şeması geçersiz, serbest argümanla yüklenecek: %v", filename, err)
+		}
+		params = normalized
+	}
+	paramsKey := ""
+	if params != nil {
+		data, _ := json.Marshal(params)
+		paramsKey = string(data)
+	}
+
+	prev, known := l.loaded[filename]
+	if known && prev.name == name && prev.desc == desc && prev.params == paramsKey && prev.modTime.Equal(info.ModTime()) && prev.size == info.Size() {
+		return false
+	}
+
+	if known && !prev.blocked && prev.name != name {
+		l.Manager.Unregister(prev.name)
+	}
+
+	// Yerleşik (Go) araçların yerine Python aracı geçemez
+	if existing, err := l.Manager.GetTool(name); err == nil {
+		if !isDiskTool(existing) {
+			logger.Warn("⚠️ '%s' bir sistem aracının adı; %s yüklenmedi.", name, filename)
+			l.loaded[filename] = loadedTool{name: name, desc: desc, params: paramsKey, modTime: info.ModTime(), size: info.Size(), blocked: true}
+			return false
+		}
+	}
+
+	tool := NewPythonTool(name, desc, fullPath, l.PythonPath).WithParameters(params).WithSandbox(l.Sandbox)
+	l.Manager.Register(tool)
+	l.loaded[filename] = loadedTool{name: name, desc: desc, params: paramsKey, modTime: info.ModTime(), size: info.Size()}
+	if known && !prev.blocked {
+		logger.Info("🔄 Araç güncellendi: %s (%s)", name, filename)
+	} else {
+		logger.Debug("🧩 Araç yüklendi: %s (%s)", name, filename)
+	}
+	return true
+}
+
+// loadManifestLocked: Manifesti (yeni veya değişmişse) okuyup aracı kaydeder. Geçersiz manifest bir kez uyarılır
+// ve dosya değişene kadar yeniden denenmez. Kilit altında çağrılmalıdır.
+func (l *Loader) loadManifestLocked(filename string, info os.FileInfo) bool {
+	prev, known := l.loaded[filename]
+	if known && prev.modTime.Equal(info.ModTime()) && prev.size == info.Size() {
+		return false
+	}
+	fullPath := filepath.Join(l.ToolsDir, filename)
+	invalid := func(name string, err error) bool {
+		logger.Warn("⚠️ %s yüklenmedi: %v", filename, err)
+		if known && !prev.blocked {
+			l.Manager.Unregister(prev.name)
+			policy.Declare(prev.name, nil)
+		}
+		l.loaded[filename] = loadedTool{name: name, modTime: info.ModTime(), size: info.Size(), blocked: true}
+		return known && !prev.blocked
+	}
+
+	m, err := ReadManifest(fullPath)
+	if err != nil {
+		return invalid("", err)
+	}
+	tool, err := NewManifestTool(m, fullPath, l.PythonPath)
+	if err != nil {
+		return invalid(m.Name, err)
+	}
+	if existing, err := l.Manager.GetTool(m.Name); err == nil && !isDiskTool(existing) {
+		return invalid(m.Name, fmt.Errorf("'%s' bir sistem aracının adı", m.Name))
+	}
+	if known && !prev.blocked && prev.name != m.Name {
+		l.Manager.Unregister(prev.name)
+		policy.Declare(prev.name, nil)
+	}
+	if err := policy.Declare(m.Name, tool.Capabilities()); err != nil {
+		return invalid(m.Name, err)
+	}
+
+	l.Manager.Register(tool.WithSandbox(l.Sandbox))
+	l.loaded[filename] = loadedTool{name: m.Name, desc: m.Description, modTime: info.ModTime(), size: info.Size()}
+	if known && !prev.blocked {
+		logger.Info("🔄 Araç güncellendi: %s (%s)", m.Name, filename)
+	} else {
+		logger.Debug("🧩 Araç yüklendi: %s (%s, %s)", m.Name, filename, policy.Class(m.Name))
+	}
+	return true
+}
+
+// isDiskTool: Araç klasöründen yüklenmiş (Python veya manifest) bir araç mı? Yerleşik araçların yerine bunlar geçemez.
+func isDiskTool(t kernel.Tool) bool {
+	switch t.(type) {
+	case *PythonTool, *ManifestTool:
+		return true
+	}
+	return false
+}
+
+// removeLocked: Kilit altında çağrılmalıdır.
+func (l *Loader) removeLocked(filename string) {
+	prev, ok := l.loaded[filename]
+	if !ok {
+		return
+	}
+	delete(l.loaded, filename)
+	if IsManifest(filename) && !prev.blocked {
+		policy.Declare(prev.name, nil)
+	}
+	if !prev.blocked && l.Manager.Unregister(prev.name) {
+		logger.Info("🗑️ Araç kaldırıldı: %s (%s)", prev.name, filename)
+	}
+}
+
+// readRegistry: registry.json'daki metadata (Dosya yoksa veya bozuksa boş)
+func (l *Loader) readRegistry() []registryEntry {
+	var registry []registryEntry
+	if data, err := os.ReadFile(filepath.Join(l.ToolsDir, "registry.json")); err == nil {
+		json.Unmarshal(data, &registry)
+	}
+	return registry
+}
+
+// HeaderParameters: Script başındaki docstring'de "PARAMETERS: {...}" satırı varsa şemayı çözer.
+func HeaderParameters(path string) map[string]interface{} {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for i := 0; i < 10 && scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, headerParamsPrefix) {
+			continue
+		}
+		var params map[string]interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, headerParamsPrefix))), &params); err != nil {
+			return nil
+		}
+		return params
+	}
 	return nil
-}
\ No newline at end of file
+}
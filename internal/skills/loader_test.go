@@ -0,0 +1,44 @@
+package skills
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncSkipsHeldFiles(t *testing.T) {
+	dir := t.TempDir()
+	mgr := NewManager()
+	l := NewLoader(mgr, dir, "python3")
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("print('ok')\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loaded := func(name string) bool {
+		_, err := mgr.GetTool(name)
+		return err == nil
+	}
+
+	write("eski.py")
+	l.Sync()
+	l.Hold("eski.py")
+	l.Hold("yeni.py")
+	write("yeni.py")
+	os.Remove(filepath.Join(dir, "eski.py"))
+	l.Sync()
+	if loaded("yeni") {
+		t.Fatal("düzenlemesi süren yeni dosya yüklenmemeliydi")
+	}
+	if !loaded("eski") {
+		t.Fatal("düzenlemesi süren dosya yöneticiden çıkarılmamalıydı")
+	}
+
+	l.Release("yeni.py")
+	l.Release("eski.py")
+	l.Sync()
+	if !loaded("yeni") || loaded("eski") {
+		t.Fatalf("Release sonrası Sync diski yansıtmalıydı (yeni: %v, eski: %v)", loaded("yeni"), loaded("eski"))
+	}
+}
@@ -30,6 +30,18 @@ func (m *Manager) Register(t kernel.Tool) {
 	m.tools[t.Name()] = t
 }
 
+// Unregister: Aracı sistemden çıkarır (Thread-safe). Araç kayıtlıysa true döner.
+func (m *Manager) Unregister(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tools[name]; !exists {
+		return false
+	}
+	delete(m.tools, name)
+	return true
+}
+
 // GetTool: İsmi verilen aracı bulur.
 func (m *Manager) GetTool(name string) (kernel.Tool, error) {
 	m.mu.RLock()
@@ -0,0 +1,368 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/sandbox"
+)
+
+// manifestSuffixes: Araç klasöründe manifest sayılan dosyalar (Örn: disk_report.tool.yaml)
+var manifestSuffixes = []string{".tool.yaml", ".tool.yml", ".tool.json"}
+
+// maxManifestStderr: Hata mesajına eklenen stderr'in en fazla uzunluğu
+const maxManifestStderr = 4000
+
+// hostEnv: Sandbox kapalıyken temel değişkenlere ek olarak aktarılan, sır içermeyen değişkenler
+var hostEnv = []string{"HOME", "USER", "USERPROFILE", "TMPDIR", "TEMP", "TMP"}
+
+// Manifest: Her dilde yazılabilen aracın tanımı. Araç argümanları stdin'den tek bir JSON nesnesi olarak okur,
+// sonucunu stdout'a {"text": "...", "data": {...}, "error": "..."} biçiminde yazar (Düz metin de kabul edilir).
+type Manifest struct {
+	Name           string                 `json:"name"`        // Boşsa dosya adı (disk_report.tool.yaml -> disk_report)
+	Description    string                 `json:"description"` // Modelin göreceği açıklama
+	Parameters     map[string]interface{} `json:"parameters"`  // JSON Schema (Kök tipi object)
+	Command        string                 `json:"command"`     // Çalıştırılabilir veya script; manifeste göre göreli olabilir
+	Interpreter    string                 `json:"interpreter"` // bash, node, python... (Boşsa command doğrudan çalışır)
+	Args           []string               `json:"args"`        // command'den sonra eklenen sabit argümanlar
+	TimeoutSeconds int                    `json:"timeout_seconds"`
+	Capabilities   []string               `json:"capabilities"` // Ek yetkiler: read, write, network, code, memory (exec her zaman dahildir)
+	Env            []string               `json:"env"`          // Sandbox'ta araca aktarılacak ortam değişkenleri (Örn: API anahtarları)
+}
+
+// IsManifest: Dosya adı bir araç manifesti mi?
+func IsManifest(filename string) bool {
+	return manifestStem(filename) != ""
+}
+
+func manifestStem(filename string) string {
+	lower := strings.ToLower(filename)
+	for _, suffix := range manifestSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return filename[:len(filename)-len(suffix)]
+		}
+	}
+	return ""
+}
+
+// ReadManifest: YAML veya JSON manifesti okur ve doğrular.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// YAML'ı JSON'a çevirerek tek yapıya çöz (Sayılar ve iç içe şemalar JSON'daki gibi olur)
+	if !strings.HasSuffix(strings.ToLower(path), ".json") {
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("geçersiz YAML: %v", err)
+		}
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("manifest JSON'a çevrilemedi: %v", err)
+		}
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("geçersiz manifest: %v", err)
+	}
+	if m.Name == "" {
+		m.Name = manifestStem(filepath.Base(path))
+	}
+	switch {
+	case !validToolName(m.Name):
+		return nil, fmt.Errorf("geçersiz araç adı: '%s' (Harf, rakam, _ ve -)", m.Name)
+	case strings.TrimSpace(m.Command) == "":
+		return nil, fmt.Errorf("'command' alanı zorunlu")
+	case m.TimeoutSeconds < 0:
+		return nil, fmt.Errorf("'timeout_seconds' negatif olamaz")
+	}
+	if m.Description == "" {
+		m.Description = "Manifestle yüklenen araç."
+	}
+	return &m, nil
+}
+
+func validToolName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// ManifestTool: Manifestle tanımlanmış, herhangi bir dilde yazılmış araç
+type ManifestTool struct {
+	name         string
+	description  string
+	params       map[string]interface{}
+	manifestPath string
+	dir          string   // Manifestin klasörü (Araca RICK_TOOL_DIR olarak verilir)
+	program      string   // Çalıştırılacak program (Yorumlayıcı veya aracın kendisi)
+	argv         []string // program'a verilecek argümanlar (Script yolu + sabit argümanlar)
+	timeout      time.Duration
+	capabilities []string
+	env          []string
+	sandbox      *sandbox.Limits
+}
+
+// NewManifestTool: Manifestteki yolları çözer; yorumlayıcı veya çalıştırılabilir bulunamazsa hata döner.
+// pythonPath doluysa "python"/"python3" yorumlayıcısı yerine venv kullanılır.
+func NewManifestTool(m *Manifest, manifestPath, pythonPath string) (*ManifestTool, error) {
+	dir, err := filepath.Abs(filepath.Dir(manifestPath))
+	if err != nil {
+		return nil, err
+	}
+	command := m.Command
+	if !filepath.IsAbs(command) {
+		if candidate := filepath.Join(dir, command); fileExists(candidate) {
+			command = candidate
+		}
+	}
+
+	t := &ManifestTool{
+		name:         m.Name,
+		description:  m.Description,
+		manifestPath: manifestPath,
+		dir:          dir,
+		timeout:      time.Duration(m.TimeoutSeconds) * time.Second,
+		capabilities: manifestCapabilities(m.Capabilities),
+		env:          m.Env,
+	}
+	if len(m.Parameters) > 0 {
+		t.params = CleanSchema(m.Parameters)
+	}
+
+	switch interpreter := strings.TrimSpace(m.Interpreter); {
+	case interpreter == "":
+		if filepath.IsAbs(command) {
+			if !fileExists(command) {
+				return nil, fmt.Errorf("çalıştırılabilir bulunamadı: %s", command)
+			}
+			if info, err := os.Stat(command); err == nil && runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+				return nil, fmt.Errorf("%s çalıştırılabilir değil (chmod +x veya 'interpreter' belirt)", command)
+			}
+		} else if _, err := exec.LookPath(command); err != nil {
+			return nil, fmt.Errorf("çalıştırılabilir bulunamadı: %s", command)
+		}
+		t.program = command
+	default:
+		if (interpreter == "python" || interpreter == "python3") && pythonPath != "" {
+			interpreter = pythonPath
+		}
+		if strings.ContainsRune(interpreter, filepath.Separator) {
+			if abs, err := filepath.Abs(interpreter); err == nil {
+				interpreter = abs
+			}
+		} else if _, err := exec.LookPath(interpreter); err != nil {
+			return nil, fmt.Errorf("yorumlayıcı bulunamadı: %s", interpreter)
+		}
+		if !filepath.IsAbs(command) {
+			if !fileExists(command) {
+				return nil, fmt.Errorf("script bulunamadı: %s", m.Command)
+			}
+			command, _ = filepath.Abs(command) // Sandbox kendi geçici klasöründe çalışır
+		}
+		t.program = interpreter
+		t.argv = []string{command}
+	}
+	t.argv = append(t.argv, m.Args...)
+	return t, nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// WithSandbox: Aracın sandbox sınırlarını ayarlar (nil ise doğrudan çalışır).
+func (t *ManifestTool) WithSandbox(limits *sandbox.Limits) *ManifestTool {
+	t.sandbox = limits
+	return t
+}
+
+func (t *ManifestTool) Name() string        { return t.name }
+func (t *ManifestTool) Description() string { return t.description }
+
+func (t *ManifestTool) Parameters() map[string]interface{} {
+	if t.params != nil {
+		return t.params
+	}
+	return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+}
+
+// Timeout: Manifestte bildirilen zaman aşımı (0 = araç zincirinin varsayılanı)
+func (t *ManifestTool) Timeout() time.Duration { return t.timeout }
+
+// Capabilities: Aracın yetkileri; bildirilenler ve her zaman exec (Güvenlik politikası bunlara göre açar)
+func (t *ManifestTool) Capabilities() []string { return t.capabilities }
+
+func (t *ManifestTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Argümanları stdin'den JSON olarak verir, stdout'taki JSON sonucu ToolResult'a çevirir.
+// Sıfırdan farklı çıkış kodu ve sandbox ihlalleri 'failed' döner; hata sadece süreç başlatılamazsa döner.
+func (t *ManifestTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	if err := ValidateArgs(t.params, args); err != nil {
+		return nil, fmt.Errorf("HATA: '%s' aracının parametreleri geçersiz: %v", t.name, err)
+	}
+	input, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("argüman paketleme hatası: %v", err)
+	}
+	env := []string{"RICK_TOOL_NAME=" + t.name, "RICK_TOOL_DIR=" + t.dir}
+
+	var stdout, stderr string
+	var exitCode int
+	var violation *sandbox.Violation
+	started := time.Now()
+	if t.sandbox != nil {
+		limits := *t.sandbox
+		if t.timeout > 0 {
+			limits.Timeout = t.timeout
+		}
+		limits.EnvAllow = append(append([]string(nil), limits.EnvAllow...), t.env...)
+		if hasCapability(t.capabilities, policy.ClassNetwork) {
+			limits.IsolateNetwork = false
+		}
+		res, err := sandbox.RunInput(ctx, limits, sandbox.Input{Stdin: input, Env: env, SeparateStderr: true}, t.program, t.argv...)
+		if err != nil {
+			if res == nil {
+				return nil, fmt.Errorf("HATA: '%s' sandbox içinde çalıştırılamadı: %v", t.name, err)
+			}
+			return nil, err
+		}
+		stdout, stderr, exitCode, violation = res.Output, res.Stderr, res.ExitCode, res.Violation
+	} else {
+		runCtx := ctx
+		if t.timeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(ctx, t.timeout)
+			defer cancel()
+		}
+		cmd := exec.CommandContext(runCtx, t.program, t.argv...)
+		cmd.Env = append(sandbox.AllowedEnv(append(append([]string(nil), hostEnv...), t.env...)...), env...)
+		cmd.Stdin = bytes.NewReader(input)
+		var outBuf, errBuf bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &outBuf, &errBuf
+		cmd.WaitDelay = 2 * time.Second
+		runErr := cmd.Run()
+		var exitErr *exec.ExitError
+		if runErr != nil && !errors.As(runErr, &exitErr) && runCtx.Err() == nil {
+			return nil, fmt.Errorf("HATA: '%s' başlatılamadı: %v", t.name, runErr)
+		}
+		stdout, stderr, exitCode = outBuf.String(), errBuf.String(), kernel.ExitCode(runErr)
+		if ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded {
+			violation = &sandbox.Violation{Kind: sandbox.ViolationTimeout, Limit: t.timeout.String(), Detail: "süre doldu, süreç durduruldu"}
+		}
+	}
+	if strings.TrimSpace(stderr) != "" {
+		logger.Debug("🧩 [%s] stderr: %s", t.name, strings.TrimSpace(stderr))
+	}
+
+	result := t.parseOutput(stdout, stderr, exitCode, violation)
+	if result.Metrics == nil {
+		result.Metrics = make(map[string]float64)
+	}
+	result.Metrics["duration_ms"] = float64(time.Since(started).Milliseconds())
+	return result, nil
+}
+
+// manifestOutput: Aracın stdout'a yazdığı sonuç
+type manifestOutput struct {
+	Status    string                 `json:"status"` // success | failed (Boşsa error alanına göre)
+	Text      string                 `json:"text"`
+	Error     string                 `json:"error"`
+	Data      map[string]interface{} `json:"data"`
+	Artifacts []kernel.Artifact      `json:"artifacts"`
+}
+
+func (t *ManifestTool) parseOutput(stdout, stderr string, exitCode int, violation *sandbox.Violation) *kernel.ToolResult {
+	data := map[string]interface{}{"exit_code": exitCode, "manifest": t.manifestPath}
+
+	var out manifestOutput
+	trimmed := strings.TrimSpace(stdout)
+	structured := strings.HasPrefix(trimmed, "{") && json.Unmarshal([]byte(trimmed), &out) == nil
+	if !structured {
+		out = manifestOutput{Text: trimmed}
+	}
+	for k, v := range out.Data {
+		data[k] = v
+	}
+
+	if violation != nil {
+		data["violation"] = violation.Kind
+		data["limit"] = violation.Limit
+		label := "⛔ SANDBOX İHLALİ"
+		if t.sandbox == nil {
+			label = "⏰ ZAMAN AŞIMI"
+		}
+		return kernel.Failed(fmt.Sprintf("%s (%s): %s [%s, sınır: %s]%s", label, t.name, violation.Detail, violation.Kind, violation.Limit, stderrNote(stderr)), data)
+	}
+	if exitCode != 0 || out.Error != "" || (out.Status != "" && out.Status != kernel.ToolStatusSuccess) {
+		msg := out.Error
+		if msg == "" {
+			msg = out.Text
+		}
+		if msg == "" {
+			msg = fmt.Sprintf("çıkış kodu %d", exitCode)
+		}
+		result := kernel.Failed(fmt.Sprintf("❌ Araç Hatası (%s): %s%s", t.name, msg, stderrNote(stderr)), data)
+		result.Artifacts = out.Artifacts
+		return result
+	}
+	result := kernel.Success(out.Text, data)
+	result.Artifacts = out.Artifacts
+	return result
+}
+
+func stderrNote(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if stderr == "" {
+		return ""
+	}
+	if len(stderr) > maxManifestStderr {
+		stderr = "..." + stderr[len(stderr)-maxManifestStderr:]
+	}
+	return "\nstderr:\n" + stderr
+}
+
+// manifestCapabilities: Manifest aracı keyfi bir program çalıştırır; bildirilen yetkiler sınıfı yükseltebilir
+// ama exec'in altına indiremez (Örn: 'capabilities: [read]' restricted seviyede açılmasın).
+func manifestCapabilities(declared []string) []string {
+	caps := append([]string(nil), declared...)
+	if !hasCapability(caps, policy.ClassExec) {
+		caps = append(caps, policy.ClassExec)
+	}
+	return caps
+}
+
+func hasCapability(caps []string, c string) bool {
+	for _, have := range caps {
+		if have == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+)
+
+func TestManifestCapabilitiesNeverBelowExec(t *testing.T) {
+	tests := []struct {
+		declared []string
+		level    string
+		allow    bool
+	}{
+		{nil, policy.LevelStandard, true},
+		{[]string{"read"}, policy.LevelStandard, true},
+		{[]string{"read"}, policy.LevelRestricted, false},
+		{[]string{"memory"}, policy.LevelRestricted, false},
+		{[]string{"code"}, policy.LevelStandard, false},
+	}
+	for _, tt := range tests {
+		name := "manifest_test_tool"
+		if err := policy.Declare(name, manifestCapabilities(tt.declared)); err != nil {
+			t.Fatal(err)
+		}
+		p, err := policy.New(policy.Options{Level: tt.level})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Check(name, nil); (err == nil) != tt.allow {
+			t.Errorf("%v @ %s: izin = %v bekleniyordu, hata: %v", tt.declared, tt.level, tt.allow, err)
+		}
+		policy.Declare(name, nil)
+	}
+}
@@ -0,0 +1,284 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// ClientName: initialize'da sunucuya tanıtılan istemci adı
+const ClientName = "rick-agent"
+
+// ClientVersion: initialize'da sunucuya tanıtılan istemci sürümü
+var ClientVersion = "3"
+
+// Client: Tek bir MCP sunucusuyla kurulmuş oturum.
+type Client struct {
+	server string
+	tr     transport
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan *message
+
+	// Sunucunun initialize cevabı
+	ProtocolVersion string
+	ServerName      string
+	ServerVersion   string
+	Instructions    string
+	ListChanged     bool // Sunucu tools/list_changed bildirimi gönderecek
+
+	// OnToolsChanged: notifications/tools/list_changed geldiğinde (ayrı goroutine'de) çağrılır
+	OnToolsChanged func()
+}
+
+func newClient(server string, tr transport) *Client {
+	return &Client{server: server, tr: tr, pending: make(map[string]chan *message)}
+}
+
+// connect: Taşıyıcıyı başlatır ve initialize el sıkışmasını tamamlar.
+func (c *Client) connect(ctx context.Context) error {
+	if err := c.tr.start(ctx, c.handle); err != nil {
+		return err
+	}
+	go func() {
+		<-c.tr.done()
+		c.failPending()
+	}()
+
+	var res initializeResult
+	err := c.call(ctx, "initialize", initializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]interface{}{},
+		ClientInfo:      implementation{Name: ClientName, Version: ClientVersion},
+	}, &res)
+	if err != nil {
+		return fmt.Errorf("initialize başarısız: %v", err)
+	}
+	if !versionSupported(res.ProtocolVersion) {
+		return fmt.Errorf("sunucunun protokol sürümü desteklenmiyor: %q", res.ProtocolVersion)
+	}
+	if res.Capabilities.Tools == nil {
+		logger.Warn("⚠️ [mcp:%s] Sunucu 'tools' yeteneği bildirmedi; yine de araç listesi istenecek.", c.server)
+	} else {
+		c.ListChanged = res.Capabilities.Tools.ListChanged
+	}
+	c.ProtocolVersion = res.ProtocolVersion
+	c.ServerName = res.ServerInfo.Name
+	c.ServerVersion = res.ServerInfo.Version
+	c.Instructions = res.Instructions
+
+	if h, ok := c.tr.(*httpTransport); ok {
+		h.setVersion(res.ProtocolVersion)
+	}
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		return fmt.Errorf("initialized bildirimi gönderilemedi: %v", err)
+	}
+	if h, ok := c.tr.(*httpTransport); ok {
+		h.listen()
+	}
+	return nil
+}
+
+// listTools: Sayfalı tools/list ile sunucunun tüm araçlarını çeker.
+func (c *Client) listTools(ctx context.Context) ([]remoteTool, error) {
+	var all []remoteTool
+	cursor := ""
+	for page := 0; page < 100; page++ {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		var res listToolsResult
+		if err := c.call(ctx, "tools/list", params, &res); err != nil {
+			return nil, err
+		}
+		all = append(all, res.Tools...)
+		if res.NextCursor == "" {
+			return all, nil
+		}
+		cursor = res.NextCursor
+	}
+	return all, fmt.Errorf("tools/list sayfalaması bitmedi (100 sayfa)")
+}
+
+// callTool: Uzak aracı çalıştırır. Araç kaynaklı hatalar (isError) cevabın içindedir, error değildir.
+func (c *Client) callTool(ctx context.Context, name string, args map[string]interface{}) (*callToolResult, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	var res callToolResult
+	if err := c.call(ctx, "tools/call", callToolParams{Name: name, Arguments: args}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// alive: Bağlantı hâlâ açık mı?
+func (c *Client) alive() bool {
+	select {
+	case <-c.tr.done():
+		return false
+	default:
+		return true
+	}
+}
+
+func (c *Client) close() error {
+	return c.tr.close()
+}
+
+// call: İstek gönderir ve cevabı bekler. Bağlam iptal edilirse sunucuya notifications/cancelled gider.
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	c.mu.Lock()
+	c.nextID++
+	id := strconv.FormatInt(c.nextID, 10)
+	ch := make(chan *message, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	req := message{JSONRPC: "2.0", ID: json.RawMessage(id), Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = raw
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if err := c.tr.send(ctx, data); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp == nil {
+			return c.tr.err()
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("%s cevabı çözülemedi: %v", method, err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		// Sunucu işi bıraksın (En iyi çaba)
+		cancelCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		c.notify(cancelCtx, "notifications/cancelled", map[string]interface{}{"requestId": json.RawMessage(id), "reason": ctx.Err().Error()})
+		cancel()
+		return ctx.Err()
+	}
+}
+
+func (c *Client) notify(ctx context.Context, method string, params interface{}) error {
+	msg := message{JSONRPC: "2.0", Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		msg.Params = raw
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.tr.send(ctx, data)
+}
+
+// handle: Taşıyıcıdan gelen ham mesajları yönlendirir.
+func (c *Client) handle(data []byte) {
+	msgs, err := decodeMessages(data)
+	if err != nil {
+		logger.Debug("🔌 [mcp:%s] Çözülemeyen mesaj atlandı: %v", c.server, err)
+		return
+	}
+	for _, msg := range msgs {
+		switch {
+		case msg.isResponse():
+			c.mu.Lock()
+			// Bazı sunucular kimliği metin olarak geri yollar
+			ch, ok := c.pending[strings.Trim(string(msg.ID), `"`)]
+			c.mu.Unlock()
+			if ok {
+				select {
+				case ch <- msg:
+				default: // Aynı kimliğe ikinci cevap
+				}
+			}
+		case msg.isRequest():
+			go c.answer(msg)
+		case msg.isNotification():
+			c.onNotification(msg)
+		}
+	}
+}
+
+// answer: Sunucudan gelen isteklere cevap (Sadece ping destekleniyor; sampling/roots yetenekleri bildirilmedi).
+func (c *Client) answer(req *message) {
+	resp := message{JSONRPC: "2.0", ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &rpcError{Code: codeMethodNotFound, Message: "desteklenmeyen metot: " + req.Method}
+	}
+	data, _ := json.Marshal(resp)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c.tr.send(ctx, data)
+}
+
+func (c *Client) onNotification(msg *message) {
+	switch msg.Method {
+	case "notifications/tools/list_changed":
+		if c.OnToolsChanged != nil {
+			go c.OnToolsChanged()
+		}
+	case "notifications/message":
+		var p struct {
+			Level string      `json:"level"`
+			Data  interface{} `json:"data"`
+		}
+		json.Unmarshal(msg.Params, &p)
+		logger.Debug("🔌 [mcp:%s] %s: %v", c.server, p.Level, p.Data)
+	}
+}
+
+// failPending: Bağlantı koptuğunda bekleyen tüm istekleri uyandırır.
+func (c *Client) failPending() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, ch := range c.pending {
+		select {
+		case ch <- nil:
+		default:
+		}
+		delete(c.pending, id)
+	}
+}
+
+func versionSupported(v string) bool {
+	for _, s := range supportedVersions {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
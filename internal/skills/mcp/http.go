@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// httpTransport: Streamable HTTP. Her mesaj tek uç noktaya POST edilir; sunucu cevabı düz JSON veya
+// SSE akışı olarak döner. Sunucunun kendiliğinden gönderdiği bildirimler için ayrıca GET ile SSE açılır.
+type httpTransport struct {
+	server  string
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+	version   string // initialize sonrası MCP-Protocol-Version başlığı
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	deliver func([]byte)
+
+	doneCh   chan struct{}
+	doneOnce sync.Once
+	failErr  error
+}
+
+func newHTTPTransport(server, url string, headers map[string]string) *httpTransport {
+	return &httpTransport{
+		server:  server,
+		url:     url,
+		headers: headers,
+		client:  &http.Client{},
+		doneCh:  make(chan struct{}),
+	}
+}
+
+func (t *httpTransport) start(ctx context.Context, deliver func([]byte)) error {
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	t.deliver = deliver
+	return nil
+}
+
+func (t *httpTransport) send(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		resp.Body.Close()
+		return nil
+	case resp.StatusCode == http.StatusNotFound && t.session() != "":
+		// Oturum sunucuda düşmüş: Yeniden bağlanıp initialize etmek gerekir
+		resp.Body.Close()
+		err := fmt.Errorf("oturum sona erdi (HTTP 404)")
+		t.fail(err)
+		return err
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		resp.Body.Close()
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		// Cevap akış içinde gelecek; akışı arka planda oku (İstek, cevabı pending üzerinden bekler)
+		go func() {
+			defer resp.Body.Close()
+			t.readEvents(resp.Body)
+		}()
+		return nil
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		t.deliver(body)
+	}
+	return nil
+}
+
+// listen: Sunucudan istemciye bildirim akışını (GET + SSE) açar; desteklenmiyorsa (405) sessizce vazgeçer.
+func (t *httpTransport) listen() {
+	go func() {
+		for {
+			req, err := http.NewRequestWithContext(t.ctx, http.MethodGet, t.url, nil)
+			if err != nil {
+				return
+			}
+			req.Header.Set("Accept", "text/event-stream")
+			t.setHeaders(req)
+			resp, err := t.client.Do(req)
+			if err != nil {
+				if t.ctx.Err() != nil {
+					return
+				}
+				logger.Debug("🔌 [mcp:%s] Bildirim akışı açılamadı: %v", t.server, err)
+			} else {
+				mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+				if resp.StatusCode != http.StatusOK || mediaType != "text/event-stream" {
+					resp.Body.Close()
+					if resp.StatusCode == http.StatusNotFound && t.session() != "" {
+						t.fail(fmt.Errorf("oturum sona erdi (HTTP 404)"))
+					}
+					return
+				}
+				t.readEvents(resp.Body)
+				resp.Body.Close()
+			}
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}()
+}
+
+// readEvents: SSE akışındaki her olayın 'data' alanını mesaj olarak iletir.
+func (t *httpTransport) readEvents(body io.Reader) {
+	reader := bufio.NewReaderSize(body, 64*1024)
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				t.deliver([]byte(data.String()))
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		if err != nil {
+			if data.Len() > 0 {
+				t.deliver([]byte(data.String()))
+			}
+			return
+		}
+	}
+}
+
+func (t *httpTransport) setHeaders(req *http.Request) {
+	for k, v := range t.headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	if t.version != "" {
+		req.Header.Set("MCP-Protocol-Version", t.version)
+	}
+}
+
+func (t *httpTransport) setVersion(version string) {
+	t.mu.Lock()
+	t.version = version
+	t.mu.Unlock()
+}
+
+func (t *httpTransport) session() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+func (t *httpTransport) fail(err error) {
+	t.doneOnce.Do(func() {
+		t.failErr = err
+		close(t.doneCh)
+	})
+}
+
+func (t *httpTransport) done() <-chan struct{} { return t.doneCh }
+
+func (t *httpTransport) err() error {
+	select {
+	case <-t.doneCh:
+		return t.failErr
+	default:
+		return nil
+	}
+}
+
+// close: Oturumu sunucuda da kapatır (DELETE, en iyi çaba).
+func (t *httpTransport) close() error {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	if id := t.session(); id != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil); err == nil {
+			t.setHeaders(req)
+			if resp, err := t.client.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}
+	}
+	t.fail(fmt.Errorf("bağlantı kapatıldı"))
+	return nil
+}
@@ -0,0 +1,281 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// Varsayılanlar
+const (
+	defaultCallTimeout    = 60 * time.Second
+	defaultConnectTimeout = 30 * time.Second
+	maxRestartBackoff     = 60 * time.Second
+	stableSession         = time.Minute // Bu kadar ayakta kalan oturumdan sonra bekleme süresi sıfırlanır
+)
+
+// ServerConfig: Bağlanılacak bir MCP sunucusu. Command (stdio) veya URL (streamable HTTP) verilmelidir.
+type ServerConfig struct {
+	Name    string
+	Command string
+	Args    []string
+	Env     map[string]string // Süreç ortamına eklenir ($VAR açılır)
+	Dir     string            // Sürecin çalışma klasörü
+	URL     string
+	Headers map[string]string // HTTP başlıkları ($VAR açılır)
+	Prefix  string            // Araç adı öneki (Boşsa sunucu adı)
+	Tools   []string          // Sadece bu uzak araçları al (Boşsa hepsi)
+	Timeout time.Duration     // Araç çağrısı zaman aşımı (0 = 60 sn)
+}
+
+// Registry: Uzak araçların kaydedildiği yer (skills.Manager)
+type Registry interface {
+	Register(t kernel.Tool)
+	Unregister(name string) bool
+	GetTool(name string) (kernel.Tool, error)
+}
+
+// Hub: Yapılandırılmış MCP sunucularını başlatır, araçlarını kayıt defterine işler ve düşen sunucuları yeniden başlatır.
+type Hub struct {
+	registry Registry
+	servers  []*upstream
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHub: Yapılandırmayı doğrular; bağlantılar Start ile kurulur.
+func NewHub(registry Registry, configs []ServerConfig) (*Hub, error) {
+	h := &Hub{registry: registry}
+	seen := make(map[string]bool)
+	for _, cfg := range configs {
+		name := strings.TrimSpace(cfg.Name)
+		if name == "" {
+			return nil, fmt.Errorf("MCP sunucusunun 'name' alanı boş")
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("MCP sunucu adı tekrar ediyor: %s", name)
+		}
+		seen[name] = true
+		if (cfg.Command == "") == (cfg.URL == "") {
+			return nil, fmt.Errorf("MCP sunucusu '%s': 'command' veya 'url' alanlarından yalnızca biri verilmeli", name)
+		}
+
+		srv := &upstream{hub: h, cfg: cfg, name: name, prefix: cfg.Prefix, timeout: cfg.Timeout, tools: make(map[string]*RemoteTool)}
+		if srv.prefix == "" {
+			srv.prefix = name
+		}
+		if srv.timeout <= 0 {
+			srv.timeout = defaultCallTimeout
+		}
+		if len(cfg.Tools) > 0 {
+			srv.allow = make(map[string]bool)
+			for _, t := range cfg.Tools {
+				srv.allow[t] = true
+			}
+		}
+		h.servers = append(h.servers, srv)
+	}
+	return h, nil
+}
+
+// Start: Her sunucu için arka planda bağlan-izle-yeniden başlat döngüsünü açar.
+func (h *Hub) Start(ctx context.Context) {
+	ctx, h.cancel = context.WithCancel(ctx)
+	for _, srv := range h.servers {
+		h.wg.Add(1)
+		go func(s *upstream) {
+			defer h.wg.Done()
+			s.run(ctx)
+		}(srv)
+	}
+}
+
+// Close: Tüm oturumları kapatır ve uzak araçları kayıt defterinden çıkarır.
+func (h *Hub) Close() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	h.wg.Wait()
+	for _, srv := range h.servers {
+		srv.unregisterAll()
+	}
+}
+
+// upstream: Bağlanılan tek sunucunun canlı durumu
+type upstream struct {
+	hub     *Hub
+	cfg     ServerConfig
+	name    string
+	prefix  string
+	timeout time.Duration
+	allow   map[string]bool
+
+	mu     sync.Mutex
+	client *Client
+	tools  map[string]*RemoteTool // Rick adı -> araç
+
+	syncMu sync.Mutex // Aynı anda tek tools/list senkronu
+}
+
+// current: Bağlı oturum (Yoksa veya kopmuşsa nil)
+func (s *upstream) current() *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil || !s.client.alive() {
+		return nil
+	}
+	return s.client
+}
+
+func (s *upstream) setClient(c *Client) {
+	s.mu.Lock()
+	s.client = c
+	s.mu.Unlock()
+}
+
+// run: Oturum kapandıkça üstel beklemeyle yeniden bağlanır; bağlam bitince döner.
+func (s *upstream) run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := s.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > stableSession {
+			backoff = time.Second
+		}
+		logger.Warn("⚠️ [mcp:%s] Bağlantı koptu: %v (%v sonra yeniden denenecek)", s.name, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRestartBackoff)
+	}
+}
+
+// session: Bağlanır, araçları senkronlar ve bağlantı kopana kadar bekler.
+func (s *upstream) session(ctx context.Context) error {
+	var tr transport
+	if s.cfg.URL != "" {
+		tr = newHTTPTransport(s.name, s.cfg.URL, s.cfg.Headers)
+	} else {
+		tr = newStdioTransport(s.name, s.cfg.Command, s.cfg.Args, s.cfg.Env, s.cfg.Dir)
+	}
+	client := newClient(s.name, tr)
+	client.OnToolsChanged = func() {
+		if err := s.sync(ctx, client); err != nil {
+			logger.Warn("⚠️ [mcp:%s] Araç listesi yenilenemedi: %v", s.name, err)
+		}
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, defaultConnectTimeout)
+	err := client.connect(connectCtx)
+	if err == nil {
+		err = s.sync(connectCtx, client)
+	}
+	cancel()
+	if err != nil {
+		client.close()
+		return err
+	}
+
+	s.setClient(client)
+	logger.Success("🔌 MCP sunucusu bağlandı: %s (%s %s, %d araç)", s.name, client.ServerName, client.ServerVersion, s.toolCount())
+
+	select {
+	case <-tr.done():
+		s.setClient(nil)
+		return tr.err()
+	case <-ctx.Done():
+		s.setClient(nil)
+		client.close()
+		return ctx.Err()
+	}
+}
+
+// sync: tools/list sonucunu kayıt defterine yansıtır (Yeni araçlar eklenir, kaybolanlar çıkarılır).
+func (s *upstream) sync(ctx context.Context, client *Client) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	listCtx, cancel := context.WithTimeout(ctx, defaultConnectTimeout)
+	defer cancel()
+	remote, err := client.listTools(listCtx)
+	if err != nil {
+		return fmt.Errorf("tools/list başarısız: %v", err)
+	}
+
+	next := make(map[string]*RemoteTool)
+	for _, rt := range remote {
+		if rt.Name == "" || (s.allow != nil && !s.allow[rt.Name]) {
+			continue
+		}
+		tool := newRemoteTool(s, rt)
+		if prev, dup := next[tool.name]; dup {
+			logger.Warn("⚠️ [mcp:%s] '%s' ve '%s' aynı ada (%s) dönüşüyor; ikincisi atlandı.", s.name, prev.remote, rt.Name, tool.name)
+			continue
+		}
+		next[tool.name] = tool
+	}
+
+	s.mu.Lock()
+	prev := s.tools
+	s.mu.Unlock()
+
+	var added, removed []string
+	for name := range prev {
+		if _, ok := next[name]; !ok {
+			s.hub.registry.Unregister(name)
+			removed = append(removed, name)
+		}
+	}
+	for name, tool := range next {
+		// Başka bir aracın (yerleşik, Python veya başka sunucu) yerine geçme
+		if existing, err := s.hub.registry.GetTool(name); err == nil {
+			if other, ok := existing.(*RemoteTool); !ok || other.srv != s {
+				logger.Warn("⚠️ [mcp:%s] '%s' adı başka bir araçta kayıtlı; uzak araç '%s' yüklenmedi.", s.name, name, tool.remote)
+				delete(next, name)
+				continue
+			}
+		}
+		s.hub.registry.Register(tool)
+		if _, ok := prev[name]; !ok {
+			added = append(added, name)
+		}
+	}
+
+	s.mu.Lock()
+	s.tools = next
+	s.mu.Unlock()
+
+	if len(prev) > 0 && (len(added) > 0 || len(removed) > 0) {
+		sort.Strings(added)
+		sort.Strings(removed)
+		logger.Info("🔄 [mcp:%s] Araç listesi değişti: +%v -%v", s.name, added, removed)
+	}
+	return nil
+}
+
+func (s *upstream) toolCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tools)
+}
+
+func (s *upstream) unregisterAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name := range s.tools {
+		s.hub.registry.Unregister(name)
+	}
+	s.tools = make(map[string]*RemoteTool)
+}
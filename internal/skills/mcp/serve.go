@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+	"github.com/aydndglr/rick-agent-v3/internal/skills/middleware"
+)
+
+// maxInlineImage: Diskteki görsellerin cevaba gömüleceği en büyük boyut
+const maxInlineImage = 5 << 20
+
+// ToolSource: Sunulacak araçlar (skills.Manager)
+type ToolSource interface {
+	ListTools() []kernel.Tool
+	GetTool(name string) (kernel.Tool, error)
+}
+
+// Server: Kayıt defterindeki araçları MCP sunucusu olarak dışarı açar. Her çağrı önce güvenlik
+// politikasından geçer; araç hataları JSON-RPC hatası değil, isError'lu araç sonucu olarak döner.
+type Server struct {
+	Tools    ToolSource
+	Policy   *policy.Policy       // nil ise tüm araçlar açık
+	Pipeline *middleware.Pipeline // Çağrıların geçtiği zincir (Panik yakalama, zaman aşımı, denetim...)
+	Name     string
+	Version  string
+
+	writeMu  sync.Mutex
+	out      io.Writer
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewServer(tools ToolSource, pol *policy.Policy) *Server {
+	return &Server{Tools: tools, Policy: pol, Pipeline: middleware.Default(middleware.Options{}), Name: ClientName, Version: ClientVersion}
+}
+
+// ServeStdio: Satır başına bir JSON mesajı okur/yazar; girdi kapanınca (EOF) süren çağrıları iptal edip döner.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	s.inflight = make(map[string]context.CancelFunc)
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		s.wg.Wait()
+	}()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReaderSize(in, 64*1024)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(strings.TrimSpace(string(line))) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			return err
+		case line := <-lines:
+			msgs, err := decodeMessages(line)
+			if err != nil {
+				s.reply(json.RawMessage("null"), nil, &rpcError{Code: codeParseError, Message: "geçersiz JSON: " + err.Error()})
+				continue
+			}
+			for _, msg := range msgs {
+				s.dispatch(ctx, msg)
+			}
+		}
+	}
+}
+
+func (s *Server) dispatch(ctx context.Context, msg *message) {
+	switch {
+	case msg.isNotification():
+		if msg.Method == "notifications/cancelled" {
+			var p struct {
+				RequestID json.RawMessage `json:"requestId"`
+			}
+			json.Unmarshal(msg.Params, &p)
+			s.mu.Lock()
+			if cancel, ok := s.inflight[string(p.RequestID)]; ok {
+				cancel()
+			}
+			s.mu.Unlock()
+		}
+	case msg.isRequest():
+		switch msg.Method {
+		case "initialize":
+			s.initialize(msg)
+		case "ping":
+			s.reply(msg.ID, struct{}{}, nil)
+		case "tools/list":
+			s.reply(msg.ID, listToolsResult{Tools: s.listTools()}, nil)
+		case "tools/call":
+			callCtx, cancel := context.WithCancel(ctx)
+			s.mu.Lock()
+			s.inflight[string(msg.ID)] = cancel
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer func() {
+					s.mu.Lock()
+					delete(s.inflight, string(msg.ID))
+					s.mu.Unlock()
+					cancel()
+				}()
+				res, rpcErr := s.callTool(callCtx, msg.Params)
+				if callCtx.Err() == context.Canceled && ctx.Err() == nil {
+					return // İstemci iptal etti: Spesifikasyona göre cevap gönderilmez
+				}
+				s.reply(msg.ID, res, rpcErr)
+			}()
+		default:
+			s.reply(msg.ID, nil, &rpcError{Code: codeMethodNotFound, Message: "desteklenmeyen metot: " + msg.Method})
+		}
+	case !msg.isResponse():
+		s.reply(json.RawMessage("null"), nil, &rpcError{Code: codeInvalidRequest, Message: "geçersiz istek"})
+	}
+}
+
+func (s *Server) initialize(msg *message) {
+	var p initializeParams
+	if err := json.Unmarshal(msg.Params, &p); err != nil {
+		s.reply(msg.ID, nil, &rpcError{Code: codeInvalidParams, Message: err.Error()})
+		return
+	}
+	version := ProtocolVersion
+	if versionSupported(p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	level := "yok"
+	if s.Policy != nil {
+		level = s.Policy.Level()
+	}
+	logger.Info("🔌 MCP istemcisi bağlandı: %s %s (protokol %s)", p.ClientInfo.Name, p.ClientInfo.Version, version)
+	s.reply(msg.ID, map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    map[string]interface{}{"tools": map[string]interface{}{"listChanged": false}},
+		"serverInfo":      implementation{Name: s.Name, Version: s.Version},
+		"instructions":    fmt.Sprintf("Rick'in yerel araçları. Güvenlik seviyesi: %s; politikanın reddettiği çağrılar isError ile döner.", level),
+	}, nil)
+}
+
+// listTools: Politikanın açtığı araçlar, kendi Parameters() şemalarıyla
+func (s *Server) listTools() []remoteTool {
+	tools := []remoteTool{}
+	for _, t := range s.Tools.ListTools() {
+		if s.Policy != nil && !s.Policy.Exposed(t.Name()) {
+			continue
+		}
+		schema := t.Parameters()
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		tools = append(tools, remoteTool{Name: t.Name(), Description: t.Description(), InputSchema: schema})
+	}
+	return tools
+}
+
+// callTool: Politika ihlalleri ve araç hataları isError'lu sonuç; bilinmeyen araç JSON-RPC hatasıdır.
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (*callToolResult, *rpcError) {
+	var p callToolParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	tool, err := s.Tools.GetTool(p.Name)
+	if err != nil || (s.Policy != nil && !s.Policy.Exposed(p.Name)) {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "bilinmeyen araç: " + p.Name}
+	}
+	if p.Arguments == nil {
+		p.Arguments = map[string]interface{}{}
+	}
+	if s.Policy != nil {
+		if err := s.Policy.Check(p.Name, p.Arguments); err != nil {
+			logger.Warn("%v (mcp)", err)
+			return errorResult(err.Error()), nil
+		}
+	}
+
+	logger.Action("🔌 [mcp-serve] %s çağrıldı", p.Name)
+	result, err := s.Pipeline.Run(kernel.WithActor(ctx, kernel.ActorMCP), tool, "", p.Arguments)
+	if err != nil {
+		text := err.Error()
+		if result != nil && strings.TrimSpace(result.Text) != "" && result.Text != text {
+			text = result.Text + "\n" + text
+		}
+		return errorResult(text), nil
+	}
+	return toCallResult(result), nil
+}
+
+// toCallResult: ToolResult'ı MCP içeriğine çevirir (Metin, görseller, dosya bağlantıları, yapısal veri).
+func toCallResult(result *kernel.ToolResult) *callToolResult {
+	res := &callToolResult{IsError: !result.OK()}
+	if result.Text != "" {
+		res.Content = append(res.Content, contentBlock{Type: "text", Text: result.Text})
+	}
+	for _, a := range result.Artifacts {
+		data := a.Data
+		if a.Kind == "image" && data == nil && a.Path != "" {
+			if info, err := os.Stat(a.Path); err == nil && info.Size() <= maxInlineImage {
+				data, _ = os.ReadFile(a.Path)
+			}
+		}
+		switch {
+		case a.Kind == "image" && data != nil:
+			mimeType := a.MimeType
+			if mimeType == "" {
+				mimeType = "image/png"
+			}
+			res.Content = append(res.Content, contentBlock{Type: "image", Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType})
+		case a.Path != "":
+			abs, err := filepath.Abs(a.Path)
+			if err != nil {
+				abs = a.Path
+			}
+			uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+			res.Content = append(res.Content, contentBlock{Type: "resource_link", URI: uri, Name: filepath.Base(a.Path), MimeType: a.MimeType})
+		}
+	}
+	if len(result.Data) > 0 {
+		if data, err := json.Marshal(result.Data); err == nil {
+			res.StructuredContent = data
+		}
+	}
+	if len(res.Content) == 0 {
+		res.Content = []contentBlock{{Type: "text", Text: "(Boş sonuç)"}}
+	}
+	return res
+}
+
+func errorResult(text string) *callToolResult {
+	return &callToolResult{Content: []contentBlock{{Type: "text", Text: text}}, IsError: true}
+}
+
+func (s *Server) reply(id json.RawMessage, result interface{}, rpcErr *rpcError) {
+	resp := message{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &rpcError{Code: codeInternalError, Message: err.Error()}
+		} else {
+			resp.Result = data
+		}
+	}
+	data, _ := json.Marshal(resp)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.out.Write(append(data, '\n'))
+}
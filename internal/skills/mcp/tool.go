@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/skills"
+)
+
+// maxToolNameLen: Sağlayıcıların (OpenAI, Gemini) kabul ettiği en uzun araç adı
+const maxToolNameLen = 64
+
+// RemoteTool: Bir MCP sunucusundaki aracı kernel.Tool olarak sunar; çağrılar o anki canlı oturuma gider.
+type RemoteTool struct {
+	srv    *upstream
+	name   string // Rick tarafındaki ad (<sunucu>__<araç>)
+	remote string // Sunucudaki ad
+	desc   string
+	schema map[string]interface{}
+}
+
+func newRemoteTool(srv *upstream, rt remoteTool) *RemoteTool {
+	desc := strings.TrimSpace(rt.Description)
+	if desc == "" {
+		desc = rt.Title
+	}
+	return &RemoteTool{
+		srv:    srv,
+		name:   toolName(srv.prefix, rt.Name),
+		remote: rt.Name,
+		desc:   fmt.Sprintf("[MCP: %s] %s", srv.name, desc),
+		schema: skills.CleanSchema(rt.InputSchema),
+	}
+}
+
+func (t *RemoteTool) Name() string                       { return t.name }
+func (t *RemoteTool) Description() string                { return t.desc }
+func (t *RemoteTool) Parameters() map[string]interface{} { return t.schema }
+
+// Server: Aracın geldiği MCP sunucusunun adı
+func (t *RemoteTool) Server() string { return t.srv.name }
+
+// RemoteName: Aracın sunucudaki özgün adı
+func (t *RemoteTool) RemoteName() string { return t.remote }
+
+func (t *RemoteTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: tools/call sonucunu ToolResult'a çevirir. Sunucunun isError bildirdiği sonuçlar 'failed' döner
+// (Model argümanları düzeltmeli); bağlantı/protokol hataları error olarak döner.
+func (t *RemoteTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	client := t.srv.current()
+	if client == nil {
+		return nil, fmt.Errorf("HATA: MCP sunucusu '%s' şu an bağlı değil (yeniden bağlanılıyor), biraz sonra tekrar dene", t.srv.name)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, t.srv.timeout)
+	defer cancel()
+
+	start := time.Now()
+	res, err := client.callTool(callCtx, t.remote, args)
+	elapsed := time.Since(start)
+	if err != nil {
+		if callCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			return nil, fmt.Errorf("HATA: MCP aracı '%s' %v içinde cevap vermedi", t.name, t.srv.timeout)
+		}
+		return nil, fmt.Errorf("HATA: MCP aracı '%s' çalıştırılamadı: %v", t.name, err)
+	}
+
+	result := convertResult(res)
+	result.Data["server"] = t.srv.name
+	result.Data["tool"] = t.remote
+	result.Metrics = map[string]float64{"duration_ms": float64(elapsed.Milliseconds())}
+	return result, nil
+}
+
+// convertResult: MCP içerik bloklarını metin, yapısal veri ve artifact'lara ayırır.
+func convertResult(res *callToolResult) *kernel.ToolResult {
+	var texts []string
+	var artifacts []kernel.Artifact
+	for _, block := range res.Content {
+		switch block.Type {
+		case "text":
+			texts = append(texts, block.Text)
+		case "image", "audio":
+			data, err := base64.StdEncoding.DecodeString(block.Data)
+			if err != nil {
+				texts = append(texts, fmt.Sprintf("[%s çözülemedi: %v]", block.Type, err))
+				continue
+			}
+			kind := "image"
+			if block.Type == "audio" {
+				kind = "file"
+			}
+			artifacts = append(artifacts, kernel.Artifact{Kind: kind, MimeType: block.MimeType, Data: data})
+			texts = append(texts, fmt.Sprintf("[%s: %s, %d bayt]", block.Type, block.MimeType, len(data)))
+		case "resource":
+			if block.Resource == nil {
+				continue
+			}
+			if block.Resource.Text != "" {
+				texts = append(texts, fmt.Sprintf("📎 %s\n%s", block.Resource.URI, block.Resource.Text))
+				continue
+			}
+			data, err := base64.StdEncoding.DecodeString(block.Resource.Blob)
+			if err != nil {
+				texts = append(texts, fmt.Sprintf("📎 %s (içerik çözülemedi: %v)", block.Resource.URI, err))
+				continue
+			}
+			artifacts = append(artifacts, kernel.Artifact{Kind: "file", MimeType: block.Resource.MimeType, Data: data})
+			texts = append(texts, fmt.Sprintf("📎 %s (%s, %d bayt)", block.Resource.URI, block.Resource.MimeType, len(data)))
+		case "resource_link":
+			texts = append(texts, fmt.Sprintf("🔗 %s: %s", block.Name, block.URI))
+		default:
+			texts = append(texts, fmt.Sprintf("[desteklenmeyen içerik türü: %s]", block.Type))
+		}
+	}
+
+	data := map[string]interface{}{}
+	if len(res.StructuredContent) > 0 && string(res.StructuredContent) != "null" {
+		var structured interface{}
+		if err := json.Unmarshal(res.StructuredContent, &structured); err == nil {
+			data["structured"] = structured
+			if len(texts) == 0 {
+				texts = append(texts, string(res.StructuredContent))
+			}
+		}
+	}
+
+	text := strings.Join(texts, "\n")
+	if text == "" {
+		text = "(Araç boş sonuç döndü)"
+	}
+	result := kernel.Success(text, data)
+	if res.IsError {
+		result = kernel.Failed("❌ "+text, data)
+	}
+	result.Artifacts = artifacts
+	return result
+}
+
+// toolName: Sağlayıcıların kabul ettiği karakterlerle (a-z, 0-9, _ , -) "<önek>__<araç>" adı üretir.
+// Sınırı aşan adlar kısaltılır ve çakışmasın diye özgün adın özetiyle biter.
+func toolName(prefix, remote string) string {
+	name := sanitizeName(remote)
+	if prefix != "" {
+		name = sanitizeName(prefix) + "__" + name
+	}
+	if len(name) <= maxToolNameLen {
+		return name
+	}
+	sum := sha256.Sum256([]byte(prefix + "/" + remote))
+	suffix := "_" + hex.EncodeToString(sum[:])[:8]
+	return name[:maxToolNameLen-len(suffix)] + suffix
+}
+
+func sanitizeName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// transport: Sunucuyla mesaj taşıyan katman (stdio veya streamable HTTP).
+// Gelen her mesaj (cevap, istek, bildirim) start'a verilen fonksiyona iletilir.
+type transport interface {
+	start(ctx context.Context, deliver func([]byte)) error
+	send(ctx context.Context, data []byte) error
+	// done: Bağlantı koptuğunda (süreç öldü, oturum düştü) kapanır
+	done() <-chan struct{}
+	// err: Kopmanın sebebi (done kapanmadan önce nil)
+	err() error
+	close() error
+}
+
+// maxStderrLine: Sunucunun stderr satırlarının loga yazılan en fazla uzunluğu
+const maxStderrLine = 2000
+
+// stdioTransport: Sunucuyu alt süreç olarak başlatır; satır başına bir JSON mesajı stdin/stdout üzerinden akar.
+type stdioTransport struct {
+	server  string
+	command string
+	args    []string
+	env     map[string]string
+	dir     string
+
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	writeMu sync.Mutex
+
+	doneCh  chan struct{}
+	exitErr error
+}
+
+func newStdioTransport(server, command string, args []string, env map[string]string, dir string) *stdioTransport {
+	return &stdioTransport{server: server, command: command, args: args, env: env, dir: dir, doneCh: make(chan struct{})}
+}
+
+func (t *stdioTransport) start(ctx context.Context, deliver func([]byte)) error {
+	cmd := exec.Command(t.command, t.args...)
+	cmd.Dir = t.dir
+	cmd.Env = os.Environ()
+	for k, v := range t.env {
+		cmd.Env = append(cmd.Env, k+"="+os.ExpandEnv(v))
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("'%s' başlatılamadı: %v", t.command, err)
+	}
+	t.cmd = cmd
+	t.stdin = stdin
+
+	// Sunucunun kendi logları (MCP'de stderr serbesttir)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if len(line) > maxStderrLine {
+				line = line[:maxStderrLine] + "..."
+			}
+			logger.Debug("🔌 [mcp:%s] %s", t.server, line)
+		}
+	}()
+
+	go func() {
+		reader := bufio.NewReaderSize(stdout, 64*1024)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(strings.TrimSpace(string(line))) > 0 {
+				deliver(line)
+			}
+			if err != nil {
+				break
+			}
+		}
+		waitErr := cmd.Wait()
+		if waitErr == nil {
+			waitErr = fmt.Errorf("süreç kapandı")
+		}
+		t.exitErr = fmt.Errorf("sunucu süreci sonlandı: %v", waitErr)
+		close(t.doneCh)
+	}()
+	return nil
+}
+
+func (t *stdioTransport) send(ctx context.Context, data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	select {
+	case <-t.doneCh:
+		return t.exitErr
+	default:
+	}
+	_, err := t.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (t *stdioTransport) done() <-chan struct{} { return t.doneCh }
+
+func (t *stdioTransport) err() error {
+	select {
+	case <-t.doneCh:
+		return t.exitErr
+	default:
+		return nil
+	}
+}
+
+// close: Spesifikasyondaki sıra: stdin'i kapat, kendiliğinden çıkmasını bekle, sonra öldür.
+func (t *stdioTransport) close() error {
+	if t.cmd == nil {
+		return nil
+	}
+	t.stdin.Close()
+	select {
+	case <-t.doneCh:
+		return nil
+	case <-time.After(2 * time.Second):
+	}
+	t.cmd.Process.Kill()
+	select {
+	case <-t.doneCh:
+	case <-time.After(2 * time.Second):
+		return fmt.Errorf("'%s' süreci kapanmadı", t.command)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/audit"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// DefaultApprovalTimeout: Cevapsız onay isteklerinin reddedildiği varsayılan süre
+const DefaultApprovalTimeout = 5 * time.Minute
+
+// Approvals: Onay isteklerini numaralandırıp bekletir; yönetici "/approve <no>" veya "/deny <no>" ile cevaplar.
+type Approvals struct {
+	Notify  func(text string) // İsteği yöneticiye iletir (Boşsa sadece loga yazılır)
+	Timeout time.Duration     // Cevapsız istek bu süre sonunda reddedilir (0 = DefaultApprovalTimeout)
+
+	mu      sync.Mutex
+	seq     int
+	pending map[string]chan bool
+}
+
+// Approve: Approver olarak kullanılır. Yöneticinin cevabını, zaman aşımını veya çağrının iptalini bekler.
+func (a *Approvals) Approve(ctx context.Context, call *Call) (bool, error) {
+	a.mu.Lock()
+	if a.pending == nil {
+		a.pending = make(map[string]chan bool)
+	}
+	a.seq++
+	id := strconv.Itoa(a.seq)
+	answer := make(chan bool, 1)
+	a.pending[id] = answer
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		delete(a.pending, id)
+		a.mu.Unlock()
+	}()
+
+	text := fmt.Sprintf("🔐 Onay bekleniyor #%s: %s %s\nOnaylamak için '/approve %s', reddetmek için '/deny %s'.",
+		id, call.Name, audit.RedactArgs(call.Args), id, id)
+	if a.Notify != nil {
+		a.Notify(text)
+	} else {
+		logger.Info("%s", text)
+	}
+
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case ok := <-answer:
+		return ok, nil
+	case <-timer.C:
+		return false, fmt.Errorf("%v içinde cevap gelmedi", timeout)
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// Resolve: Bekleyen isteği cevaplar.
+func (a *Approvals) Resolve(id string, ok bool) error {
+	a.mu.Lock()
+	answer, found := a.pending[id]
+	delete(a.pending, id)
+	a.mu.Unlock()
+	if !found {
+		return fmt.Errorf("#%s numaralı bekleyen onay isteği yok", id)
+	}
+	answer <- ok
+	return nil
+}
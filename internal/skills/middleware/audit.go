@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/audit"
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// maxAuditError: Kayda yazılan hata mesajının en fazla uzunluğu
+const maxAuditError = 1000
+
+// AuditInterceptor: Her çağrıyı (Reddedilenler ve çökenler dahil) hash zincirli denetim kaydına yazar.
+// Kayıt yazılamazsa çağrı engellenmez, sadece loglanır.
+type AuditInterceptor struct {
+	Log *audit.Log
+}
+
+func (a *AuditInterceptor) Before(ctx context.Context, call *Call) error { return nil }
+
+func (a *AuditInterceptor) After(ctx context.Context, call *Call, res *kernel.ToolResult, err error) {
+	if a.Log == nil {
+		return
+	}
+	entry := audit.Entry{
+		Session:      call.Session,
+		Conversation: call.Conversation,
+		Actor:        call.Actor,
+		CallID:       call.ID,
+		Tool:         call.Name,
+		Args:         audit.RedactArgs(call.Args),
+		Status:       audit.StatusSuccess,
+		DurationMs:   time.Since(call.Started).Milliseconds(),
+	}
+	if res != nil {
+		entry.OutputBytes = int64(len(res.Text))
+		if res.Status != "" {
+			entry.Status = res.Status
+		}
+		if !res.OK() && err == nil {
+			entry.Error = res.Text
+		}
+	}
+	if err != nil {
+		entry.Status = audit.StatusError
+		entry.Error = err.Error()
+	}
+	if call.Denied {
+		entry.Status = audit.StatusDenied
+	}
+	if entry.Error = audit.RedactText(entry.Error); len(entry.Error) > maxAuditError {
+		entry.Error = truncateText(entry.Error, maxAuditError)
+	}
+	if err := a.Log.Append(entry); err != nil {
+		logger.Error("🧾 Denetim kaydı yazılamadı: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// defaultMaxOutput: Modele giden araç metninin varsayılan üst sınırı
+const defaultMaxOutput = 64 << 10
+
+// DefaultTimeout: Araç başına bir süre verilmemişse uygulanan zaman aşımı
+const DefaultTimeout = 2 * time.Minute
+
+// defaultToolTimeouts: Doğası gereği uzun süren araçlar (Kurulum, tarama, model indirme...)
+var defaultToolTimeouts = map[string]time.Duration{
+	"dev_studio":       10 * time.Minute,
+	"edit_python_tool": 10 * time.Minute,
+	"test_tool":        10 * time.Minute,
+	"browser":          3 * time.Minute,
+	"ssh_tool":         5 * time.Minute,
+	"ingest":           30 * time.Minute,
+	"memory_admin":     30 * time.Minute,
+	"ollama_models":    30 * time.Minute,
+}
+
+// Timeouts: Zaman aşımı ayarları (0 = varsayılan, negatif = sınırsız)
+type Timeouts struct {
+	Default time.Duration
+	PerTool map[string]time.Duration // Varsayılan araç sürelerinin üzerine yazar
+}
+
+// For: Aracın zaman aşımı (0 = sınırsız)
+func (t Timeouts) For(tool string) time.Duration {
+	d, ok := t.PerTool[tool]
+	if !ok {
+		d, ok = defaultToolTimeouts[tool]
+	}
+	if !ok {
+		d = t.Default
+		if d == 0 {
+			d = DefaultTimeout
+		}
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// timeoutDeclarer: Kendi zaman aşımını bildiren araçlar (Manifest araçları)
+type timeoutDeclarer interface {
+	Timeout() time.Duration
+}
+
+// declaredTimeoutGrace: Kendi süresini bildiren araca, zaman aşımını kendisi raporlayabilsin diye verilen ek süre
+const declaredTimeoutGrace = 5 * time.Second
+
+// forCall: Config'te araç için süre yoksa aracın bildirdiği süre, o da yoksa For
+func (t Timeouts) forCall(call *Call) time.Duration {
+	if _, ok := t.PerTool[call.Name]; !ok {
+		if td, ok := call.Tool.(timeoutDeclarer); ok && td.Timeout() > 0 {
+			return td.Timeout() + declaredTimeoutGrace
+		}
+	}
+	return t.For(call.Name)
+}
+
+// panicError: Zaman aşımı goroutine'inden dış katmana taşınan panik
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+// Recover: Araçtaki panikleri yakalar ve süreci düşürmek yerine araç hatası olarak döner.
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (res *kernel.ToolResult, err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				stack := debug.Stack()
+				if p, ok := r.(*panicError); ok {
+					r, stack = p.value, p.stack
+				}
+				logger.Error("💥 '%s' aracı çöktü: %v\n%s", call.Name, r, stack)
+				res = &kernel.ToolResult{Status: kernel.ToolStatusError, Text: fmt.Sprintf("💥 Araç çöktü: %v", r)}
+				err = fmt.Errorf("HATA: '%s' aracı çöktü (%v); argümanları kontrol et", call.Name, r)
+			}()
+			return next(ctx, call)
+		}
+	}
+}
+
+// Timeout: Araca süre sınırı koyar. Bağlamı dinlemeyen araçlar da beklenmez; arka planda iptal sinyaliyle bırakılır.
+func Timeout(t Timeouts) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*kernel.ToolResult, error) {
+			limit := t.forCall(call)
+			if limit == 0 {
+				return next(ctx, call)
+			}
+			ctx, cancel := context.WithTimeout(ctx, limit)
+			defer cancel()
+
+			type outcome struct {
+				res   *kernel.ToolResult
+				err   error
+				panic *panicError
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						done <- outcome{panic: &panicError{value: r, stack: debug.Stack()}}
+					}
+				}()
+				res, err := next(ctx, call)
+				done <- outcome{res: res, err: err}
+			}()
+
+			select {
+			case o := <-done:
+				if o.panic != nil {
+					panic(o.panic) // Recover katmanı yakalar
+				}
+				return o.res, o.err
+			case <-ctx.Done():
+				if ctx.Err() == context.DeadlineExceeded {
+					logger.Warn("⏰ '%s' %v içinde bitmedi, bırakıldı.", call.Name, limit)
+					return nil, fmt.Errorf("HATA: '%s' aracı %v içinde bitmedi (zaman aşımı)", call.Name, limit)
+				}
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+// Metrics: Toplam süreyi ve çıktı boyutunu sonuca ekler (Aracın kendi ölçümleri korunur).
+func Metrics() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*kernel.ToolResult, error) {
+			start := time.Now()
+			res, err := next(ctx, call)
+			elapsed := time.Since(start)
+			if res != nil {
+				if res.Metrics == nil {
+					res.Metrics = make(map[string]float64)
+				}
+				if _, ok := res.Metrics["duration_ms"]; !ok {
+					res.Metrics["duration_ms"] = float64(elapsed.Milliseconds())
+				}
+				res.Metrics["total_ms"] = float64(elapsed.Milliseconds())
+				res.Metrics["output_bytes"] = float64(len(res.Text))
+				artifactBytes := 0
+				for _, a := range res.Artifacts {
+					artifactBytes += len(a.Data)
+				}
+				if artifactBytes > 0 {
+					res.Metrics["artifact_bytes"] = float64(artifactBytes)
+				}
+			}
+			logger.Debug("⏱️ %s: %v", call.Name, elapsed.Round(time.Millisecond))
+			return res, err
+		}
+	}
+}
+
+// Truncate: Modelin bağlamını şişirmemek için uzun metni baştan ve sondan keserek kısaltır.
+func Truncate(maxBytes int) Middleware {
+	if maxBytes == 0 {
+		maxBytes = defaultMaxOutput
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*kernel.ToolResult, error) {
+			res, err := next(ctx, call)
+			if maxBytes < 0 || res == nil || len(res.Text) <= maxBytes {
+				return res, err
+			}
+			original := len(res.Text)
+			res.Text = truncateText(res.Text, maxBytes)
+			if res.Metrics == nil {
+				res.Metrics = make(map[string]float64)
+			}
+			res.Metrics["truncated_bytes"] = float64(original - len(res.Text))
+			return res, err
+		}
+	}
+}
+
+// truncateText: Metnin ilk 3/4'ünü ve son 1/4'ünü tutar (Hatalar genelde sondadır); UTF-8 sınırlarına uyar.
+func truncateText(text string, maxBytes int) string {
+	head := maxBytes * 3 / 4
+	tail := maxBytes - head
+	for head > 0 && !utf8.RuneStart(text[head]) {
+		head--
+	}
+	start := len(text) - tail
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	var sb strings.Builder
+	sb.WriteString(text[:head])
+	sb.WriteString(fmt.Sprintf("\n\n... [✂️ %d bayt kesildi, toplam %d bayt] ...\n\n", start-head, len(text)))
+	sb.WriteString(text[start:])
+	return sb.String()
+}
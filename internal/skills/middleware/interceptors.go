@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+)
+
+// Interceptor: Çağrıdan önce ve sonra araya giren eklenti. Before hata dönerse araç çalıştırılmaz;
+// After her çağrı için (reddedilenler ve çökenler dahil) çalışır.
+type Interceptor interface {
+	Before(ctx context.Context, call *Call) error
+	After(ctx context.Context, call *Call, res *kernel.ToolResult, err error)
+}
+
+// Intercept: Kesme noktalarını sırayla çalıştırır. Kesme noktasının kendi paniği çağrıyı reddeder (Before)
+// veya yok sayılır (After); süreci düşürmez.
+func Intercept(interceptors ...Interceptor) Middleware {
+	return func(next Handler) Handler {
+		if len(interceptors) == 0 {
+			return next
+		}
+		return func(ctx context.Context, call *Call) (res *kernel.ToolResult, err error) {
+			defer func() {
+				for _, ic := range interceptors {
+					safeAfter(ctx, ic, call, res, err)
+				}
+			}()
+			for _, ic := range interceptors {
+				if err := safeBefore(ctx, ic, call); err != nil {
+					call.Denied = true
+					logger.Warn("%v", err)
+					return &kernel.ToolResult{Status: kernel.ToolStatusError, Text: err.Error()}, err
+				}
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+func safeBefore(ctx context.Context, ic Interceptor, call *Call) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("HATA: '%s' için ön denetim çöktü: %v", call.Name, r)
+		}
+	}()
+	return ic.Before(ctx, call)
+}
+
+func safeAfter(ctx context.Context, ic Interceptor, call *Call, res *kernel.ToolResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("💥 '%s' için son denetim çöktü: %v", call.Name, r)
+		}
+	}()
+	ic.After(ctx, call, res, err)
+}
+
+// PolicyInterceptor: Güvenlik politikasını uygular. Yönetici ve komut satırı çağrılarında (insan) seviyenin
+// araç kısıtı gevşer ama yol kuralları (Gizli ve korunan yollar, izinli klasörler) yine geçerlidir.
+type PolicyInterceptor struct {
+	Policy *policy.Policy
+}
+
+func (p *PolicyInterceptor) Before(ctx context.Context, call *Call) error {
+	if p.Policy == nil {
+		return nil
+	}
+	if call.Actor == kernel.ActorAdmin || call.Actor == kernel.ActorCLI {
+		return p.Policy.CheckPaths(call.Name, call.Args)
+	}
+	return p.Policy.Check(call.Name, call.Args)
+}
+
+func (p *PolicyInterceptor) After(ctx context.Context, call *Call, res *kernel.ToolResult, err error) {
+}
+
+// Approver: Onay gerektiren çağrıyı bir insana sorar (WhatsApp, terminal...). true = onaylandı.
+type Approver func(ctx context.Context, call *Call) (bool, error)
+
+// ApprovalInterceptor: Kalıplara uyan araçları çalıştırmadan önce onay ister.
+// Onaylayıcı bağlı değilse bu çağrılar reddedilir. Yönetici ve komut satırı çağrıları onay istemez.
+type ApprovalInterceptor struct {
+	Tools    []string // Onay gerektiren araç kalıpları (path.Match)
+	Approver Approver
+}
+
+func (a *ApprovalInterceptor) Before(ctx context.Context, call *Call) error {
+	if call.Actor == kernel.ActorAdmin || call.Actor == kernel.ActorCLI || !a.requires(call.Name) {
+		return nil
+	}
+	if a.Approver == nil {
+		return fmt.Errorf("🛑 '%s' yönetici onayı gerektiriyor ve bağlı bir onaylayıcı yok", call.Name)
+	}
+	ok, err := a.Approver(ctx, call)
+	if err != nil {
+		return fmt.Errorf("🛑 '%s' için onay alınamadı: %v", call.Name, err)
+	}
+	if !ok {
+		return fmt.Errorf("🛑 '%s' çağrısı yönetici tarafından reddedildi", call.Name)
+	}
+	logger.Action("✅ '%s' çağrısı onaylandı", call.Name)
+	return nil
+}
+
+func (a *ApprovalInterceptor) After(ctx context.Context, call *Call, res *kernel.ToolResult, err error) {
+}
+
+func (a *ApprovalInterceptor) requires(name string) bool {
+	for _, pattern := range a.Tools {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// LogAuditor: Her çağrının kim tarafından, hangi sonuçla ve ne kadar sürede yapıldığını loga yazar.
+type LogAuditor struct{}
+
+func (LogAuditor) Before(ctx context.Context, call *Call) error { return nil }
+
+func (LogAuditor) After(ctx context.Context, call *Call, res *kernel.ToolResult, err error) {
+	status := kernel.ToolStatusSuccess
+	if err != nil {
+		status = kernel.ToolStatusError
+	} else if res != nil && res.Status != "" {
+		status = res.Status
+	}
+	logger.Debug("🧾 [denetim] %s -> %s: %s (%v)", call.Actor, call.Name, status, time.Since(call.Started).Round(time.Millisecond))
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+// Call: Zincirden geçen tek bir araç çağrısı
+type Call struct {
+	ID           string // Modelin çağrı kimliği (Varsa)
+	Tool         kernel.Tool
+	Name         string
+	Args         map[string]interface{}
+	Actor        string // kernel.ActorFrom
+	Conversation string // kernel.ConversationFrom
+	Session      string // kernel.SessionFrom
+	Started      time.Time
+	Denied       bool // Bir kesme noktası çağrıyı reddetti (Araç çalışmadı)
+}
+
+// Handler: Çağrıyı çalıştıran fonksiyon
+type Handler func(ctx context.Context, call *Call) (*kernel.ToolResult, error)
+
+// Middleware: Handler'ı saran katman (Zaman aşımı, panik yakalama, ölçüm...)
+type Middleware func(next Handler) Handler
+
+// Pipeline: Araç çalıştırma zinciri. İlk eklenen katman en dışta çalışır.
+type Pipeline struct {
+	middlewares []Middleware
+	handler     Handler
+}
+
+// New: Verilen katmanlarla zincir kurar; en içte kernel.RunTool çalışır.
+func New(mws ...Middleware) *Pipeline {
+	p := &Pipeline{}
+	p.Use(mws...)
+	return p
+}
+
+// Use: Zincire (içe doğru) katman ekler.
+func (p *Pipeline) Use(mws ...Middleware) {
+	p.middlewares = append(p.middlewares, mws...)
+	var h Handler = func(ctx context.Context, call *Call) (*kernel.ToolResult, error) {
+		return kernel.RunTool(ctx, call.Tool, call.Args)
+	}
+	for i := len(p.middlewares) - 1; i >= 0; i-- {
+		h = p.middlewares[i](h)
+	}
+	p.handler = h
+}
+
+// Run: Aracı zincirden geçirerek çalıştırır.
+func (p *Pipeline) Run(ctx context.Context, tool kernel.Tool, callID string, args map[string]interface{}) (*kernel.ToolResult, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	call := &Call{
+		ID:           callID,
+		Tool:         tool,
+		Name:         tool.Name(),
+		Args:         args,
+		Actor:        kernel.ActorFrom(ctx),
+		Conversation: kernel.ConversationFrom(ctx),
+		Session:      kernel.SessionFrom(ctx),
+		Started:      time.Now(),
+	}
+	return p.handler(ctx, call)
+}
+
+// Options: Varsayılan zincirin ayarları
+type Options struct {
+	Timeouts       Timeouts
+	MaxOutputBytes int           // 0 = 64 KB, -1 = kapalı
+	Interceptors   []Interceptor // Politika, onay, denetim...
+}
+
+// Default: Intercept -> Recover -> Metrics -> Truncate -> Timeout -> araç
+// Kesme noktaları reddedilen ve çöken çağrıları da görür; süre ölçümü kesme noktalarını saymaz.
+func Default(opts Options) *Pipeline {
+	return New(
+		Intercept(opts.Interceptors...),
+		Recover(),
+		Metrics(),
+		Truncate(opts.MaxOutputBytes),
+		Timeout(opts.Timeouts),
+	)
+}
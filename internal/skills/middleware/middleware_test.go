@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/policy"
+)
+
+// fakeTool: Davranışı fonksiyonla verilen test aracı
+type fakeTool struct {
+	name    string
+	run     func(ctx context.Context) (string, error)
+	timeout time.Duration // > 0 ise timeoutDeclarer gibi davranır
+}
+
+func (f *fakeTool) Name() string                       { return f.name }
+func (f *fakeTool) Description() string                { return "test" }
+func (f *fakeTool) Parameters() map[string]interface{} { return nil }
+func (f *fakeTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return f.run(ctx)
+}
+
+type declaringTool struct{ *fakeTool }
+
+func (d declaringTool) Timeout() time.Duration { return d.timeout }
+
+func sleepTool(d time.Duration, honorCtx bool) *fakeTool {
+	return &fakeTool{name: "slow", run: func(ctx context.Context) (string, error) {
+		if !honorCtx {
+			time.Sleep(d)
+			return "bitti", nil
+		}
+		select {
+		case <-time.After(d):
+			return "bitti", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}}
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts Timeouts
+		tool     kernel.Tool
+		wantErr  string
+	}{
+		{"süre içinde biter", Timeouts{Default: time.Second}, sleepTool(10*time.Millisecond, true), ""},
+		{"bağlamı dinleyen araç kesilir", Timeouts{Default: 20 * time.Millisecond}, sleepTool(time.Second, true), "zaman aşımı"},
+		{"bağlamı dinlemeyen araç beklenmez", Timeouts{Default: 20 * time.Millisecond}, sleepTool(300*time.Millisecond, false), "zaman aşımı"},
+		{"negatif süre sınırsızdır", Timeouts{Default: -1}, sleepTool(30*time.Millisecond, true), ""},
+		{"araç başına süre varsayılanı ezer", Timeouts{Default: time.Second, PerTool: map[string]time.Duration{"slow": 20 * time.Millisecond}}, sleepTool(time.Second, true), "zaman aşımı"},
+		{"aracın bildirdiği süre kullanılır", Timeouts{Default: 20 * time.Millisecond}, declaringTool{&fakeTool{name: "slow", timeout: time.Second, run: sleepTool(50*time.Millisecond, true).run}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(Timeout(tt.timeouts))
+			started := time.Now()
+			_, err := p.Run(context.Background(), tt.tool, "", nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("hata beklenmiyordu: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("'%s' içeren hata bekleniyordu, alınan: %v", tt.wantErr, err)
+			}
+			if elapsed := time.Since(started); elapsed > 200*time.Millisecond {
+				t.Fatalf("zaman aşımı geç döndü: %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestTimeoutsFor(t *testing.T) {
+	tests := []struct {
+		timeouts Timeouts
+		tool     string
+		want     time.Duration
+	}{
+		{Timeouts{}, "fs_read", DefaultTimeout},
+		{Timeouts{}, "dev_studio", 10 * time.Minute},
+		{Timeouts{Default: time.Minute}, "fs_read", time.Minute},
+		{Timeouts{Default: -1}, "fs_read", 0},
+		{Timeouts{PerTool: map[string]time.Duration{"dev_studio": -1}}, "dev_studio", 0},
+	}
+	for _, tt := range tests {
+		if got := tt.timeouts.For(tt.tool); got != tt.want {
+			t.Errorf("%s: %v, beklenen %v", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	panicky := &fakeTool{name: "boom", run: func(ctx context.Context) (string, error) { panic("patladı") }}
+	tests := []struct {
+		name string
+		p    *Pipeline
+	}{
+		{"doğrudan", New(Recover())},
+		{"zaman aşımı goroutine'inden", New(Recover(), Timeout(Timeouts{Default: time.Second}))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.p.Run(context.Background(), panicky, "", nil)
+			if err == nil || !strings.Contains(err.Error(), "çöktü") {
+				t.Fatalf("çökme hatası bekleniyordu: %v", err)
+			}
+			if res == nil || res.Status != kernel.ToolStatusError || !strings.Contains(res.Text, "patladı") {
+				t.Fatalf("hata sonucu bekleniyordu: %+v", res)
+			}
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	text := strings.Repeat("ğ", 100) // 2 baytlık karakterler
+	got := truncateText(text, 51)
+	if !strings.Contains(got, "bayt kesildi") {
+		t.Fatalf("kesme notu yok: %s", got)
+	}
+	if !strings.HasPrefix(got, "ğ") || !strings.HasSuffix(got, "ğ") {
+		t.Fatalf("UTF-8 sınırı bozuldu: %q", got)
+	}
+}
+
+func TestPolicyInterceptor(t *testing.T) {
+	pol, err := policy.New(policy.Options{Level: policy.LevelRestricted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ic := &PolicyInterceptor{Policy: pol}
+	tests := []struct {
+		name  string
+		actor string
+		tool  string
+		path  string
+		allow bool
+	}{
+		{"model seviyeye takılır", kernel.ActorModel, "fs_write", "notes.txt", false},
+		{"yönetici seviyeye takılmaz", kernel.ActorAdmin, "fs_write", "notes.txt", true},
+		{"yönetici de gizli yola erişemez", kernel.ActorAdmin, "fs_read", "config/config.yaml", false},
+		{"komut satırı korunan yola yazamaz", kernel.ActorCLI, "fs_write", "go.mod", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ic.Before(context.Background(), &Call{Name: tt.tool, Actor: tt.actor, Args: map[string]interface{}{"path": tt.path}})
+			if (err == nil) != tt.allow {
+				t.Fatalf("izin = %v bekleniyordu, hata: %v", tt.allow, err)
+			}
+		})
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  *bool // nil = cevap verme
+		timeout time.Duration
+		want    bool
+		wantErr bool
+	}{
+		{"onaylandı", boolPtr(true), time.Second, true, false},
+		{"reddedildi", boolPtr(false), time.Second, false, false},
+		{"cevapsız kaldı", nil, 30 * time.Millisecond, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Approvals{Timeout: tt.timeout}
+			a.Notify = func(text string) {
+				if tt.answer == nil {
+					return
+				}
+				go func() {
+					if err := a.Resolve("1", *tt.answer); err != nil {
+						t.Errorf("Resolve: %v", err)
+					}
+				}()
+			}
+			ok, err := a.Approve(context.Background(), &Call{Name: "sys_exec"})
+			if ok != tt.want || (err != nil) != tt.wantErr {
+				t.Fatalf("(%v, %v) alındı, beklenen (%v, hata=%v)", ok, err, tt.want, tt.wantErr)
+			}
+			if err := a.Resolve("1", true); err == nil {
+				t.Fatal("biten istek tekrar cevaplanamamalı")
+			}
+		})
+	}
+}
+
+func TestApprovalInterceptor(t *testing.T) {
+	ic := &ApprovalInterceptor{Tools: []string{"sys_*"}}
+	if err := ic.Before(context.Background(), &Call{Name: "sys_exec", Actor: kernel.ActorModel}); err == nil {
+		t.Fatal("onaylayıcı yokken reddedilmeliydi")
+	}
+	if err := ic.Before(context.Background(), &Call{Name: "fs_read", Actor: kernel.ActorModel}); err != nil {
+		t.Fatalf("kalıba uymayan araç onay istememeli: %v", err)
+	}
+	ic.Approver = func(ctx context.Context, call *Call) (bool, error) { return false, nil }
+	err := ic.Before(context.Background(), &Call{Name: "sys_exec", Actor: kernel.ActorModel})
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Fatalf("reddedilen çağrı hata dönmeli: %v", err)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
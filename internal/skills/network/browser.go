@@ -3,6 +3,8 @@ package network
 import (
 	"context"
 	"fmt"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 )
 
 // BrowserTool: Rick'in internetteki TEK silahı.
@@ -56,16 +58,29 @@ func (t *BrowserTool) Parameters() map[string]interface{} {
 }
 
 func (t *BrowserTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Metin raporunun yanında ekran görüntülerini de (artifact) döner ki model sayfayı GÖRSÜN.
+func (t *BrowserTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	mode, _ := args["mode"].(string)
 
 	switch mode {
 	case "search":
-		return t.doSearch(ctx, args)
+		out, err := t.doSearch(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return kernel.Success(out, nil), nil
 	case "read":
-		return t.doRead(ctx, args)
+		out, err := t.doRead(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return kernel.Success(out, map[string]interface{}{"url": args["url"]}), nil
 	case "interact":
 		return t.doInteract(ctx, args)
 	default:
-		return "", fmt.Errorf("HATA: Geçersiz mod. Lütfen 'search', 'read' veya 'interact' kullanın")
+		return nil, fmt.Errorf("HATA: Geçersiz mod. Lütfen 'search', 'read' veya 'interact' kullanın")
 	}
 }
\ No newline at end of file
@@ -8,11 +8,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
 	"github.com/chromedp/chromedp"
 )
 
-func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{}) (string, error) {
+func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	targetUrl, _ := args["url"].(string)
 	device, _ := args["device"].(string)
 
@@ -21,7 +22,7 @@ func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{
 		if singleAction, hasSingle := args["action"].(string); hasSingle {
 			actionsRaw = []interface{}{map[string]interface{}{"action": singleAction, "selector": args["selector"], "value": args["value"]}}
 		} else {
-			return "", fmt.Errorf("HATA: 'interact' modu için 'actions' dizisi gereklidir")
+			return nil, fmt.Errorf("HATA: 'interact' modu için 'actions' dizisi gereklidir")
 		}
 	}
 
@@ -31,6 +32,7 @@ func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{
 
 	var report strings.Builder
 	report.WriteString(fmt.Sprintf("🤖 RICK ULTRA-INTERACT RAPORU\n%s\n", strings.Repeat("=", 30)))
+	var artifacts []kernel.Artifact // Ekran görüntüleri modele geri gösterilir
 
 	// ========================================================
 	// 1. EKRAN BOYUTU VE URL YÖNLENDİRME
@@ -53,7 +55,7 @@ func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{
 
 	if len(initTasks) > 0 {
 		if err := chromedp.Run(cCtx, initTasks...); err != nil {
-			return "", fmt.Errorf("URL/Viewport ayarı başarısız: %v", err)
+			return nil, fmt.Errorf("URL/Viewport ayarı başarısız: %v", err)
 		}
 	}
 
@@ -282,13 +284,13 @@ func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{
 		if err != nil {
 			errStr := fmt.Errorf("Adım %d (%s) Başarısız: %v", i+1, action, err)
 			report.WriteString(fmt.Sprintf("❌ %s\n", errStr.Error()))
-			return report.String(), errStr
+			return &kernel.ToolResult{Text: report.String(), Artifacts: artifacts}, errStr
 		}
 
 		if jsResult == "NOT_FOUND" {
 			errStr := fmt.Errorf("Adım %d (%s) Başarısız: Hedef bulunamadı", i+1, action)
 			report.WriteString(fmt.Sprintf("❌ %s\n", errStr.Error()))
-			return report.String(), errStr
+			return &kernel.ToolResult{Text: report.String(), Artifacts: artifacts}, errStr
 		}
 
 		report.WriteString(fmt.Sprintf("✅ Adım %d [%s] Tamamlandı.\n", i+1, action))
@@ -298,7 +300,8 @@ func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{
 			savePath := filepath.Join("logs", value)
 			os.MkdirAll("logs", 0755)
 			os.WriteFile(savePath, screenshotBuf, 0644)
-			report.WriteString(fmt.Sprintf("   📸 SS Kaydedildi: %s\n", savePath))
+			report.WriteString(fmt.Sprintf("   📸 SS Kaydedildi: %s (Görsel sana ekte gösteriliyor)\n", savePath))
+			artifacts = append(artifacts, kernel.Artifact{Kind: "image", Path: savePath, MimeType: "image/png", Data: screenshotBuf})
 		}
 		if resultVar != nil {
 			switch v := resultVar.(type) {
@@ -315,5 +318,5 @@ func (t *BrowserTool) doInteract(ctx context.Context, args map[string]interface{
 	report.WriteString(strings.Repeat("-", 30) + "\n")
 	report.WriteString(fmt.Sprintf("🏁 Son Durak: %s\n📜 Başlık: %s", finalURL, finalTitle))
 
-	return report.String(), nil
+	return &kernel.ToolResult{Text: report.String(), Artifacts: artifacts}, nil
 }
\ No newline at end of file
@@ -0,0 +1,441 @@
+package recall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/memory"
+)
+
+// --- TOOL 4: HAFIZA YÖNETİMİ (memory_admin) ---
+
+// DefaultExportDir: Ajanın 'export' dosyalarını yazabildiği tek klasör
+const DefaultExportDir = "exports"
+
+// AdminTool: Hafıza store'unun bakım işlerini (embedding göçü vb.) yönetir.
+type AdminTool struct {
+	Store       *memory.VectorStore
+	Consolidate memory.ConsolidateOptions // Config'teki toparlama ayarları
+	ExportDir   string                    // Doluysa 'export' sadece bu klasörün içine yazar (Boşsa yol serbest, Örn: CLI)
+}
+
+func (t *AdminTool) Name() string { return "memory_admin" }
+
+func (t *AdminTool) Description() string {
+	return "Uzun süreli hafızanın bakımını yapar. 'reembed' tüm kayıtları arka planda yeni bir embedding modeline taşır (Örn: embedding modeli değiştiğinde), 'status' süren veya son göçün ilerlemesini gösterir. " +
+		"'consolidate' benzer kayıtları özetle birleştirir ve süresi dolanları arşive taşır (dry_run ile önce rapor al), 'archived' arşivdeki kayıtları listeler, 'restore' arşivdeki kayıtları geri alır. " +
+		"İnceleme için: 'list' kayıtları filtreyle listeler, 'show' tek kaydı tüm metadata'sıyla gösterir, 'search' sonuçları puanlarıyla (vektör, kelime, birleşik) gösterir, 'delete' kayıtları kalıcı siler. " +
+		"'export' hafızayı taşınabilir JSONL dosyasına yazar (embeddings ile vektörler dahil), 'import' böyle bir dosyayı geri yükler. " +
+		"Yönetici komutu dışında inceleme eylemleri sadece bu sohbetin ve ortak hafızanın kayıtlarını görür; 'delete', 'restore' ve 'import' önce 'confirm' olmadan ön izleme yapar."
+}
+
+func (t *AdminTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action":     map[string]interface{}{"type": "string", "enum": []string{"reembed", "status", "consolidate", "archived", "restore", "list", "show", "search", "delete", "export", "import"}},
+			"model":      map[string]interface{}{"type": "string", "description": "Sadece 'reembed' için. Hedef embedding modeli (Örn: 'nomic-embed-text')."},
+			"dry_run":    map[string]interface{}{"type": "boolean", "description": "Sadece 'consolidate' için. True ise hiçbir şeyi değiştirmeden ne yapılacağını raporlar."},
+			"ids":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "'restore' için arşiv, 'delete' için hafıza kayıtlarının ID'leri."},
+			"id":         map[string]interface{}{"type": "string", "description": "Sadece 'show' için. Gösterilecek kaydın ID'si."},
+			"query":      map[string]interface{}{"type": "string", "description": "Sadece 'search' için. Arama metni."},
+			"namespace":  map[string]interface{}{"type": "string", "description": "'archived', 'list', 'search', 'export' için ad alanı filtresi (Boşsa hepsi); 'import' için tüm kayıtların yazılacağı ad alanı."},
+			"tags":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "'list' ve 'search' için. Kayıt bu etiketlerin hepsine sahip olmalı."},
+			"since":      map[string]interface{}{"type": "string", "description": "'list' ve 'search' için. Bu zamandan sonraki kayıtlar: '2026-10-01', RFC3339 veya göreli ('24h', '7d')."},
+			"until":      map[string]interface{}{"type": "string", "description": "'list' ve 'search' için. Bu zamandan önceki kayıtlar (since ile aynı biçim)."},
+			"limit":      map[string]interface{}{"type": "integer", "description": "'archived', 'list', 'search' için. En fazla kaç kayıt listelenecek (Varsayılan: 20)."},
+			"path":       map[string]interface{}{"type": "string", "description": "'export' için dışa aktarma klasörüne yazılacak dosya adı, 'import' için okunacak JSONL dosyası."},
+			"embeddings": map[string]interface{}{"type": "boolean", "description": "Sadece 'export' için. Vektörler de yazılsın (Dosya büyür ama aynı modeli kullanan hedefte yeniden embedding gerekmez)."},
+			"confirm":    map[string]interface{}{"type": "boolean", "description": "'delete', 'restore', 'import' için. True ise gerçekten uygular; False/boş ise sadece ön izleme yapar (Yönetici komutunda gerekmez)."},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *AdminTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+func (t *AdminTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	action, _ := args["action"].(string)
+
+	// Model çağrıları sohbet kapsamıyla sınırlanır ve değişiklikler memory_forget gibi onay ister
+	privileged := adminActor(ctx)
+	confirm, _ := args["confirm"].(bool)
+	confirm = confirm || privileged
+	namespaces := plainList(args["namespace"])
+	if !privileged {
+		var err error
+		if namespaces, err = readNamespaces(ctx, args); err != nil {
+			return nil, err
+		}
+	}
+
+	switch action {
+	case "reembed":
+		model, _ := args["model"].(string)
+		model = strings.TrimSpace(model)
+		if model == "" {
+			return nil, fmt.Errorf("HATA: 'reembed' işlemi için 'model' parametresi zorunludur")
+		}
+		if err := t.Store.StartReembed(ctx, model); err != nil {
+			return nil, err
+		}
+		status, _ := t.Store.ReembedStatus()
+		return kernel.Success(fmt.Sprintf("🔁 Embedding göçü arka planda başladı: %d kayıt '%s' modeline taşınacak. İlerleme için 'status' kullan.", status.Total, model),
+			map[string]interface{}{"reembed": status}), nil
+
+	case "status":
+		status, ok := t.Store.ReembedStatus()
+		if !ok {
+			return kernel.Success("📭 Bu oturumda embedding göçü başlatılmadı.", nil), nil
+		}
+		return kernel.Success(formatReembedStatus(status), map[string]interface{}{"reembed": status}), nil
+
+	case "consolidate":
+		opts := t.Consolidate
+		opts.DryRun, _ = args["dry_run"].(bool)
+		report, err := t.Store.Consolidate(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		prefix := "🧹 HAFIZA TOPARLANDI"
+		if report.DryRun {
+			prefix = "🧹 HAFIZA TOPARLAMA ÖNİZLEMESİ (Hiçbir şey değişmedi)"
+		}
+		msg := fmt.Sprintf("%s:\n🔹 Benzer küme: %d (%d kayıt birleştirildi, %d özet)\n🔹 Süre aşımıyla arşivlenen: %d\n",
+			prefix, report.Clusters, report.Merged, len(report.Summaries), report.Expired)
+		if report.Skipped > 0 {
+			msg += fmt.Sprintf("⚠️ %d küme özetlenemediği için olduğu gibi bırakıldı.\n", report.Skipped)
+		}
+		return kernel.Success(msg, map[string]interface{}{"consolidation": report}), nil
+
+	case "archived":
+		archived, err := t.archived(ctx, args, namespaces, privileged)
+		if err != nil {
+			return nil, err
+		}
+		limit := adminLimit(args)
+		if len(archived) == 0 {
+			return kernel.Success("📭 Arşivde kayıt yok.", map[string]interface{}{"count": 0}), nil
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("🗄️ ARŞİVDEKİ KAYITLAR (%d):\n", len(archived)))
+		for i, a := range archived {
+			if i == limit {
+				sb.WriteString(fmt.Sprintf("... ve %d kayıt daha\n", len(archived)-limit))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("🆔 %s | %s | 📂 %s | 🏷️ %s\n   %s\n", a.ID, a.ArchivedAt.Format("2006-01-02 15:04"), a.Namespace, a.Reason, a.Content))
+		}
+		return kernel.Success(sb.String(), map[string]interface{}{"count": len(archived)}), nil
+
+	case "restore":
+		ids := stringList(args["ids"])
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("HATA: 'restore' işlemi için 'ids' parametresi zorunludur")
+		}
+		archived, err := t.archived(ctx, args, namespaces, privileged)
+		if err != nil {
+			return nil, err
+		}
+		inScope := make(map[string]bool, len(archived))
+		for _, a := range archived {
+			inScope[a.ID] = true
+		}
+		for _, id := range ids {
+			if !inScope[id] {
+				return nil, fmt.Errorf("HATA: '%s' arşivde yok veya bu sohbetin erişebileceği bir ad alanında değil", id)
+			}
+		}
+		if !confirm {
+			return kernel.Success(fmt.Sprintf("⚠️ ONAY GEREKİYOR: %d kayıt arşivden geri alınacak (%s). Eminsen aynı parametrelerle 'confirm: true' gönder.", len(ids), strings.Join(ids, ", ")),
+				map[string]interface{}{"pending_ids": ids, "confirmed": false}), nil
+		}
+		n, err := t.Store.Restore(ctx, ids...)
+		if err != nil {
+			return nil, err
+		}
+		return kernel.Success(fmt.Sprintf("♻️ %d kayıt arşivden geri alındı.", n), map[string]interface{}{"restored": n}), nil
+
+	case "list", "search":
+		q, err := adminQuery(args, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		if action == "list" {
+			records, err := t.Store.Query(ctx, q)
+			if err != nil {
+				return nil, err
+			}
+			if len(records) == 0 {
+				return kernel.Success("📭 Filtreye uyan kayıt yok.", map[string]interface{}{"count": 0}), nil
+			}
+			text := fmt.Sprintf("🧠 HAFIZA KAYITLARI (%d, en yeni önce):\n%s", len(records), formatRecords(records))
+			return kernel.Success(text, map[string]interface{}{"count": len(records), "records": records}), nil
+		}
+
+		if q.Query == "" {
+			return nil, fmt.Errorf("HATA: 'search' işlemi için 'query' parametresi zorunludur")
+		}
+		results, err := t.Store.SearchWithOptions(ctx, q.Query, memory.SearchOptions{Limit: q.Limit, Filter: memory.QueryFilter(q)})
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return kernel.Success(fmt.Sprintf("📭 '%s' için sonuç yok.", q.Query), map[string]interface{}{"count": 0}), nil
+		}
+		return kernel.Success(formatScored(q.Query, results), map[string]interface{}{"count": len(results)}), nil
+
+	case "show":
+		id, _ := args["id"].(string)
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, fmt.Errorf("HATA: 'show' işlemi için 'id' parametresi zorunludur")
+		}
+		doc, ok := t.Store.Lookup(id)
+		if !ok || !visible(namespaces, doc.Namespace) {
+			return kernel.Failed(fmt.Sprintf("📭 '%s' ID'li kayıt yok (Arşivdeyse 'archived' ile bak).", id), map[string]interface{}{"id": id}), nil
+		}
+		return kernel.Success(formatDocument(doc), map[string]interface{}{"id": doc.ID}), nil
+
+	case "delete":
+		ids := plainList(args["ids"])
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("HATA: 'delete' işlemi için 'ids' parametresi zorunludur")
+		}
+		var targets []kernel.MemoryRecord
+		for _, id := range ids {
+			doc, ok := t.Store.Lookup(id)
+			if !ok {
+				continue
+			}
+			if !visible(namespaces, doc.Namespace) {
+				return nil, fmt.Errorf("HATA: '%s' kaydı bu sohbetin erişebileceği bir ad alanında değil (%s)", doc.ID, doc.Namespace)
+			}
+			targets = append(targets, kernel.MemoryRecord{ID: doc.ID, Namespace: doc.Namespace, Content: doc.Content, Metadata: doc.Metadata, CreatedAt: doc.CreatedAt})
+		}
+		if !confirm {
+			text := fmt.Sprintf("⚠️ ONAY GEREKİYOR: Aşağıdaki %d kayıt silinecek. Eminsen aynı parametrelerle 'confirm: true' gönder.\n%s", len(targets), formatRecords(targets))
+			return kernel.Success(text, map[string]interface{}{"pending_ids": ids, "confirmed": false}), nil
+		}
+		n, err := t.Store.Delete(ctx, ids...)
+		if err != nil {
+			return nil, err
+		}
+		logger.Action("🗑️ %d hafıza kaydı silindi (%s)", n, kernel.ActorFrom(ctx))
+		return kernel.Success(fmt.Sprintf("🗑️ %d/%d kayıt kalıcı olarak silindi.", n, len(ids)), map[string]interface{}{"deleted": n}), nil
+
+	case "export":
+		path, _ := args["path"].(string)
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("HATA: 'export' işlemi için 'path' parametresi zorunludur")
+		}
+		path, err := t.exportPath(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("HATA: dosya oluşturulamadı: %v", err)
+		}
+		embeddings, _ := args["embeddings"].(bool)
+		n, err := t.Store.Export(ctx, f, memory.ExportOptions{Namespaces: namespaces, Embeddings: embeddings})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("HATA: dışa aktarma yarıda kaldı (%d kayıt yazıldı): %v", n, err)
+		}
+		return kernel.Success(fmt.Sprintf("📤 %d kayıt dışa aktarıldı: %s", n, path), map[string]interface{}{"exported": n, "path": path}), nil
+
+	case "import":
+		path, _ := args["path"].(string)
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("HATA: 'import' işlemi için 'path' parametresi zorunludur")
+		}
+		namespace, _ := args["namespace"].(string)
+		namespace = strings.TrimSpace(namespace)
+		if !privileged {
+			// Model kayıtları sadece kendi yazabileceği ad alanına alabilir
+			var err error
+			if namespace, err = writeNamespace(ctx, args); err != nil {
+				return nil, err
+			}
+		}
+		if !confirm {
+			return kernel.Success(fmt.Sprintf("⚠️ ONAY GEREKİYOR: '%s' içindeki kayıtlar '%s' ad alanına yüklenecek. Eminsen aynı parametrelerle 'confirm: true' gönder.", path, namespace),
+				map[string]interface{}{"path": path, "namespace": namespace, "confirmed": false}), nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("HATA: dosya açılamadı: %v", err)
+		}
+		defer f.Close()
+		report, err := t.Store.Import(ctx, f, memory.ImportOptions{Namespace: namespace})
+		if err != nil {
+			return nil, err
+		}
+		return kernel.Success(FormatImportReport(path, report), map[string]interface{}{"report": report}), nil
+	}
+
+	return nil, fmt.Errorf("geçersiz eylem: '%s'", action)
+}
+
+// adminActor: Çağrı yöneticiden ("/" komutu) veya yardımcı komut satırından mı geliyor? (Kapsam ve onay sınırı yok)
+func adminActor(ctx context.Context) bool {
+	actor := kernel.ActorFrom(ctx)
+	return actor == kernel.ActorAdmin || actor == kernel.ActorCLI
+}
+
+// archived: Arşivdeki kayıtlardan okuma kapsamına girenleri döner.
+func (t *AdminTool) archived(ctx context.Context, args map[string]interface{}, namespaces []string, privileged bool) ([]memory.ArchivedDocument, error) {
+	if privileged {
+		namespace, _ := args["namespace"].(string)
+		return t.Store.Archived(ctx, strings.TrimSpace(namespace))
+	}
+	all, err := t.Store.Archived(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	var out []memory.ArchivedDocument
+	for _, a := range all {
+		if visible(namespaces, a.Namespace) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// exportPath: ExportDir doluysa yolu o klasöre göre çözer; dışına çıkan yolları reddeder.
+func (t *AdminTool) exportPath(path string) (string, error) {
+	if t.ExportDir == "" {
+		return path, nil
+	}
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("HATA: 'export' sadece '%s' klasörüne yazabilir; dosya adı ver (Örn: 'hafiza.jsonl')", t.ExportDir)
+	}
+	full := filepath.Join(t.ExportDir, path)
+	if rel, err := filepath.Rel(t.ExportDir, full); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("HATA: '%s' dışa aktarma klasörünün (%s) dışında", path, t.ExportDir)
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return "", fmt.Errorf("HATA: dışa aktarma klasörü oluşturulamadı: %v", err)
+	}
+	return full, nil
+}
+
+func formatReembedStatus(s memory.ReembedStatus) string {
+	percent := 100
+	if s.Total > 0 {
+		percent = s.Done * 100 / s.Total
+	}
+	state := "⏳ Sürüyor"
+	elapsed := time.Since(s.Started)
+	if !s.Running {
+		state = "✅ Bitti"
+		if s.Err != "" || s.Failed > 0 {
+			state = "⚠️ Eksik kaldı"
+		}
+		elapsed = s.Finished.Sub(s.Started)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔁 EMBEDDING GÖÇÜ [%s]: %s\n", s.Model, state))
+	sb.WriteString(fmt.Sprintf("🔹 İlerleme: %%%d (%d/%d), Hata: %d, Süre: %v\n", percent, s.Done, s.Total, s.Failed, elapsed.Round(time.Second)))
+	if s.Err != "" {
+		sb.WriteString(fmt.Sprintf("🔹 Not: %s\n", s.Err))
+	}
+	if !s.Running && s.Failed > 0 {
+		sb.WriteString("🔹 Başarısız kayıtlar için aynı modelle 'reembed' tekrar çalıştırılabilir.\n")
+	}
+	return sb.String()
+}
+
+// adminLimit: İnceleme eylemlerinin liste sınırı (Varsayılan: 20)
+func adminLimit(args map[string]interface{}) int {
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		return int(l)
+	}
+	return 20
+}
+
+// adminQuery: İnceleme sorgusu. Yönetici için ad alanı verilmezse tüm hafızaya, model için sohbet kapsamına bakar.
+func adminQuery(args map[string]interface{}, namespaces []string) (kernel.MemoryQuery, error) {
+	query, _ := args["query"].(string)
+	q := kernel.MemoryQuery{
+		Query:      strings.TrimSpace(query),
+		Namespaces: namespaces,
+		Tags:       stringList(args["tags"]),
+		Limit:      adminLimit(args),
+	}
+	var err error
+	since, _ := args["since"].(string)
+	if q.Since, err = ParseTimeArg(since); err != nil {
+		return q, fmt.Errorf("HATA: %v", err)
+	}
+	until, _ := args["until"].(string)
+	if q.Until, err = ParseTimeArg(until); err != nil {
+		return q, fmt.Errorf("HATA: %v", err)
+	}
+	return q, nil
+}
+
+// formatScored: Arama sonuçlarını sıralamayı belirleyen puanlarla birlikte listeler.
+func formatScored(query string, results []memory.SearchResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔎 '%s' İÇİN SONUÇLAR (%d):\n", query, len(results)))
+	for i, r := range results {
+		content := r.Doc.Content
+		if len([]rune(content)) > 300 {
+			content = string([]rune(content)[:300]) + "..."
+		}
+		sb.WriteString(fmt.Sprintf("%d. 🆔 %s | 📂 %s | %s\n", i+1, r.Doc.ID, r.Doc.Namespace, r.Doc.CreatedAt.Format("2006-01-02 15:04")))
+		sb.WriteString(fmt.Sprintf("   📊 Puan: %.4f (Vektör: %.3f, Kelime: %.3f, Önem: %.2f", r.Score, r.VectorScore, r.LexicalScore, memory.Importance(r.Doc)))
+		if memory.Pinned(r.Doc) {
+			sb.WriteString(", 📌 sabit")
+		}
+		sb.WriteString(")\n   " + content + "\n")
+	}
+	return sb.String()
+}
+
+// formatDocument: Tek kaydı tam içeriği, metadata'sı ve embedding bilgisiyle gösterir (Vektörün kendisi hariç).
+func formatDocument(doc memory.Document) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🆔 %s\n📂 Ad alanı: %s\n🕒 Oluşturulma: %s\n", doc.ID, doc.Namespace, doc.CreatedAt.Format("2006-01-02 15:04:05")))
+	sb.WriteString(fmt.Sprintf("🧬 Embedding: %s (%d boyut)\n", doc.EmbeddingModel, len(doc.Embedding)))
+	sb.WriteString(fmt.Sprintf("⚖️ Önem: %.2f", memory.Importance(doc)))
+	if memory.Pinned(doc) {
+		sb.WriteString(" | 📌 Sabitlenmiş")
+	}
+	sb.WriteString("\n")
+	if len(doc.Metadata) > 0 {
+		meta, _ := json.MarshalIndent(doc.Metadata, "", "  ")
+		sb.WriteString("🏷️ Metadata:\n" + string(meta) + "\n")
+	}
+	sb.WriteString("📝 İçerik:\n" + doc.Content + "\n")
+	return sb.String()
+}
+
+// FormatImportReport: İçe aktarma raporunu okunur metne çevirir (Araç ve CLI ortak kullanır).
+func FormatImportReport(path string, r *memory.ImportReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📥 HAFIZA İÇE AKTARILDI (%s):\n", path))
+	sb.WriteString(fmt.Sprintf("🔹 Yazılan: %d (Yeniden embed: %d), Zaten var: %d, Hata: %d\n", r.Imported, r.Reembedded, r.Skipped, len(r.Errors)))
+	for i, e := range r.Errors {
+		if i == 10 {
+			sb.WriteString(fmt.Sprintf("   ... ve %d hata daha\n", len(r.Errors)-10))
+			break
+		}
+		sb.WriteString("   ⚠️ " + e + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,87 @@
+package recall
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/memory"
+)
+
+// fakeBrain: Harf sıklığından sabit boyutlu vektör üreten test beyni
+type fakeBrain struct{}
+
+func (fakeBrain) Chat(ctx context.Context, history []kernel.Message, tools []kernel.Tool) (*kernel.BrainResponse, error) {
+	return &kernel.BrainResponse{}, nil
+}
+
+func (fakeBrain) Embed(ctx context.Context, text string) ([]float32, error) {
+	v := make([]float32, 26)
+	for _, r := range strings.ToLower(text) {
+		if r >= 'a' && r <= 'z' {
+			v[r-'a']++
+		}
+	}
+	v[0]++ // Boş metin de sıfır vektör olmasın
+	return v, nil
+}
+
+func TestAdminToolScope(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewVectorStore(filepath.Join(t.TempDir(), "memory.json"), fakeBrain{})
+	own, _ := store.Save(ctx, kernel.ConversationNamespace("a"), "alice notes", nil)
+	other, _ := store.Save(ctx, kernel.ConversationNamespace("b"), "bob secrets", nil)
+	tool := &AdminTool{Store: store}
+
+	model := kernel.WithActor(kernel.WithConversation(ctx, "a"), kernel.ActorModel)
+	admin := kernel.WithActor(kernel.WithConversation(ctx, "a"), kernel.ActorAdmin)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		args    map[string]interface{}
+		wantErr bool
+		want    string
+	}{
+		{"model başka sohbeti listelemez", model, map[string]interface{}{"action": "list"}, false, "alice notes"},
+		{"model başka sohbetin kaydını göremez", model, map[string]interface{}{"action": "show", "id": other}, false, "kayıt yok"},
+		{"model başka sohbetin kaydını silemez", model, map[string]interface{}{"action": "delete", "ids": []interface{}{other}, "confirm": true}, true, ""},
+		{"model onaysız silemez", model, map[string]interface{}{"action": "delete", "ids": []interface{}{own}}, false, "ONAY GEREKİYOR"},
+		{"model onaysız içe aktaramaz", model, map[string]interface{}{"action": "import", "path": "yok.jsonl"}, false, "ONAY GEREKİYOR"},
+		{"yönetici tüm hafızayı görür", admin, map[string]interface{}{"action": "list"}, false, "bob secrets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tool.ExecuteRich(tt.ctx, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("hata = %v, beklenen hata = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !strings.Contains(res.Text, tt.want) {
+				t.Fatalf("'%s' içeren çıktı bekleniyordu, alınan: %s", tt.want, res.Text)
+			}
+			if tt.args["action"] == "list" && tt.ctx == model && strings.Contains(res.Text, "bob secrets") {
+				t.Fatalf("başka sohbetin kaydı sızdı: %s", res.Text)
+			}
+		})
+	}
+	if _, ok := store.Lookup(own); !ok {
+		t.Fatal("onaysız silme kaydı silmemeliydi")
+	}
+
+	if _, err := tool.ExecuteRich(model, map[string]interface{}{"action": "delete", "ids": []interface{}{own}, "confirm": true}); err != nil {
+		t.Fatalf("onaylı silme: %v", err)
+	}
+	if _, ok := store.Lookup(own); ok {
+		t.Fatal("onaylı silme kaydı silmeliydi")
+	}
+	if _, err := tool.ExecuteRich(admin, map[string]interface{}{"action": "delete", "ids": []interface{}{other}}); err != nil {
+		t.Fatalf("yönetici silmesi: %v", err)
+	}
+	if _, ok := store.Lookup(other); ok {
+		t.Fatal("yönetici onaysız silebilmeli")
+	}
+}
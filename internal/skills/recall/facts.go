@@ -0,0 +1,140 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// --- TOOL 6: KESİN BİLGİLER (facts) ---
+
+// FactsTool: Kullanıcı profili ve kesin bilgiler (dil tercihi, sunucu envanteri, kalıcı kurallar) için anahtar-değer deposu.
+type FactsTool struct {
+	Facts kernel.FactStore
+}
+
+func (t *FactsTool) Name() string { return "facts" }
+
+func (t *FactsTool) Description() string {
+	return "Birebir hatırlanması gereken kesin bilgileri (anahtar-değer) yönetir: kullanıcının dil tercihi, 'prod sunucum X', kalıcı kurallar gibi. " +
+		"Bu bilgiler her görevde sistem mesajına otomatik eklenir. 'set' ekler/günceller, 'get' tek bilgiyi getirir, 'list' kaynaklarıyla (kim, ne zaman) listeler, 'delete' siler. " +
+		"Bulanık notlar ve uzun metinler için bunun yerine 'memory_save' kullan."
+}
+
+func (t *FactsTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action":    map[string]interface{}{"type": "string", "enum": []string{"set", "get", "list", "delete"}},
+			"key":       map[string]interface{}{"type": "string", "description": "Noktalı, kısa anahtar (Örn: 'user.language', 'server.prod'). 'set', 'get', 'delete' için zorunlu; 'list' için önek filtresi."},
+			"value":     map[string]interface{}{"type": "string", "description": "Sadece 'set' için. Bilginin değeri (Örn: 'Türkçe', 'db01.example.com')."},
+			"scope":     map[string]interface{}{"type": "string", "enum": []string{"shared", "conversation", "all"}, "description": "'set'/'delete' için varsayılan 'shared' (tüm sohbetler). 'get'/'list' varsayılanı bu sohbet + ortak; 'all' sadece 'list' için."},
+			"namespace": map[string]interface{}{"type": "string", "description": "Opsiyonel, scope yerine açık ad alanı (Örn: 'user:ahmet', 'project:rick')."},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *FactsTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+func (t *FactsTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	action, _ := args["action"].(string)
+	key, _ := args["key"].(string)
+	key = strings.TrimSpace(key)
+	if key == "" && (action == "set" || action == "get" || action == "delete") {
+		return nil, fmt.Errorf("HATA: '%s' işlemi için 'key' parametresi zorunludur", action)
+	}
+
+	switch action {
+	case "set":
+		value, _ := args["value"].(string)
+		scope, err := factWriteScope(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		fact, err := t.Facts.SetFact(ctx, kernel.Fact{
+			Key:          key,
+			Value:        value,
+			Scope:        scope,
+			SetBy:        kernel.ActorFrom(ctx),
+			Conversation: kernel.ConversationFrom(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Değer loglanmaz: Canlı log kancası diğer sohbetlere de akar
+		logger.Action("📌 Bilgi kaydedildi (%s, %s): %s", fact.Scope, fact.SetBy, fact.Key)
+		return kernel.Success(fmt.Sprintf("📌 Bilgi kaydedildi (%s): %s = %s", fact.Scope, fact.Key, fact.Value), map[string]interface{}{"fact": fact}), nil
+
+	case "get":
+		scopes, err := readNamespaces(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(scopes) == 0 {
+			return nil, fmt.Errorf("HATA: 'get' için 'all' kapsamı kullanılamaz, 'list' kullan")
+		}
+		// Sohbete özel bilgi ortak bilgiyi ezer (readNamespaces sırası: sohbet, ortak)
+		for _, scope := range scopes {
+			if fact, err := t.Facts.GetFact(ctx, scope, key); err == nil {
+				return kernel.Success(formatFacts([]kernel.Fact{*fact}), map[string]interface{}{"fact": fact}), nil
+			}
+		}
+		return kernel.Failed(fmt.Sprintf("📭 '%s' anahtarlı bilgi yok.", key), map[string]interface{}{"key": key}), nil
+
+	case "list":
+		scopes, err := readNamespaces(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		facts, err := t.Facts.ListFacts(ctx, scopes, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(facts) == 0 {
+			return kernel.Success("📭 Kayıtlı bilgi yok.", map[string]interface{}{"count": 0}), nil
+		}
+		return kernel.Success(formatFacts(facts), map[string]interface{}{"count": len(facts), "facts": facts}), nil
+
+	case "delete":
+		scope, err := factWriteScope(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		deleted, err := t.Facts.DeleteFact(ctx, scope, key)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			return kernel.Failed(fmt.Sprintf("📭 '%s' anahtarlı bilgi bulunamadı (%s).", key, scope), map[string]interface{}{"deleted": false}), nil
+		}
+		logger.Action("🗑️ Bilgi silindi (%s, %s): %s", scope, kernel.ActorFrom(ctx), key)
+		return kernel.Success(fmt.Sprintf("🗑️ Bilgi silindi (%s): %s", scope, key), map[string]interface{}{"deleted": true}), nil
+	}
+
+	return nil, fmt.Errorf("geçersiz eylem: '%s'", action)
+}
+
+// factWriteScope: Bilgiler (hafızanın aksine) varsayılan olarak tüm sohbetlerde geçerlidir.
+func factWriteScope(ctx context.Context, args map[string]interface{}) (string, error) {
+	ns, _ := args["namespace"].(string)
+	if scope, _ := args["scope"].(string); scope == "" && strings.TrimSpace(ns) == "" {
+		return kernel.NamespaceShared, nil
+	}
+	return writeNamespace(ctx, args)
+}
+
+// formatFacts: Bilgileri kaynaklarıyla (kim, hangi sohbette, ne zaman) listeler.
+func formatFacts(facts []kernel.Fact) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📌 KAYITLI BİLGİLER (%d):\n", len(facts)))
+	for _, f := range facts {
+		sb.WriteString(fmt.Sprintf("- %s = %s\n   📂 %s | 👤 %s (%s) | 🕒 %s\n", f.Key, f.Value, f.Scope, f.SetBy, f.Conversation, f.UpdatedAt.Format("2006-01-02 15:04")))
+	}
+	return sb.String()
+}
@@ -0,0 +1,101 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+	"github.com/aydndglr/rick-agent-v3/internal/memory"
+)
+
+// --- TOOL 5: BİLGİ TABANI (ingest) ---
+
+// IngestTool: Yerel dosya/klasörleri (runbook, README, kaynak kod) parçalayıp hafızaya aktarır.
+type IngestTool struct {
+	Store *memory.VectorStore
+}
+
+func (t *IngestTool) Name() string { return "ingest" }
+
+func (t *IngestTool) Description() string {
+	return "Yerel bir klasörü veya dosyayı (Markdown, düz metin, kaynak kod, HTML) bilgi tabanı olarak hafızaya aktarır. Metin başlık ve fonksiyon sınırlarında parçalanır; sonra 'memory_search' ile dosya:satır kaynağıyla bulunur. " +
+		"Tekrar çalıştırmak artımlıdır: değişmeyen dosyalar atlanır, değişenler güncellenir, silinenler kaldırılır."
+}
+
+func (t *IngestTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path":      map[string]interface{}{"type": "string", "description": "Aktarılacak klasör veya dosya yolu."},
+			"include":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Sadece bu glob'lara uyan dosyalar (Örn: ['**/*.md', 'docs/**']). Boşsa desteklenen tüm dosyalar."},
+			"exclude":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Atlanacak glob'lar (.git, node_modules, vendor, venv ve gizli dosyalar (.env, config.yaml, SSH anahtarları) zaten atlanır)."},
+			"scope":     map[string]interface{}{"type": "string", "enum": []string{"shared", "conversation"}, "description": "'shared' (Varsayılan): tüm sohbetlerde kullanılır. 'conversation': sadece bu sohbette."},
+			"namespace": map[string]interface{}{"type": "string", "description": "Opsiyonel, scope yerine açık ad alanı (Örn: 'project:rick')."},
+			"tags":      map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Tüm parçalara eklenecek etiketler."},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *IngestTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+func (t *IngestTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	path, _ := args["path"].(string)
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("HATA: 'path' parametresi zorunludur")
+	}
+
+	// Bilgi tabanı varsayılan olarak ortak hafızaya gider
+	namespace := kernel.NamespaceShared
+	ns, _ := args["namespace"].(string)
+	if scope, _ := args["scope"].(string); scope != "" || strings.TrimSpace(ns) != "" {
+		var err error
+		if namespace, err = writeNamespace(ctx, args); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Action("📚 Bilgi tabanı aktarılıyor: %s -> %s", path, namespace)
+	report, err := t.Store.Ingest(ctx, memory.IngestOptions{
+		Path:      path,
+		Include:   plainList(args["include"]),
+		Exclude:   plainList(args["exclude"]),
+		Namespace: namespace,
+		Tags:      stringList(args["tags"]),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	msg := FormatIngestReport(path, namespace, report)
+	data := map[string]interface{}{"ingest": report, "namespace": namespace}
+	if len(report.Errors) > 0 && report.Chunks == 0 && report.Unchanged == 0 {
+		return kernel.Failed(msg, data), nil
+	}
+	return kernel.Success(msg, data), nil
+}
+
+// FormatIngestReport: Aktarma sonucunu okunur metne çevirir (CLI da kullanır).
+func FormatIngestReport(path, namespace string, r *memory.IngestReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📚 BİLGİ TABANI AKTARILDI: %s (📂 %s)\n", path, namespace))
+	sb.WriteString(fmt.Sprintf("🔹 Dosya: %d (yeni: %d, güncellenen: %d, değişmemiş: %d, kaldırılan: %d)\n",
+		r.Files, r.Added, r.Updated, r.Unchanged, r.Removed))
+	sb.WriteString(fmt.Sprintf("🔹 Yazılan parça: %d\n", r.Chunks))
+	if len(r.Errors) > 0 {
+		sb.WriteString(fmt.Sprintf("⚠️ %d hata:\n", len(r.Errors)))
+		for i, e := range r.Errors {
+			if i == 10 {
+				sb.WriteString(fmt.Sprintf("   ... ve %d hata daha\n", len(r.Errors)-10))
+				break
+			}
+			sb.WriteString("   - " + e + "\n")
+		}
+	}
+	return sb.String()
+}
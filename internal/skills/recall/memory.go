@@ -0,0 +1,304 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// --- TOOL 1: HATIRLA (memory_save) ---
+
+type SaveTool struct {
+	Memory kernel.Memory
+}
+
+func (t *SaveTool) Name() string { return "memory_save" }
+
+func (t *SaveTool) Description() string {
+	return "Uzun süreli hafızaya kalıcı bir bilgi kaydeder (kullanıcı tercihleri, sunucu adları, görev ID'leri, çözülen hatalar vb.). Kayıt kalıcı bir ID alır; bu ID ile daha sonra bulunabilir veya silinebilir."
+}
+
+func (t *SaveTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"content":    map[string]interface{}{"type": "string", "description": "Hatırlanacak bilgi. Tek başına anlaşılır, kısa ve net bir cümle olmalı."},
+			"tags":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Etiketler (Örn: ['sunucu', 'prod'])."},
+			"source":     map[string]interface{}{"type": "string", "description": "Bilginin kaynağı (Örn: 'kullanıcı', 'ssh:db01', bir URL). Varsayılan: 'model'."},
+			"scope":      map[string]interface{}{"type": "string", "enum": []string{"conversation", "shared"}, "description": "'conversation' (Varsayılan): sadece bu sohbette hatırlanır. 'shared': tüm sohbetlerde geçerli genel bilgi."},
+			"namespace":  map[string]interface{}{"type": "string", "description": "Opsiyonel, scope yerine açık ad alanı (Örn: 'project:rick', 'user:ahmet')."},
+			"importance": map[string]interface{}{"type": "number", "description": "0-1 arası önem puanı (Varsayılan: 0.5). Önemli kayıtlar aramada öne çıkar ve süre aşımıyla arşivlenmez."},
+			"pinned":     map[string]interface{}{"type": "boolean", "description": "True ise kayıt hiç aşınmaz, birleştirilmez ve arşivlenmez (Kalıcı kurallar, kimlik bilgileri vb.)."},
+		},
+		"required": []string{"content"},
+	}
+}
+
+func (t *SaveTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+func (t *SaveTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	content, _ := args["content"].(string)
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("HATA: 'content' parametresi boş olamaz")
+	}
+
+	source, _ := args["source"].(string)
+	if strings.TrimSpace(source) == "" {
+		source = "model"
+	}
+	namespace, err := writeNamespace(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[string]interface{}{
+		"source":       source,
+		"conversation": kernel.ConversationFrom(ctx),
+	}
+	tags := stringList(args["tags"])
+	if len(tags) > 0 {
+		metadata["tags"] = tags
+	}
+	if importance, ok := args["importance"].(float64); ok {
+		if importance < 0 || importance > 1 {
+			return nil, fmt.Errorf("HATA: 'importance' 0 ile 1 arasında olmalıdır")
+		}
+		metadata["importance"] = importance
+	}
+	if pinned, _ := args["pinned"].(bool); pinned {
+		metadata["pinned"] = true
+	}
+
+	id, err := t.Memory.Save(ctx, namespace, content, metadata)
+	if err != nil {
+		return nil, err
+	}
+	// İçerik loglanmaz: Canlı log kancası diğer sohbetlere de akar
+	logger.Action("🧠 Hafızaya kaydedildi [%s] (%s)", id, namespace)
+	return kernel.Success(fmt.Sprintf("✅ Hafızaya kaydedildi (%s). 🆔 ID: %s", namespace, id), map[string]interface{}{"id": id, "namespace": namespace, "tags": tags}), nil
+}
+
+// --- TOOL 2: HATIRLA/ARA (memory_search) ---
+
+type SearchTool struct {
+	Memory kernel.Memory
+}
+
+func (t *SearchTool) Name() string { return "memory_search" }
+
+func (t *SearchTool) Description() string {
+	return "Uzun süreli hafızada arama yapar (anlamsal + birebir kelime eşleşmesi). Etiket ve zaman aralığıyla süzülebilir. 'query' boşsa filtreye uyan en yeni kayıtları listeler. Sonuçlar kalıcı ID'leriyle döner."
+}
+
+func (t *SearchTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query":     map[string]interface{}{"type": "string", "description": "Aranacak metin veya ifade (Örn: 'db01 disk hatası', 'TSK-1A2B')."},
+			"tags":      map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Kayıtta bulunması gereken etiketler."},
+			"since":     map[string]interface{}{"type": "string", "description": "Bu zamandan sonraki kayıtlar: '2026-10-01', RFC3339 veya göreli ('24h', '7d')."},
+			"until":     map[string]interface{}{"type": "string", "description": "Bu zamandan önceki kayıtlar (since ile aynı biçim)."},
+			"limit":     map[string]interface{}{"type": "integer", "description": "En fazla sonuç sayısı (Varsayılan: 5)."},
+			"scope":     map[string]interface{}{"type": "string", "enum": []string{"default", "conversation", "shared", "all"}, "description": "'default': bu sohbet + ortak hafıza. 'all': tüm sohbetler (sadece gerekirse)."},
+			"namespace": map[string]interface{}{"type": "string", "description": "Opsiyonel, scope yerine tek bir ad alanı (Örn: 'project:rick')."},
+			"metadata":  map[string]interface{}{"type": "object", "description": "Metadata eşleşme filtresi (Örn: {'source': 'kullanıcı'})."},
+		},
+	}
+}
+
+func (t *SearchTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+func (t *SearchTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	q, err := parseQuery(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := t.Memory.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("hafıza araması başarısız: %v", err)
+	}
+
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.ID
+	}
+	if len(records) == 0 {
+		return kernel.Success("📭 Hafızada eşleşen kayıt bulunamadı.", map[string]interface{}{"ids": ids}), nil
+	}
+	text := fmt.Sprintf("🧠 HAFIZA SONUÇLARI (%d):\n%s", len(records), formatRecords(records))
+	return kernel.Success(text, map[string]interface{}{"ids": ids}), nil
+}
+
+// --- TOOL 3: UNUT (memory_forget) ---
+
+type ForgetTool struct {
+	Memory kernel.Memory
+}
+
+func (t *ForgetTool) Name() string { return "memory_forget" }
+
+func (t *ForgetTool) Description() string {
+	return "Hafızadaki kayıtları KALICI olarak siler. ID listesi veya arama sorgusu ile çalışır. Önce 'confirm' olmadan çağır: silinecek kayıtlar listelenir. Listeyi kontrol ettikten sonra aynı parametrelerle 'confirm: true' göndererek sil."
+}
+
+func (t *ForgetTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"ids":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Silinecek kayıtların ID'leri (memory_search sonuçlarındaki 🆔)."},
+			"query":     map[string]interface{}{"type": "string", "description": "ID yerine: bu sorguyla eşleşen kayıtları sil."},
+			"tags":      map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Sorguyla birlikte: sadece bu etiketlere sahip kayıtlar."},
+			"limit":     map[string]interface{}{"type": "integer", "description": "Sorguyla en fazla kaç kayıt silinsin (Varsayılan: 5)."},
+			"scope":     map[string]interface{}{"type": "string", "enum": []string{"default", "conversation", "shared", "all"}, "description": "'default': bu sohbet + ortak hafıza. 'all': tüm sohbetler (sadece gerekirse)."},
+			"namespace": map[string]interface{}{"type": "string", "description": "Opsiyonel, scope yerine tek bir ad alanı (Örn: 'project:rick')."},
+			"metadata":  map[string]interface{}{"type": "object", "description": "Metadata eşleşme filtresi (Örn: {'source': 'kullanıcı'})."},
+
+			"confirm": map[string]interface{}{"type": "boolean", "description": "True ise gerçekten siler. False/boş ise sadece ön izleme yapar."},
+		},
+	}
+}
+
+func (t *ForgetTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+func (t *ForgetTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
+	ids, _ := args["ids"].([]interface{})
+	query, _ := args["query"].(string)
+	confirm, _ := args["confirm"].(bool)
+
+	q, err := parseQuery(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Silinecek kayıtları belirle (Sadece erişilebilir ad alanlarından)
+	var targets []kernel.MemoryRecord
+	switch {
+	case len(ids) > 0:
+		for _, raw := range ids {
+			id, _ := raw.(string)
+			rec, err := t.Memory.Get(ctx, strings.TrimSpace(id))
+			if err != nil {
+				return nil, err
+			}
+			if !visible(q.Namespaces, rec.Namespace) {
+				return nil, fmt.Errorf("HATA: '%s' kaydı bu sohbetin erişebileceği bir ad alanında değil (%s)", rec.ID, rec.Namespace)
+			}
+			targets = append(targets, *rec)
+		}
+	case strings.TrimSpace(query) != "":
+		if targets, err = t.Memory.Query(ctx, q); err != nil {
+			return nil, fmt.Errorf("hafıza araması başarısız: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("HATA: Silmek için 'ids' veya 'query' vermelisin")
+	}
+
+	if len(targets) == 0 {
+		return kernel.Success("📭 Silinecek eşleşen kayıt bulunamadı.", map[string]interface{}{"deleted": 0}), nil
+	}
+
+	targetIDs := make([]string, len(targets))
+	for i, r := range targets {
+		targetIDs[i] = r.ID
+	}
+
+	// 2. Onay yoksa sadece ön izleme
+	if !confirm {
+		text := fmt.Sprintf("⚠️ ONAY GEREKİYOR: Aşağıdaki %d kayıt silinecek. Eminsen aynı parametrelerle 'confirm: true' gönder.\n%s", len(targets), formatRecords(targets))
+		return kernel.Success(text, map[string]interface{}{"pending_ids": targetIDs, "confirmed": false}), nil
+	}
+
+	deleted, err := t.Memory.Delete(ctx, targetIDs...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Action("🗑️ Hafızadan silindi: %d kayıt %v", deleted, targetIDs)
+	return kernel.Success(fmt.Sprintf("✅ %d hafıza kaydı kalıcı olarak silindi.", deleted), map[string]interface{}{"deleted": deleted, "ids": targetIDs}), nil
+}
+
+// parseQuery: Ortak arama parametrelerini (query, tags, since, until, limit, scope, metadata) MemoryQuery'ye çevirir.
+func parseQuery(ctx context.Context, args map[string]interface{}) (kernel.MemoryQuery, error) {
+	q := kernel.MemoryQuery{Tags: stringList(args["tags"])}
+	q.Query, _ = args["query"].(string)
+	q.Query = strings.TrimSpace(q.Query)
+	if l, ok := args["limit"].(float64); ok {
+		q.Limit = int(l)
+	}
+	q.Metadata, _ = args["metadata"].(map[string]interface{})
+
+	var err error
+	if q.Namespaces, err = readNamespaces(ctx, args); err != nil {
+		return q, err
+	}
+
+	since, _ := args["since"].(string)
+	until, _ := args["until"].(string)
+	if q.Since, err = ParseTimeArg(since); err != nil {
+		return q, err
+	}
+	if q.Until, err = ParseTimeArg(until); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// readNamespaces: Okuma kapsamı. Varsayılan: bu sohbet + ortak hafıza. 'all' için boş liste (filtre yok) döner.
+func readNamespaces(ctx context.Context, args map[string]interface{}) ([]string, error) {
+	if ns, _ := args["namespace"].(string); strings.TrimSpace(ns) != "" {
+		ns = strings.TrimSpace(ns)
+		return []string{ns}, kernel.ValidNamespace(ns)
+	}
+
+	conversation := kernel.ConversationNamespace(kernel.ConversationFrom(ctx))
+	scope, _ := args["scope"].(string)
+	switch scope {
+	case "", "default":
+		return []string{conversation, kernel.NamespaceShared}, nil
+	case "conversation":
+		return []string{conversation}, nil
+	case "shared":
+		return []string{kernel.NamespaceShared}, nil
+	case "all":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("geçersiz scope: '%s' (default, conversation, shared, all)", scope)
+}
+
+// writeNamespace: Yazma kapsamı. Varsayılan: bu sohbet.
+func writeNamespace(ctx context.Context, args map[string]interface{}) (string, error) {
+	if ns, _ := args["namespace"].(string); strings.TrimSpace(ns) != "" {
+		ns = strings.TrimSpace(ns)
+		return ns, kernel.ValidNamespace(ns)
+	}
+	scope, _ := args["scope"].(string)
+	switch scope {
+	case "", "conversation":
+		return kernel.ConversationNamespace(kernel.ConversationFrom(ctx)), nil
+	case "shared":
+		return kernel.NamespaceShared, nil
+	}
+	return "", fmt.Errorf("geçersiz scope: '%s' (conversation, shared)", scope)
+}
+
+// visible: Ad alanı okuma kapsamında mı? (Boş kapsam = hepsi)
+func visible(namespaces []string, ns string) bool {
+	if len(namespaces) == 0 {
+		return true
+	}
+	for _, n := range namespaces {
+		if n == ns {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package recall
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+// stringList: Modelden gelen dizi veya virgüllü metni temiz, küçük harfli listeye çevirir.
+func stringList(raw interface{}) []string {
+	items := plainList(raw)
+	for i, item := range items {
+		items[i] = strings.ToLower(item)
+	}
+	return items
+}
+
+// plainList: stringList gibi ama büyük/küçük harfi korur (Dosya yolları, glob'lar).
+func plainList(raw interface{}) []string {
+	var items []string
+	switch v := raw.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
+	case []string:
+		items = v
+	case string:
+		items = strings.Split(v, ",")
+	}
+
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// ParseTimeArg: "2026-10-19", RFC3339 veya göreli süre ("24h", "7d" = 7 gün önce) kabul eder.
+func ParseTimeArg(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if strings.HasSuffix(raw, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(raw, "d")); err == nil {
+			return time.Now().AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(raw); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("geçersiz zaman: '%s' (Örn: '2026-10-19', '24h', '7d')", raw)
+}
+
+// formatRecords: Kayıtları ID'leriyle birlikte modele okunur biçimde listeler.
+func formatRecords(records []kernel.MemoryRecord) string {
+	var sb strings.Builder
+	for _, r := range records {
+		content := r.Content
+		if len([]rune(content)) > 400 {
+			content = string([]rune(content)[:400]) + "..."
+		}
+		sb.WriteString(fmt.Sprintf("🆔 %s | %s", r.ID, r.CreatedAt.Format("2006-01-02 15:04")))
+		if tags := r.Tags(); len(tags) > 0 {
+			sb.WriteString(" | 🏷️ " + strings.Join(tags, ", "))
+		}
+		sb.WriteString(" | 📂 " + r.Namespace)
+		if src, ok := r.Metadata["source"]; ok {
+			sb.WriteString(fmt.Sprintf(" | 📍 %v", src))
+		}
+		sb.WriteString("\n   " + content + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,290 @@
+// Package sandbox: Dinamik (Python) araçları sınırlandırılmış bir ortamda çalıştırır.
+// Her çalıştırma: duvar saati zaman aşımı, çıktı sınırı, temizlenmiş ortam değişkenleri ve
+// çalışma sonunda silinen geçici bir klasör alır. Linux'ta ek olarak CPU, bellek, süreç ve
+// açık dosya sınırları (rlimit) ile isteğe bağlı ağ yalıtımı (yetkisiz user+net namespace) uygulanır.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HelperArg: Rick'in kendini sandbox yardımcısı olarak yeniden çalıştırdığı gizli argüman (Bkz. MaybeRunHelper)
+const HelperArg = "__rick_sandbox_exec"
+
+// İhlal türleri (ToolResult.Data["violation"])
+const (
+	ViolationTimeout   = "timeout"
+	ViolationCPU       = "cpu"
+	ViolationMemory    = "memory"
+	ViolationProcesses = "processes"
+	ViolationFiles     = "open_files"
+	ViolationOutput    = "output"
+	ViolationNetwork   = "network"
+)
+
+// Limits: Tek çalıştırmanın sınırları. Sıfır değerler varsayılanla doldurulur, negatif değerler sınırı kapatır.
+type Limits struct {
+	Timeout        time.Duration // Duvar saati (Varsayılan: 60 sn)
+	CPUSeconds     int           // İşlemci süresi (Varsayılan: 30)
+	MemoryMB       int           // Adres alanı (Varsayılan: 1024)
+	MaxProcesses   int           // Kullanıcı başına süreç/iş parçacığı (Varsayılan: kapalı; gerçek UID'nin tüm süreçlerini sayar)
+	MaxOpenFiles   int           // Açık dosya tanıtıcısı (Varsayılan: 256)
+	MaxOutputBytes int           // stdout+stderr toplamı (Varsayılan: 256 KB)
+	IsolateNetwork bool          // Ağ erişimini kapat (Sadece Linux, yetkisiz namespace destekleniyorsa)
+	EnvAllow       []string      // Scripte aktarılacak ek ortam değişkenleri (Gerisi silinir)
+}
+
+// Violation: Sınır aşıldığında dönen yapısal hata
+type Violation struct {
+	Kind   string // Violation* sabitlerinden biri
+	Limit  string // Aşılan sınırın okunur hali (Örn: "30s", "512 MB")
+	Detail string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("sandbox ihlali (%s, sınır %s): %s", v.Kind, v.Limit, v.Detail)
+}
+
+// Input: Sürece verilen girdi ve ortam (Manifest araçları argümanlarını stdin'den JSON olarak okur)
+type Input struct {
+	Stdin          []byte
+	Env            []string // Temizlenmiş ortama eklenen "AD=DEĞER" çiftleri
+	SeparateStderr bool     // stderr Result.Stderr'e ayrı yazılır (stdout makinece okunacaksa); sınır ikisine ayrı uygulanır
+}
+
+// Result: Çalıştırma sonucu
+type Result struct {
+	Output    string // stdout (SeparateStderr yoksa stderr dahil)
+	Stderr    string
+	ExitCode  int
+	Duration  time.Duration
+	Truncated bool       // Çıktı sınırı aşıldı
+	Violation *Violation // Sınır aşımı yoksa nil
+	Isolated  bool       // Ağ yalıtımı gerçekten uygulandı mı
+}
+
+// baseEnv: Her zaman aktarılan, sır içermeyen değişkenler
+var baseEnv = []string{"PATH", "LANG", "LC_ALL", "LC_CTYPE", "TZ", "SYSTEMROOT", "COMSPEC", "PATHEXT", "WINDIR"}
+
+// WithDefaults: Boş alanları varsayılanlarla doldurur.
+func (l Limits) WithDefaults() Limits {
+	if l.Timeout == 0 {
+		l.Timeout = 60 * time.Second
+	}
+	if l.CPUSeconds == 0 {
+		l.CPUSeconds = 30
+	}
+	if l.MemoryMB == 0 {
+		l.MemoryMB = 1024
+	}
+	if l.MaxOpenFiles == 0 {
+		l.MaxOpenFiles = 256
+	}
+	if l.MaxOutputBytes == 0 {
+		l.MaxOutputBytes = 256 << 10
+	}
+	return l
+}
+
+// Run: Programı sınırlar altında, kendine ait geçici klasörde çalıştırır.
+// Sınır aşımları hata değil, Result.Violation olarak döner; hata sadece süreç hiç başlatılamazsa döner.
+func Run(ctx context.Context, limits Limits, program string, args ...string) (*Result, error) {
+	return RunInput(ctx, limits, Input{}, program, args...)
+}
+
+// RunInput: Run gibi; ek olarak stdin, ortam değişkenleri ve ayrı stderr verir.
+func RunInput(ctx context.Context, limits Limits, in Input, program string, args ...string) (*Result, error) {
+	limits = limits.WithDefaults()
+
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return nil, fmt.Errorf("çalıştırılabilir bulunamadı: %v", err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "rick-sandbox-")
+	if err != nil {
+		return nil, fmt.Errorf("geçici çalışma klasörü oluşturulamadı: %v", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	runCtx := ctx
+	var cancel context.CancelFunc
+	if limits.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, limits.Timeout)
+	} else {
+		runCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var out, errOut *cappedBuffer
+	setup := func(cmd *exec.Cmd) {
+		out = &cappedBuffer{limit: limits.MaxOutputBytes, onOverflow: cancel}
+		errOut = out
+		if in.SeparateStderr {
+			errOut = &cappedBuffer{limit: limits.MaxOutputBytes, onOverflow: cancel}
+		}
+		cmd.Dir = workDir
+		cmd.Env = append(scrubbedEnv(limits.EnvAllow, workDir), in.Env...)
+		cmd.Stdout = out
+		cmd.Stderr = errOut
+		if in.Stdin != nil {
+			cmd.Stdin = bytes.NewReader(in.Stdin)
+		}
+		cmd.WaitDelay = 2 * time.Second
+	}
+
+	started := time.Now()
+	cmd, isolated, err := start(runCtx, limits, path, args, setup)
+	if err != nil {
+		return nil, fmt.Errorf("süreç başlatılamadı: %v", err)
+	}
+	waitErr := cmd.Wait()
+
+	res := &Result{
+		Output:    out.String(),
+		ExitCode:  exitCode(waitErr),
+		Duration:  time.Since(started),
+		Truncated: out.Overflowed() || errOut.Overflowed(),
+		Isolated:  isolated,
+	}
+	if in.SeparateStderr {
+		res.Stderr = errOut.String()
+	}
+	switch {
+	case res.Truncated:
+		res.Violation = &Violation{Kind: ViolationOutput, Limit: formatBytes(limits.MaxOutputBytes), Detail: "çıktı sınırı aşıldı, süreç durduruldu"}
+	case ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded:
+		res.Violation = &Violation{Kind: ViolationTimeout, Limit: limits.Timeout.String(), Detail: "süre doldu, süreç durduruldu"}
+	case waitErr != nil:
+		res.Violation = classify(waitErr, res.Output+res.Stderr, limits, isolated)
+	}
+	if ctx.Err() != nil && res.Violation == nil {
+		return res, ctx.Err() // Oturum iptali sandbox ihlali değildir
+	}
+	return res, nil
+}
+
+// AllowedEnv: Ortamdan sadece sır içermeyen temel değişkenleri ve izin verilenleri seçer.
+// Sandbox dışında çalışan süreçler de API anahtarlarını bununla devralmaz.
+func AllowedEnv(allow ...string) []string {
+	keep := make(map[string]bool)
+	for _, k := range append(append([]string(nil), baseEnv...), allow...) {
+		keep[strings.ToUpper(strings.TrimSpace(k))] = true
+	}
+
+	var env []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if keep[strings.ToUpper(key)] {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+// scrubbedEnv: Sadece güvenli ve izin verilen değişkenleri taşır; HOME ve TMPDIR geçici klasöre yönlenir.
+func scrubbedEnv(allow []string, workDir string) []string {
+	env := append(AllowedEnv(allow...),
+		"HOME="+workDir,
+		"TMPDIR="+workDir,
+		"PYTHONIOENCODING=utf-8",
+		"PYTHONDONTWRITEBYTECODE=1",
+	)
+	if runtime.GOOS == "windows" {
+		env = append(env, "USERPROFILE="+workDir, "TEMP="+workDir, "TMP="+workDir)
+	}
+	return env
+}
+
+// classify: Çıkış durumunu ve çıktıyı inceleyip hangi sınırın aşıldığını tahmin eder (Bilinmiyorsa nil).
+func classify(waitErr error, output string, limits Limits, isolated bool) *Violation {
+	if v := signalViolation(waitErr, limits); v != nil {
+		return v
+	}
+	switch {
+	case strings.Contains(output, "MemoryError") || strings.Contains(output, "Cannot allocate memory"):
+		return &Violation{Kind: ViolationMemory, Limit: fmt.Sprintf("%d MB", limits.MemoryMB), Detail: "bellek sınırı aşıldı"}
+	case strings.Contains(output, "Too many open files"):
+		return &Violation{Kind: ViolationFiles, Limit: fmt.Sprint(limits.MaxOpenFiles), Detail: "açık dosya sınırı aşıldı"}
+	case limits.MaxProcesses > 0 && (strings.Contains(output, "Resource temporarily unavailable") || strings.Contains(output, "can't start new thread")):
+		return &Violation{Kind: ViolationProcesses, Limit: fmt.Sprint(limits.MaxProcesses), Detail: "süreç/iş parçacığı sınırı aşıldı"}
+	case isolated && (strings.Contains(output, "Network is unreachable") || strings.Contains(output, "Temporary failure in name resolution") || strings.Contains(output, "Name or service not known")):
+		return &Violation{Kind: ViolationNetwork, Limit: "ağ kapalı", Detail: "script ağa erişmeye çalıştı ama sandbox ağı yalıtıyor"}
+	}
+	return nil
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func secondsDuration(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
+func formatBytes(n int) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%d MB", n>>20)
+	}
+	return fmt.Sprintf("%d KB", n>>10)
+}
+
+// cappedBuffer: Sınıra kadar yazar, aşılınca fazlasını atar ve süreci durdurmak için onOverflow'u bir kez çağırır.
+type cappedBuffer struct {
+	mu         sync.Mutex
+	buf        bytes.Buffer
+	limit      int
+	overflowed bool
+	onOverflow func()
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.limit <= 0 {
+		return c.buf.Write(p)
+	}
+	if room := c.limit - c.buf.Len(); room < len(p) {
+		if room > 0 {
+			c.buf.Write(p[:room])
+		}
+		if !c.overflowed {
+			c.overflowed = true
+			if c.onOverflow != nil {
+				go c.onOverflow()
+			}
+		}
+		return len(p), nil // Yazan taraf bloklanmasın; süreç zaten durduruluyor
+	}
+	return c.buf.Write(p)
+}
+
+func (c *cappedBuffer) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.String()
+}
+
+func (c *cappedBuffer) Overflowed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.overflowed
+}
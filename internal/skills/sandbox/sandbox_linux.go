@@ -0,0 +1,174 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// rlimitNproc: syscall paketinde tanımlı değil (linux/resource.h)
+const rlimitNproc = 6
+
+var (
+	netnsOnce   sync.Once
+	netnsOK     atomic.Bool // Başlatma hatasında eşzamanlı süreçlerden kapatılabilir
+	netnsWarned sync.Once
+)
+
+// MaybeRunHelper: Süreç sandbox yardımcısı olarak başlatıldıysa (HelperArg) rlimit'leri kurar ve hedef
+// programa exec eder; asla geri dönmez. Değilse hiçbir şey yapmadan döner.
+// main() içinde her şeyden önce çağrılmalıdır.
+func MaybeRunHelper() {
+	if len(os.Args) < 2 || os.Args[1] != HelperArg {
+		return
+	}
+	// Biçim: <exe> HelperArg <cpu> <mem_mb> <nproc> <nofile> <program> [args...]
+	if len(os.Args) < 7 {
+		fmt.Fprintln(os.Stderr, "sandbox: eksik argüman")
+		os.Exit(126)
+	}
+	var nums [4]uint64
+	for i := range nums {
+		n, err := strconv.ParseInt(os.Args[2+i], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sandbox: geçersiz sınır %q\n", os.Args[2+i])
+			os.Exit(126)
+		}
+		if n > 0 {
+			nums[i] = uint64(n)
+		}
+	}
+	program, argv := os.Args[6], os.Args[6:]
+	env := os.Environ()
+
+	// Sınırlar exec'ten hemen önce kurulur (Yardımcının kendi Go çalışma zamanı sınırlardan etkilenmesin)
+	set := func(resource int, cur, max uint64) {
+		if cur == 0 {
+			return
+		}
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: cur, Max: max}); err != nil {
+			fmt.Fprintf(os.Stderr, "sandbox: rlimit %d kurulamadı: %v\n", resource, err)
+			os.Exit(126)
+		}
+	}
+	set(syscall.RLIMIT_CPU, nums[0], nums[0]+1) // Yumuşak sınırda SIGXCPU, bir saniye sonra SIGKILL
+	set(syscall.RLIMIT_AS, nums[1]<<20, nums[1]<<20)
+	set(rlimitNproc, nums[2], nums[2])
+	set(syscall.RLIMIT_NOFILE, nums[3], nums[3])
+
+	err := syscall.Exec(program, argv, env)
+	fmt.Fprintf(os.Stderr, "sandbox: %s çalıştırılamadı: %v\n", program, err)
+	os.Exit(126)
+}
+
+// start: Programı yardımcı üzerinden (rlimit'lerle) kendi süreç grubunda başlatır. Ağ yalıtımı istenip
+// çekirdek izin vermezse bir kez uyarır ve yalıtımsız çalıştırır. Yalıtımın uygulanıp uygulanmadığını döner.
+func start(ctx context.Context, limits Limits, path string, args []string, setup func(*exec.Cmd)) (*exec.Cmd, bool, error) {
+	isolate := limits.IsolateNetwork && netnsUsable()
+
+	cmd, err := helperCommand(ctx, limits, path, args, isolate)
+	if err != nil {
+		return nil, false, err
+	}
+	setup(cmd)
+	err = cmd.Start()
+	if err != nil && isolate {
+		netnsOK.Store(false)
+		warnNoNetns(err)
+		isolate = false
+		if cmd, err = helperCommand(ctx, limits, path, args, false); err != nil {
+			return nil, false, err
+		}
+		setup(cmd)
+		err = cmd.Start()
+	} else if limits.IsolateNetwork && !isolate {
+		warnNoNetns(nil)
+	}
+	return cmd, isolate, err
+}
+
+func helperCommand(ctx context.Context, limits Limits, path string, args []string, isolate bool) (*exec.Cmd, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox yardımcısı bulunamadı: %v", err)
+	}
+	helperArgs := append([]string{
+		HelperArg,
+		strconv.Itoa(limits.CPUSeconds),
+		strconv.Itoa(limits.MemoryMB),
+		strconv.Itoa(limits.MaxProcesses),
+		strconv.Itoa(limits.MaxOpenFiles),
+		path,
+	}, args...)
+
+	cmd := exec.CommandContext(ctx, exe, helperArgs...)
+	attr := &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	if isolate {
+		uid, gid := os.Getuid(), os.Getgid()
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}}
+		attr.GidMappingsEnableSetgroups = false
+	}
+	cmd.SysProcAttr = attr
+	// Zaman aşımında sadece yardımcıyı değil, scriptin başlattığı tüm alt süreçleri öldür
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd, nil
+}
+
+// netnsUsable: Yetkisiz user namespace'leri açık mı? (Bazı dağıtımlar sysctl ile kapatır)
+func netnsUsable() bool {
+	netnsOnce.Do(func() {
+		ok := true
+		for _, path := range []string{"/proc/sys/kernel/unprivileged_userns_clone", "/proc/sys/user/max_user_namespaces"} {
+			if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == "0" {
+				ok = false
+			}
+		}
+		netnsOK.Store(ok)
+	})
+	return netnsOK.Load()
+}
+
+func warnNoNetns(err error) {
+	netnsWarned.Do(func() {
+		if err != nil {
+			logger.Warn("⚠️ Sandbox ağ yalıtımı kurulamadı (%v); araçlar ağ erişimiyle çalışacak.", err)
+		} else {
+			logger.Warn("⚠️ Bu sistemde yetkisiz user namespace kapalı; sandbox ağ yalıtımı uygulanamıyor.")
+		}
+	})
+}
+
+// signalViolation: Sinyalle sonlanan süreçlerde CPU sınırını tanır (SIGXCPU veya sert sınırdaki SIGKILL).
+func signalViolation(waitErr error, limits Limits) *Violation {
+	exitErr, ok := waitErr.(*exec.ExitError)
+	if !ok {
+		return nil
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return nil
+	}
+	switch status.Signal() {
+	case syscall.SIGXCPU:
+		return &Violation{Kind: ViolationCPU, Limit: fmt.Sprintf("%d sn", limits.CPUSeconds), Detail: "işlemci süresi sınırı aşıldı"}
+	case syscall.SIGKILL:
+		if limits.CPUSeconds > 0 && exitErr.ProcessState.UserTime()+exitErr.ProcessState.SystemTime() >= secondsDuration(limits.CPUSeconds) {
+			return &Violation{Kind: ViolationCPU, Limit: fmt.Sprintf("%d sn", limits.CPUSeconds), Detail: "işlemci süresi sınırı aşıldı"}
+		}
+	}
+	return nil
+}
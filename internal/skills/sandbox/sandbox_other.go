@@ -0,0 +1,30 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+var limitsWarned sync.Once
+
+// MaybeRunHelper: Yardımcı süreç sadece Linux'ta kullanılır; burada hiçbir şey yapmaz.
+func MaybeRunHelper() {}
+
+// start: rlimit ve ağ yalıtımı olmadan başlatır (Zaman aşımı, çıktı sınırı, ortam temizliği ve geçici klasör yine geçerli).
+func start(ctx context.Context, limits Limits, path string, args []string, setup func(*exec.Cmd)) (*exec.Cmd, bool, error) {
+	limitsWarned.Do(func() {
+		logger.Warn("⚠️ Bu işletim sisteminde sandbox sadece zaman aşımı, çıktı sınırı ve ortam temizliği uygular (rlimit ve ağ yalıtımı yok).")
+	})
+	cmd := exec.CommandContext(ctx, path, args...)
+	setup(cmd)
+	return cmd, false, cmd.Start()
+}
+
+func signalViolation(waitErr error, limits Limits) *Violation {
+	return nil
+}
@@ -0,0 +1,269 @@
+package skills
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// unsupportedSchemaKeys: Bazı sağlayıcıların (Gemini) reddettiği JSON Schema alanları; araç şemasından atılır.
+var unsupportedSchemaKeys = []string{"$schema", "$id", "additionalProperties"}
+
+// NormalizeSchema: Modelin verdiği parametre şemasını araç tanımına uygun hale getirir.
+// Sadece 'properties' haritası verilmişse {"type": "object", "properties": ...} ile sarar,
+// JSON metin olarak verilmişse çözer. Boş şema için nil döner (Serbest 'args' nesnesi kullanılır).
+func NormalizeSchema(raw interface{}) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		if err := json.Unmarshal([]byte(v), &schema); err != nil {
+			return nil, fmt.Errorf("HATA: parametre şeması geçerli JSON değil: %v", err)
+		}
+	case map[string]interface{}:
+		schema = v
+	default:
+		return nil, fmt.Errorf("HATA: parametre şeması bir JSON nesnesi olmalı")
+	}
+	if len(schema) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := schema["type"]; !ok {
+		if _, hasProps := schema["properties"]; !hasProps {
+			schema = map[string]interface{}{"properties": schema}
+		}
+		schema["type"] = "object"
+	}
+	if schema["type"] != "object" {
+		return nil, fmt.Errorf("HATA: parametre şemasının kök tipi 'object' olmalı")
+	}
+	props, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("HATA: parametre şemasında 'properties' nesnesi eksik")
+	}
+	if _, ok := props["args"]; ok {
+		return nil, fmt.Errorf("HATA: 'args' ayrılmış bir addır (Script'teki args sözlüğünün kendisi), parametreye başka bir ad ver")
+	}
+	for name, prop := range props {
+		p, ok := prop.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("HATA: '%s' parametresinin tanımı bir nesne olmalı (Örn: {\"type\": \"string\"})", name)
+		}
+		if t, ok := p["type"].(string); ok && !knownType(t) {
+			return nil, fmt.Errorf("HATA: '%s' parametresinin tipi geçersiz: '%s'", name, t)
+		}
+	}
+	for _, req := range schemaStrings(schema["required"]) {
+		if _, ok := props[req]; !ok {
+			return nil, fmt.Errorf("HATA: zorunlu parametre '%s' şemada tanımlı değil", req)
+		}
+	}
+	return stripUnsupported(schema).(map[string]interface{}), nil
+}
+
+// CleanSchema: Dışarıdan gelen (MCP vb.) hazır bir şemayı sağlayıcılara gönderilebilir hale getirir.
+// NormalizeSchema'dan farkı: Reddetmez; desteklenmeyen alanları atar, kökü nesne şemasına tamamlar.
+func CleanSchema(schema map[string]interface{}) map[string]interface{} {
+	if len(schema) == 0 {
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	out := stripUnsupported(schema).(map[string]interface{})
+	out["type"] = "object"
+	if _, ok := out["properties"].(map[string]interface{}); !ok {
+		out["properties"] = map[string]interface{}{}
+	}
+	return out
+}
+
+// ValidateArgs: Argümanları şemaya göre denetler (zorunlu alanlar, tipler, enum, iç içe nesne ve diziler).
+// Tüm sorunları tek hata mesajında toplar; şema nil ise her şey geçerlidir.
+func ValidateArgs(schema map[string]interface{}, args map[string]interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	var problems []string
+	validateValue(schema, args, "", &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
+
+func validateValue(schema map[string]interface{}, value interface{}, path string, problems *[]string) {
+	label := path
+	if label == "" {
+		label = "argümanlar"
+	}
+
+	if t, ok := schema["type"].(string); ok && !matchesType(t, value) {
+		*problems = append(*problems, fmt.Sprintf("'%s' %s olmalı (gelen: %s)", label, t, jsonType(value)))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*problems = append(*problems, fmt.Sprintf("'%s' şunlardan biri olmalı: %v", label, enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, req := range schemaStrings(schema["required"]) {
+			if _, ok := v[req]; !ok {
+				*problems = append(*problems, fmt.Sprintf("zorunlu parametre eksik: '%s'", joinPath(path, req)))
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := props[k].(map[string]interface{}); ok {
+				validateValue(sub, v[k], joinPath(path, k), problems)
+			}
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		if items == nil {
+			return
+		}
+		for i, item := range v {
+			validateValue(items, item, fmt.Sprintf("%s[%d]", label, i), problems)
+		}
+	}
+}
+
+func knownType(t string) bool {
+	switch t {
+	case "string", "number", "integer", "boolean", "array", "object", "null":
+		return true
+	}
+	return false
+}
+
+func matchesType(t string, value interface{}) bool {
+	switch t {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		f, ok := toFloat(value)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func schemaStrings(raw interface{}) []string {
+	var out []string
+	switch v := raw.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+	case []string:
+		out = v
+	}
+	return out
+}
+
+// stripUnsupported: Şemadan sağlayıcıların reddettiği alanları (iç içe dahil) atar.
+func stripUnsupported(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			skip := false
+			for _, bad := range unsupportedSchemaKeys {
+				if k == bad {
+					skip = true
+					break
+				}
+			}
+			if !skip {
+				out[k] = stripUnsupported(val)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = stripUnsupported(val)
+		}
+		return out
+	}
+	return node
+}
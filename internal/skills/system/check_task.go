@@ -3,9 +3,11 @@ package system
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/shirou/gopsutil/v3/process"
 )
 
@@ -25,6 +27,11 @@ func (t *CheckTaskTool) Parameters() map[string]interface{} {
 }
 
 func (t *CheckTaskTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Tekil görev sorgusunda durum, PID ve log yolunu yapısal veri olarak ekler.
+func (t *CheckTaskTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	taskID, _ := args["task_id"].(string)
 	tm := GetTaskManager()
 
@@ -34,7 +41,7 @@ func (t *CheckTaskTool) Execute(ctx context.Context, args map[string]interface{}
 		defer tm.mu.RUnlock()
 		
 		if len(tm.Tasks) == 0 {
-			return "📭 Sistemde kayıtlı herhangi bir görev bulunamadı.", nil
+			return kernel.Success("📭 Sistemde kayıtlı herhangi bir görev bulunamadı.", map[string]interface{}{"count": 0}), nil
 		}
 
 		var sb strings.Builder
@@ -57,7 +64,7 @@ func (t *CheckTaskTool) Execute(ctx context.Context, args map[string]interface{}
 			sb.WriteString(fmt.Sprintf("%s ID: %-15s | %-10s | Süre: %-8s | Komut: %s\n", 
 				statusIcon, id, strings.ToUpper(task.Status), duration, task.Command))
 		}
-		return sb.String(), nil
+		return kernel.Success(sb.String(), map[string]interface{}{"count": len(tm.Tasks)}), nil
 	}
 
 	// 2. TEKİL GÖREV DETAYI MODU
@@ -66,7 +73,7 @@ func (t *CheckTaskTool) Execute(ctx context.Context, args map[string]interface{}
 	tm.mu.RUnlock()
 
 	if !exists {
-		return "", fmt.Errorf("HATA: '%s' ID'li bir görev bulunamadı", taskID)
+		return nil, fmt.Errorf("HATA: '%s' ID'li bir görev bulunamadı", taskID)
 	}
 
 	// 🚀 CANLI KAYNAK TÜKETİMİ (CPU / RAM) HESAPLAMA
@@ -113,5 +120,17 @@ func (t *CheckTaskTool) Execute(ctx context.Context, args map[string]interface{}
 	res += strings.Repeat("-", 40) + "\n"
 	res += fmt.Sprintf("📄 SON LOGLAR:\n%s\n", logContent)
 
-	return res, nil
+	data := map[string]interface{}{
+		"task_id":  task.ID,
+		"status":   task.Status,
+		"pid":      task.PID,
+		"command":  task.Command,
+		"log_path": filepath.Join(tm.LogDir, task.ID+".log"),
+	}
+	return &kernel.ToolResult{
+		Status:    kernel.ToolStatusSuccess,
+		Text:      res,
+		Data:      data,
+		Artifacts: []kernel.Artifact{{Kind: "file", Path: data["log_path"].(string), MimeType: "text/plain"}},
+	}, nil
 }
\ No newline at end of file
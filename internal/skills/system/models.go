@@ -0,0 +1,165 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aydndglr/rick-agent-v3/internal/brain/providers"
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// ModelTool: Çalışan Ollama beyninin modellerini yönetir (listele, incele, indir, değiştir).
+type ModelTool struct {
+	Ollama *providers.OllamaProvider
+}
+
+func NewModelTool(ollama *providers.OllamaProvider) *ModelTool {
+	return &ModelTool{Ollama: ollama}
+}
+
+func (t *ModelTool) Name() string { return "ollama_models" }
+
+func (t *ModelTool) Description() string {
+	return "Yerel Ollama modellerini yönetir. 'list' kurulu modelleri, 'show' model detaylarını ve yeteneklerini (tools/vision) gösterir, 'pull' modeli indirir, 'switch' aktif modeli yeniden başlatmadan değiştirir (global veya sadece bu sohbet için), 'current' aktif modeli, 'reset' sohbete özel seçimi kaldırır."
+}
+
+func (t *ModelTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action":         map[string]interface{}{"type": "string", "enum": []string{"list", "show", "pull", "switch", "current", "reset"}},
+			"model":          map[string]interface{}{"type": "string", "description": "Model adı (Örn: 'qwen3:8b'). 'show', 'pull' ve 'switch' için zorunlu."},
+			"scope":          map[string]interface{}{"type": "string", "enum": []string{"global", "conversation"}, "description": "Sadece 'switch' için. 'conversation' sadece bu sohbetin modelini değiştirir (Varsayılan: global)."},
+			"require_vision": map[string]interface{}{"type": "boolean", "description": "Sadece 'switch' için. True ise görsel (vision) desteklemeyen modele geçişi reddeder."},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *ModelTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	action, _ := args["action"].(string)
+	model, _ := args["model"].(string)
+	model = strings.TrimSpace(model)
+	conversationID := kernel.ConversationFrom(ctx)
+
+	if model == "" && (action == "show" || action == "pull" || action == "switch") {
+		return "", fmt.Errorf("HATA: '%s' işlemi için 'model' parametresi zorunludur", action)
+	}
+
+	switch action {
+	case "list":
+		models, err := t.Ollama.ListModels(ctx)
+		if err != nil {
+			return "", fmt.Errorf("model listesi alınamadı: %v", err)
+		}
+		if len(models) == 0 {
+			return "📭 Yerelde kurulu hiçbir Ollama modeli yok. 'pull' ile indirebilirsin.", nil
+		}
+		active := t.Ollama.ActiveModel(ctx)
+		var sb strings.Builder
+		sb.WriteString("🧠 YEREL OLLAMA MODELLERİ:\n")
+		sb.WriteString(strings.Repeat("-", 60) + "\n")
+		for _, m := range models {
+			marker := "  "
+			if m.Name == active {
+				marker = "👉"
+			}
+			sb.WriteString(fmt.Sprintf("%s %-30s | %-6s | %-8s | %.1f GB\n", marker, m.Name,
+				m.Details.ParameterSize, m.Details.QuantizationLevel, float64(m.Size)/1024/1024/1024))
+		}
+		return sb.String(), nil
+
+	case "show":
+		info, err := t.Ollama.ShowModel(ctx, model)
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("🔎 MODEL DETAYI: %s\n", model))
+		sb.WriteString(strings.Repeat("-", 40) + "\n")
+		caps := "Bilinmiyor (Eski Ollama sürümü)"
+		if len(info.Capabilities) > 0 {
+			caps = strings.Join(info.Capabilities, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("🔹 Yetenekler: %s\n", caps))
+		for _, key := range []string{"family", "parameter_size", "quantization_level", "format"} {
+			if v, ok := info.Details[key]; ok {
+				sb.WriteString(fmt.Sprintf("🔹 %s: %v\n", key, v))
+			}
+		}
+		for key, v := range info.ModelInfo {
+			if strings.HasSuffix(key, ".context_length") {
+				sb.WriteString(fmt.Sprintf("🔹 Bağlam Penceresi: %v token\n", v))
+			}
+		}
+		return sb.String(), nil
+
+	case "pull":
+		logger.Action("📥 Model indiriliyor: %s", model)
+		lastPercent := -10
+		err := t.Ollama.PullModel(ctx, model, func(p providers.PullProgress) {
+			if p.Total <= 0 {
+				return
+			}
+			// Her %10'da bir ilerleme bildir (WhatsApp'ı spam'lemesin)
+			percent := int(p.Completed * 100 / p.Total)
+			if percent >= lastPercent+10 {
+				lastPercent = percent
+				logger.Action("📥 [%s] %s: %%%d (%.0f / %.0f MB)", model, p.Status, percent,
+					float64(p.Completed)/1024/1024, float64(p.Total)/1024/1024)
+			}
+		})
+		if err != nil {
+			return "", fmt.Errorf("model indirilemedi: %v", err)
+		}
+		logger.Success("✅ Model indirildi: %s", model)
+		return fmt.Sprintf("✅ '%s' modeli başarıyla indirildi. Kullanmak için 'switch' yap.", model), nil
+
+	case "switch":
+		info, err := t.Ollama.ShowModel(ctx, model)
+		if err != nil {
+			return "", err
+		}
+		// Rick araçlarsız çalışamaz: Yetenek listesi varsa 'tools' şart
+		if len(info.Capabilities) > 0 && !info.HasCapability("tools") {
+			return "", fmt.Errorf("HATA: '%s' modeli araç çağırmayı (tools) desteklemiyor. Rick bu modelle görev yapamaz", model)
+		}
+		requireVision, _ := args["require_vision"].(bool)
+		if requireVision && !info.HasCapability("vision") {
+			return "", fmt.Errorf("HATA: '%s' modeli görsel (vision) desteklemiyor", model)
+		}
+
+		scope, _ := args["scope"].(string)
+		target := ""
+		if scope == "conversation" {
+			target = conversationID
+		}
+		previous := t.Ollama.ActiveModel(ctx)
+		t.Ollama.SetModel(target, model)
+
+		scopeStr := "GLOBAL"
+		if target != "" {
+			scopeStr = fmt.Sprintf("sohbet [%s]", target)
+		}
+		logger.Success("🔀 Model değiştirildi (%s): %s -> %s", scopeStr, previous, model)
+
+		res := fmt.Sprintf("✅ Aktif model (%s): %s -> %s", scopeStr, previous, model)
+		if len(info.Capabilities) == 0 {
+			res += "\n⚠️ Ollama yetenek bilgisi vermedi, araç desteği doğrulanamadı."
+		} else if !info.HasCapability("vision") {
+			res += "\n⚠️ Bu model görsel (vision) desteklemiyor, resimli mesajlar işlenemez."
+		}
+		return res, nil
+
+	case "current":
+		return fmt.Sprintf("🧠 Bu sohbetin modeli: %s | Global model: %s", t.Ollama.ActiveModel(ctx), t.Ollama.GlobalModel()), nil
+
+	case "reset":
+		t.Ollama.ResetModel(conversationID)
+		return fmt.Sprintf("✅ Sohbete özel model seçimi kaldırıldı. Aktif model: %s", t.Ollama.GlobalModel()), nil
+	}
+
+	return "", fmt.Errorf("geçersiz eylem: '%s'", action)
+}
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
 )
 
@@ -31,11 +32,16 @@ func (t *StartTaskTool) Parameters() map[string]interface{} {
 }
 
 func (t *StartTaskTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Görev kimliğini, PID'i ve log dosyasını yapısal olarak döner.
+func (t *StartTaskTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	cmdStr, _ := args["command"].(string)
 	workDir, _ := args["work_dir"].(string)
 	
 	if cmdStr == "" {
-		return "", fmt.Errorf("eksik parametre: command")
+		return nil, fmt.Errorf("eksik parametre: command")
 	}
 
 	tm := GetTaskManager()
@@ -61,7 +67,7 @@ func (t *StartTaskTool) Execute(ctx context.Context, args map[string]interface{}
 	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		cancel()
-		return "", fmt.Errorf("log dosyası hazırlanamadı: %v", err)
+		return nil, fmt.Errorf("log dosyası hazırlanamadı: %v", err)
 	}
 
 	// Rick'in en büyük silahı: stdout ve stderr'i doğrudan dosyaya bağla!
@@ -72,7 +78,7 @@ func (t *StartTaskTool) Execute(ctx context.Context, args map[string]interface{}
 	if err := cmd.Start(); err != nil {
 		logFile.Close()
 		cancel()
-		return "", fmt.Errorf("görev başlatılamadı: %v", err)
+		return nil, fmt.Errorf("görev başlatılamadı: %v", err)
 	}
 
 	pid := 0
@@ -109,5 +115,10 @@ func (t *StartTaskTool) Execute(ctx context.Context, args map[string]interface{}
 		logger.Info("Görev bitti: %s", taskID)
 	}()
 
-	return fmt.Sprintf("🚀 Görev Arka Planda Başlatıldı! \n🆔 ID: %s \n🔢 PID: %d \n📂 Log: %s \n\nDurumu izlemek için 'check_task' aracını kullan.", taskID, pid, logPath), nil
+	return &kernel.ToolResult{
+		Status:    kernel.ToolStatusSuccess,
+		Text:      fmt.Sprintf("🚀 Görev Arka Planda Başlatıldı! \n🆔 ID: %s \n🔢 PID: %d \n📂 Log: %s \n\nDurumu izlemek için 'check_task' aracını kullan.", taskID, pid, logPath),
+		Data:      map[string]interface{}{"task_id": taskID, "pid": pid, "log_path": logPath},
+		Artifacts: []kernel.Artifact{{Kind: "file", Path: logPath, MimeType: "text/plain"}},
+	}, nil
 }
\ No newline at end of file
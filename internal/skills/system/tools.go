@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
 	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
 )
 
@@ -32,7 +33,15 @@ func (t *ExecTool) Parameters() map[string]interface{} {
 	}
 }
 func (t *ExecTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return kernel.ResultText(t.ExecuteRich(ctx, args))
+}
+
+// ExecuteRich: Çıkış kodunu ve zaman aşımını yapısal olarak bildirir; sıfır olmayan çıkış 'failed' döner.
+func (t *ExecTool) ExecuteRich(ctx context.Context, args map[string]interface{}) (*kernel.ToolResult, error) {
 	cmdStr, _ := args["command"].(string)
+	if strings.TrimSpace(cmdStr) == "" {
+		return nil, fmt.Errorf("eksik parametre: command")
+	}
 
 	// Zaman Aşımı (Timeout) Ayarı
 	timeoutSec := 60 // Varsayılan 60 saniye
@@ -63,28 +72,44 @@ func (t *ExecTool) Execute(ctx context.Context, args map[string]interface{}) (st
 		logger.Action("💻 Terminal: %s", cmdStr)
 	}
 
+	started := time.Now()
 	output, err := cmd.CombinedOutput()
 	result := strings.TrimSpace(string(output))
+	exitCode := kernel.ExitCode(err)
+
+	data := map[string]interface{}{"command": cmdStr, "exit_code": exitCode}
+	metrics := map[string]float64{
+		"duration_ms":  float64(time.Since(started).Milliseconds()),
+		"output_bytes": float64(len(output)),
+	}
 	
 	// Zaman aşımı yakalama
 	if execCtx.Err() == context.DeadlineExceeded {
-		return fmt.Sprintf("🛑 UYARI: Komut %d saniye içinde tamamlanamadı ve zorla durduruldu (Timeout). \nEğer bu uzun sürecek bir işlemse 'start_task' aracını kullanmalısın!\nKısmi Çıktı:\n%s", timeoutSec, result), nil
+		data["timed_out"] = true
+		res := kernel.Failed(fmt.Sprintf("🛑 UYARI: Komut %d saniye içinde tamamlanamadı ve zorla durduruldu (Timeout). \nEğer bu uzun sürecek bir işlemse 'start_task' aracını kullanmalısın!\nKısmi Çıktı:\n%s", timeoutSec, result), data)
+		res.Metrics = metrics
+		return res, nil
 	}
 
-	if err != nil {
-		return fmt.Sprintf("⚠️ Komut Hatası (Exit Code): %v\nÇıktı:\n%s", err, result), nil
-	}
-	
-	if result == "" {
-		return "✅ Komut çalıştı (Çıktı yok).", nil
-	}
-	
 	// Çıktı çok uzunsa Rick'in jetonlarını tüketmemesi için kırpma
 	if len(result) > 4000 {
+		data["truncated"] = true
 		result = result[:4000] + "\n\n...[SİSTEM UYARISI: ÇIKTI ÇOK UZUN OLDUĞU İÇİN KESİLDİ. Daha fazlası için çıktıyı bir dosyaya yazdırıp fs_read ile okuyabilirsin]..."
 	}
+
+	if err != nil {
+		res := kernel.Failed(fmt.Sprintf("⚠️ Komut Hatası (Exit Code %d): %v\nÇıktı:\n%s", exitCode, err, result), data)
+		res.Metrics = metrics
+		return res, nil
+	}
 	
-	return result, nil
+	if result == "" {
+		result = "✅ Komut çalıştı (Çıktı yok)."
+	}
+
+	res := kernel.Success(result, data)
+	res.Metrics = metrics
+	return res, nil
 }
 
 // --- TOOL 2: SYSTEM INFO ---
@@ -0,0 +1,307 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/config"
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+	"github.com/aydndglr/rick-agent-v3/internal/core/logger"
+)
+
+// Oturum kayıtları bu süreden eskiyse defterden temizlenir (Gün ve sohbet toplamları kalıcıdır)
+const sessionRetention = 30 * 24 * time.Hour
+
+// saveDelay: Değişiklikler en fazla bu gecikmeyle diske yazılır (Her beyin çağrısında bütün defter yeniden yazılmasın)
+const saveDelay = 2 * time.Second
+
+// Totals: Belirli bir kapsamdaki (oturum, sohbet, gün) birikmiş kullanım
+type Totals struct {
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	Requests         int       `json:"requests"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (t *Totals) add(prompt, completion, total int, cost float64) {
+	t.PromptTokens += prompt
+	t.CompletionTokens += completion
+	t.TotalTokens += total
+	t.CostUSD += cost
+	t.Requests++
+	t.UpdatedAt = time.Now()
+}
+
+// String: İnsan okunabilir tek satırlık özet
+func (t Totals) String() string {
+	return fmt.Sprintf("%d token (Girdi: %d, Çıktı: %d) | %d istek | $%.4f",
+		t.TotalTokens, t.PromptTokens, t.CompletionTokens, t.Requests, t.CostUSD)
+}
+
+// ledger: Diskte saklanan muhasebe defteri
+type ledger struct {
+	Sessions      map[string]*Totals `json:"sessions"`
+	Conversations map[string]*Totals `json:"conversations"`
+	Days          map[string]*Totals `json:"days"` // "2006-01-02"
+	Models        map[string]*Totals `json:"models"`
+}
+
+// Budget: Oturum ve gün başına sınırlar (0 = sınırsız)
+type Budget struct {
+	SessionTokens int
+	DailyTokens   int
+	SessionUSD    float64
+	DailyUSD      float64
+}
+
+// Meter: Token kullanımını biriktiren, maliyeti hesaplayan ve bütçeyi denetleyen sayaç
+type Meter struct {
+	FilePath string
+	Prices   map[string]config.TokenPrice
+	Budget   Budget
+
+	data      ledger
+	dirty     bool        // Diske yazılmamış değişiklik var
+	saveTimer *time.Timer // Bekleyen gecikmeli yazma
+	mu        sync.Mutex
+}
+
+// NewMeter: Config'deki fiyat tablosu ve bütçelerle sayacı kurar, eski defteri yükler.
+func NewMeter(cfg *config.Config) *Meter {
+	path := cfg.Usage.LedgerPath
+	if path == "" {
+		path = filepath.Join("logs", "usage_ledger.json")
+	}
+
+	m := &Meter{
+		FilePath: path,
+		Prices:   cfg.Usage.Prices,
+		Budget: Budget{
+			SessionTokens: cfg.Usage.SessionBudgetTokens,
+			DailyTokens:   cfg.Usage.DailyBudgetTokens,
+			SessionUSD:    cfg.Usage.SessionBudgetUSD,
+			DailyUSD:      cfg.Usage.DailyBudgetUSD,
+		},
+		data: ledger{
+			Sessions:      make(map[string]*Totals),
+			Conversations: make(map[string]*Totals),
+			Days:          make(map[string]*Totals),
+			Models:        make(map[string]*Totals),
+		},
+	}
+	m.load()
+	return m
+}
+
+// Cost: Modelin fiyat tablosuna göre maliyeti hesaplar. Model yoksa "default" fiyatı kullanılır.
+func (m *Meter) Cost(model string, prompt, completion int) float64 {
+	price, ok := m.Prices[model]
+	if !ok {
+		price = m.Prices["default"]
+	}
+	return (float64(prompt)*price.Prompt + float64(completion)*price.Completion) / 1_000_000
+}
+
+// Record: Tek bir beyin çağrısının kullanımını oturum, sohbet, gün ve model toplamlarına işler.
+func (m *Meter) Record(sessionID, conversationID, model string, u map[string]int) {
+	if u == nil {
+		return
+	}
+	prompt := u[kernel.UsagePromptTokens]
+	completion := u[kernel.UsageCompletionTokens]
+	total := u[kernel.UsageTotalTokens]
+	if total == 0 {
+		total = prompt + completion
+	}
+	cost := m.Cost(model, prompt, completion)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bucket(m.data.Sessions, sessionID).add(prompt, completion, total, cost)
+	bucket(m.data.Conversations, conversationID).add(prompt, completion, total, cost)
+	bucket(m.data.Days, today()).add(prompt, completion, total, cost)
+	if model != "" {
+		bucket(m.data.Models, model).add(prompt, completion, total, cost)
+	}
+
+	m.dirty = true
+	if m.saveTimer == nil {
+		m.saveTimer = time.AfterFunc(saveDelay, m.Flush)
+	}
+}
+
+// Flush: Bekleyen değişiklikleri hemen diske yazar (Kapanışta çağrılmalıdır).
+func (m *Meter) Flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveTimer != nil {
+		m.saveTimer.Stop()
+		m.saveTimer = nil
+	}
+	if !m.dirty {
+		return
+	}
+	// Yazılamazsa değişiklikler kirli kalır; sonraki kayıt veya kapanış tekrar dener
+	if err := m.save(); err != nil {
+		logger.Warn("⚠️ Kullanım defteri yazılamadı: %v", err)
+		return
+	}
+	m.dirty = false
+}
+
+// CheckBudget: Oturum veya günlük bütçe aşıldıysa açıklayıcı bir hata döner.
+func (m *Meter) CheckBudget(sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess := m.data.Sessions[sessionID]
+	day := m.data.Days[today()]
+
+	if sess != nil {
+		if m.Budget.SessionTokens > 0 && sess.TotalTokens >= m.Budget.SessionTokens {
+			return fmt.Errorf("oturum token bütçesi aşıldı (%d / %d token)", sess.TotalTokens, m.Budget.SessionTokens)
+		}
+		if m.Budget.SessionUSD > 0 && sess.CostUSD >= m.Budget.SessionUSD {
+			return fmt.Errorf("oturum maliyet bütçesi aşıldı ($%.4f / $%.2f)", sess.CostUSD, m.Budget.SessionUSD)
+		}
+	}
+	if day != nil {
+		if m.Budget.DailyTokens > 0 && day.TotalTokens >= m.Budget.DailyTokens {
+			return fmt.Errorf("günlük token bütçesi aşıldı (%d / %d token)", day.TotalTokens, m.Budget.DailyTokens)
+		}
+		if m.Budget.DailyUSD > 0 && day.CostUSD >= m.Budget.DailyUSD {
+			return fmt.Errorf("günlük maliyet bütçesi aşıldı ($%.4f / $%.2f)", day.CostUSD, m.Budget.DailyUSD)
+		}
+	}
+	return nil
+}
+
+// Session, Conversation, Day: Kapsam toplamlarının kopyasını döner.
+func (m *Meter) Session(id string) Totals      { return m.get(m.data.Sessions, id) }
+func (m *Meter) Conversation(id string) Totals { return m.get(m.data.Conversations, id) }
+func (m *Meter) Day(date string) Totals        { return m.get(m.data.Days, date) }
+
+// Report: rick_control için okunabilir kullanım raporu hazırlar.
+func (m *Meter) Report(sessionID, conversationID string) string {
+	var sb strings.Builder
+	sb.WriteString("📊 TOKEN KULLANIM RAPORU:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	if sessionID != "" {
+		sb.WriteString(fmt.Sprintf("🔹 Oturum [%s]: %s\n", sessionID, m.Session(sessionID)))
+	}
+	if conversationID != "" {
+		sb.WriteString(fmt.Sprintf("🔹 Sohbet [%s]: %s\n", conversationID, m.Conversation(conversationID)))
+	}
+	sb.WriteString(fmt.Sprintf("🔹 Bugün [%s]: %s\n", today(), m.Day(today())))
+
+	m.mu.Lock()
+	models := make([]string, 0, len(m.data.Models))
+	for name := range m.data.Models {
+		models = append(models, name)
+	}
+	sort.Strings(models)
+	if len(models) > 0 {
+		sb.WriteString("🔹 Modeller (Tüm Zamanlar):\n")
+		for _, name := range models {
+			sb.WriteString(fmt.Sprintf("   - %s: %s\n", name, *m.data.Models[name]))
+		}
+	}
+	m.mu.Unlock()
+
+	b := m.Budget
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	sb.WriteString(fmt.Sprintf("💰 Bütçe: Oturum %s / %s | Gün %s / %s",
+		limitStr(b.SessionTokens), limitUSD(b.SessionUSD), limitStr(b.DailyTokens), limitUSD(b.DailyUSD)))
+	return sb.String()
+}
+
+func (m *Meter) get(set map[string]*Totals, id string) Totals {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := set[id]; ok {
+		return *t
+	}
+	return Totals{}
+}
+
+func bucket(set map[string]*Totals, id string) *Totals {
+	t, ok := set[id]
+	if !ok {
+		t = &Totals{}
+		set[id] = t
+	}
+	return t
+}
+
+func today() string { return time.Now().Format("2006-01-02") }
+
+func limitStr(v int) string {
+	if v <= 0 {
+		return "∞ token"
+	}
+	return fmt.Sprintf("%d token", v)
+}
+
+func limitUSD(v float64) string {
+	if v <= 0 {
+		return "∞ $"
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
+// -- Persistence (Disk İşlemleri) --
+
+// save: Kilit altında çağrılır. Eski oturumları budar ve defteri atomik olarak yazar.
+func (m *Meter) save() error {
+	cutoff := time.Now().Add(-sessionRetention)
+	for id, t := range m.data.Sessions {
+		if t.UpdatedAt.Before(cutoff) {
+			delete(m.data.Sessions, id)
+		}
+	}
+
+	data, err := json.MarshalIndent(m.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(m.FilePath), 0755)
+
+	tempPath := m.FilePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, m.FilePath)
+}
+
+func (m *Meter) load() {
+	data, err := os.ReadFile(m.FilePath)
+	if err != nil {
+		return
+	}
+	var stored ledger
+	if err := json.Unmarshal(data, &stored); err != nil {
+		logger.Warn("⚠️ Kullanım defteri okunamadı: %v", err)
+		return
+	}
+	for id, t := range stored.Sessions {
+		m.data.Sessions[id] = t
+	}
+	for id, t := range stored.Conversations {
+		m.data.Conversations[id] = t
+	}
+	for id, t := range stored.Days {
+		m.data.Days[id] = t
+	}
+	for id, t := range stored.Models {
+		m.data.Models[id] = t
+	}
+}
@@ -0,0 +1,40 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aydndglr/rick-agent-v3/internal/core/config"
+	"github.com/aydndglr/rick-agent-v3/internal/core/kernel"
+)
+
+func TestFlushKeepsDirtyUntilSaved(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "logs")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(blocker, "usage_ledger.json")
+
+	cfg := &config.Config{}
+	cfg.Usage.LedgerPath = path
+	m := NewMeter(cfg)
+	m.Record("TSK-1", "sohbet", "qwen3:8b", map[string]int{kernel.UsagePromptTokens: 10, kernel.UsageCompletionTokens: 5})
+	m.Flush()
+	if !m.dirty {
+		t.Fatal("yazma başarısızken değişiklikler kirli kalmalıydı")
+	}
+
+	// Engel kalkınca sonraki Flush bekleyen kaydı yazar
+	if err := os.Remove(blocker); err != nil {
+		t.Fatal(err)
+	}
+	m.Flush()
+	if m.dirty {
+		t.Fatal("başarılı yazmadan sonra defter temiz olmalıydı")
+	}
+	if got := NewMeter(cfg).Session("TSK-1"); got.TotalTokens != 15 {
+		t.Fatalf("diskteki oturum toplamı %d, beklenen 15", got.TotalTokens)
+	}
+}